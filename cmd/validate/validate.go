@@ -2,20 +2,56 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
+	"maps"
 	"os"
+	"slices"
 
 	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
 )
 
 func main() {
 	// This is a cli that takes a path to a teams config file and validates it
 	// Usage: go run validate.go <path-to-config-file>
 	path := os.Args[1]
-	_, _, err := github.ParseTeamConfig(path)
+	teams, personalDigestUsers, personalNotifications, err := github.ParseTeamConfig(path)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 
 	fmt.Println("Team config is valid")
+
+	for _, name := range slices.Sorted(maps.Keys(teams)) {
+		if silence := teams[name].Config.SilenceDependabot; !silence.IsKnown() {
+			fmt.Printf("team %s: config.silenceDependabot %q is ignored, use always or rollup\n", name, silence)
+		}
+	}
+
+	// Checking the channels needs the Slack tokens, so it is optional
+	if os.Getenv(slack.TokenEnv("")) == "" {
+		fmt.Printf("Set %s to check which channels are public\n", slack.TokenEnv(""))
+		return
+	}
+
+	log := slog.New(slog.DiscardHandler)
+	workspaces, err := slack.NewWorkspaces(log, teams, personalDigestUsers, personalNotifications)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	publicChannels, err := workspaces.PublicChannels(teams)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(publicChannels)) {
+		policy := teams[name].Config.PrivateContent
+		for _, channel := range publicChannels[name] {
+			fmt.Printf("team %s: %s is public, events from private and internal repositories %s\n", name, channel, policy.Describe())
+		}
+	}
 }
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// requireAdmin only lets through requests with the admin token as bearer token.
+// If GHEP_ADMIN_TOKEN is not set, all admin endpoints are disabled.
+func (c *Client) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if c.adminToken == "" {
+			http.Error(w, "admin endpoints are disabled", http.StatusNotFound)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(c.adminToken)) != 1 {
+			c.log.Warn("Invalid admin token", "path", r.URL.Path)
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (c *Client) resyncPostHandler(w http.ResponseWriter, r *http.Request) {
+	if c.resyncer == nil {
+		http.Error(w, "resync is not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Only the leader resyncs, so two pods never resync at once
+	if !c.resyncer.Leading() {
+		http.Error(w, "resync runs on the leader, try again", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !c.resyncer.Trigger() {
+		http.Error(w, "resync already running", http.StatusConflict)
+		return
+	}
+
+	c.log.Info("Resync triggered from admin endpoint")
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprint(w, "Resync started\n")
+}
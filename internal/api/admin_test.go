@@ -0,0 +1,86 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeResyncer struct {
+	follower  bool
+	triggered int
+}
+
+func (f *fakeResyncer) Leading() bool {
+	return !f.follower
+}
+
+func (f *fakeResyncer) Trigger() bool {
+	f.triggered++
+	return f.triggered == 1
+}
+
+func TestResyncPostHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		adminToken string
+		header     string
+		want       int
+	}{
+		{name: "admin endpoints disabled", adminToken: "", header: "Bearer secret", want: http.StatusNotFound},
+		{name: "missing token", adminToken: "secret", header: "", want: http.StatusUnauthorized},
+		{name: "wrong token", adminToken: "secret", header: "Bearer wrong", want: http.StatusUnauthorized},
+		{name: "valid token", adminToken: "secret", header: "Bearer secret", want: http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{log: slog.Default(), resyncer: &fakeResyncer{}, adminToken: tt.adminToken}
+
+			req := httptest.NewRequest(http.MethodPost, "/internal/resync", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			c.requireAdmin(c.resyncPostHandler)(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	t.Run("already running", func(t *testing.T) {
+		c := &Client{log: slog.Default(), resyncer: &fakeResyncer{triggered: 1}, adminToken: "secret"}
+
+		req := httptest.NewRequest(http.MethodPost, "/internal/resync", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+
+		c.requireAdmin(c.resyncPostHandler)(rec, req)
+
+		if rec.Code != http.StatusConflict {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusConflict)
+		}
+	})
+
+	t.Run("not the leader", func(t *testing.T) {
+		resyncer := &fakeResyncer{follower: true}
+		c := &Client{log: slog.Default(), resyncer: resyncer, adminToken: "secret"}
+
+		req := httptest.NewRequest(http.MethodPost, "/internal/resync", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+
+		c.requireAdmin(c.resyncPostHandler)(rec, req)
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("got status %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+		if resyncer.triggered != 0 {
+			t.Error("expected no resync to be triggered")
+		}
+	})
+}
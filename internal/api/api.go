@@ -18,37 +18,94 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/navikt/ghep/internal/events"
 	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
 	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
+// shutdownTimeout is how long requests in progress get to finish when the server shuts down.
+const shutdownTimeout = 10 * time.Second
+
+// Resyncer triggers an on-demand resync of Github and Slack data. Resyncs only
+// run on the leader.
+type Resyncer interface {
+	Leading() bool
+	Trigger() bool
+}
+
+// DigestSender sends a team's digest on demand.
+type DigestSender interface {
+	SendPullRequestDigest(ctx context.Context, teamSlug string) error
+}
+
 type Client struct {
 	log           *slog.Logger
 	db            *gensql.Queries
 	events        events.Handler
 	teamConfig    map[string]github.Team
 	webhookSecret string
+	resyncer      Resyncer
+	digests       DigestSender
+	personal      *events.PersonalNotifier
+	adminToken    string
+	github        github.Clients
+
+	slackWorkspaces slack.Workspaces
 
 	ExternalContributorsChannel string
 	SubscribeToOrg              bool
 }
 
-func New(log *slog.Logger, db *gensql.Queries, events events.Handler, teamConfig map[string]github.Team, webhookSecret, externalContributorsChannel string, subscribeToOrg bool) Client {
+// Options are the dependencies and settings of the API, passed to New.
+type Options struct {
+	DB              *gensql.Queries
+	Events          events.Handler
+	TeamConfig      map[string]github.Team
+	GithubClients   github.Clients
+	SlackWorkspaces slack.Workspaces
+	Resyncer        Resyncer
+	Digests         DigestSender
+	Personal        *events.PersonalNotifier
+
+	WebhookSecret               string
+	AdminToken                  string
+	ExternalContributorsChannel string
+	SubscribeToOrg              bool
+}
+
+func New(log *slog.Logger, opts Options) Client {
 	return Client{
 		log:           log,
-		db:            db,
-		events:        events,
-		teamConfig:    teamConfig,
-		webhookSecret: webhookSecret,
-
-		ExternalContributorsChannel: externalContributorsChannel,
-		SubscribeToOrg:              subscribeToOrg,
+		db:            opts.DB,
+		events:        opts.Events,
+		teamConfig:    opts.TeamConfig,
+		webhookSecret: opts.WebhookSecret,
+		resyncer:      opts.Resyncer,
+		digests:       opts.Digests,
+		personal:      opts.Personal,
+		adminToken:    opts.AdminToken,
+		github:        opts.GithubClients,
+
+		slackWorkspaces: opts.SlackWorkspaces,
+
+		ExternalContributorsChannel: opts.ExternalContributorsChannel,
+		SubscribeToOrg:              opts.SubscribeToOrg,
 	}
 }
 
-func (c *Client) Run(base, addr string) error {
+// Run serves the API until ctx is done, and then shuts the server down,
+// waiting for requests in progress.
+func (c *Client) Run(ctx context.Context, base, addr string) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc(fmt.Sprintf("POST %s/events", base), c.eventsPostHandler)
+	mux.HandleFunc(fmt.Sprintf("GET %s/feeds/{path...}", base), c.feedGetHandler)
+	mux.HandleFunc(fmt.Sprintf("POST %s/slack/commands", base), c.slackCommandsPostHandler)
+	mux.HandleFunc(fmt.Sprintf("POST %s/slack/interactions", base), c.slackInteractionsPostHandler)
 	mux.HandleFunc("GET /internal/health", c.healthGetHandler)
+	mux.HandleFunc("POST /internal/resync", c.requireAdmin(c.resyncPostHandler))
+	mux.HandleFunc("GET /internal/feeds", c.requireAdmin(c.feedTokensGetHandler))
+	mux.HandleFunc("GET /internal/mutes", c.requireAdmin(c.mutesGetHandler))
+	mux.HandleFunc("POST /internal/mutes", c.requireAdmin(c.mutesPostHandler))
+	mux.HandleFunc("DELETE /internal/mutes/{id}", c.requireAdmin(c.muteDeleteHandler))
 	mux.HandleFunc("GET /internal/", c.frontendGetHandler)
 
 	srv := &http.Server{
@@ -59,7 +116,22 @@ func (c *Client) Run(base, addr string) error {
 		IdleTimeout:  120 * time.Second,
 	}
 
-	return srv.ListenAndServe()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	c.log.Info("Shutting down API server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return srv.Shutdown(shutdownCtx)
 }
 
 func (c *Client) eventsPostHandler(w http.ResponseWriter, r *http.Request) {
@@ -87,6 +159,14 @@ func (c *Client) eventsPostHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("error creating event: %s", err.Error()), http.StatusInternalServerError)
 		return
 	}
+	event.DeliveryID = deliveryID
+
+	githubClient, ok := c.github.ForEvent(event)
+	if !ok {
+		fmt.Fprint(w, "Event is from an organization not using Ghep\n")
+		return
+	}
+	log = log.With("org", githubClient.Org())
 
 	if slices.Contains([]string{"member_added", "member_removed"}, event.Action) {
 		log.Info("Handling org event", "action", event.Action, "user", event.Membership.User.Login, "triggered_by", event.Sender.Login)
@@ -144,29 +224,36 @@ func (c *Client) eventsPostHandler(w http.ResponseWriter, r *http.Request) {
 	var teams []string
 	if c.SubscribeToOrg {
 		if event.Team != nil {
-			if _, ok := c.teamConfig[event.Team.Name]; !ok {
-				fmt.Fprintf(w, "Event has team, but org subscription is enabled, ignoring events for %s\n", event.Team.Name)
+			name := githubClient.TeamName(event.Team.Name)
+			if _, ok := c.teamConfig[name]; !ok {
+				fmt.Fprintf(w, "Event has team, but org subscription is enabled, ignoring events for %s\n", html.EscapeString(name))
 				return
 			}
 
-			teams = append(teams, event.Team.Name)
+			teams = append(teams, name)
 		} else {
-			for name := range c.teamConfig {
-				teams = append(teams, name)
-				break
+			for name, team := range c.teamConfig {
+				if client, ok := c.github.ForTeam(team); ok && client.Org() == githubClient.Org() {
+					teams = append(teams, name)
+					break
+				}
 			}
 		}
 	} else {
 		if event.Team != nil {
-			team, err := c.db.GetTeam(r.Context(), event.Team.Name)
+			name := event.Team.Name
+			team, err := c.db.GetTeamByGithubSlug(r.Context(), gensql.GetTeamByGithubSlugParams{
+				Org:        github.OrgKey(githubClient.Org()),
+				GithubSlug: name,
+			})
 			if err != nil {
 				if !errors.Is(err, pgx.ErrNoRows) {
-					log.Error("Getting team from database", "team", event.Team.Name, "error", err)
+					log.Error("Getting team from database", "team", name, "error", err)
 					http.Error(w, fmt.Sprintf("Error getting team from database: %s", err.Error()), http.StatusInternalServerError)
 					return
 				}
 
-				fmt.Fprintf(w, "%s is not using Ghep\n", event.Team.Name)
+				fmt.Fprintf(w, "%s is not using Ghep\n", html.EscapeString(name))
 				return
 			}
 
@@ -175,9 +262,13 @@ func (c *Client) eventsPostHandler(w http.ResponseWriter, r *http.Request) {
 				teams = append(teams, team)
 			}
 		} else {
-			teamsFromDB, err := c.db.ListTeamsByRepository(r.Context(), event.GetRepositoryName())
+			repository := event.GetRepositoryName()
+			teamsFromDB, err := c.db.ListTeamsByRepository(r.Context(), gensql.ListTeamsByRepositoryParams{
+				Org:  github.OrgKey(githubClient.Org()),
+				Name: repository,
+			})
 			if err != nil {
-				log.Error("Listing teams by repository", "repository", event.GetRepositoryName(), "error", err)
+				log.Error("Listing teams by repository", "repository", repository, "error", err)
 				http.Error(w, fmt.Sprintf("Error listing teams by repository: %s", err.Error()), http.StatusInternalServerError)
 				return
 			}
@@ -196,6 +287,16 @@ func (c *Client) eventsPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Only events routed to a team using Ghep are sent as DMs
+	if c.personal != nil {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			c.personal.Notify(ctx, log, deliveryID, event)
+		}()
+	}
+
 	for _, name := range teams {
 		log = log.With("repository", event.GetRepositoryName(), "team", name, "action", event.Action)
 		if err := c.events.Handle(r.Context(), log, c.teamConfig[name], event); err != nil {
@@ -5,7 +5,6 @@ import (
 	"log/slog"
 	"testing"
 
-	"github.com/navikt/ghep/internal/events"
 	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/sql/gensql"
 	"github.com/pashagolub/pgxmock/v4"
@@ -26,7 +25,7 @@ func TestIsAnExternalContributorEvent(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
 
 	db := gensql.New(mock)
-	apiClient := New(slog.Default(), db, events.Handler{}, map[string]github.Team{}, "test-secret", "externalChannel", false)
+	apiClient := New(slog.Default(), Options{DB: db, TeamConfig: map[string]github.Team{}, WebhookSecret: "test-secret", ExternalContributorsChannel: "externalChannel"})
 
 	event := github.Event{
 		Sender: github.User{
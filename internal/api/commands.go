@@ -0,0 +1,314 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+const commandUsage = "Usage:\n" +
+	"• `/ghep status` shows the sources, channels, repositories and mutes of the team posting to this channel\n" +
+	"• `/ghep mute <repository or source> <duration> [reason]` stops posting events from a repository, a source in one repository like `ghep/pulls`, or a source in all repositories like `*/pulls`, for example for `2d` or `3h`\n" +
+	"• `/ghep unmute <repository or source>` posts the muted events again\n" +
+	"• `/ghep digest pr now` sends the pull request digest right away\n" +
+	"• `/ghep notifications` shows or changes your DMs about your own pull requests, commits and mentions\n" +
+	"• `/ghep whoami` shows which Github user you are linked to"
+
+// slashCommand is a slash command sent from Slack.
+type slashCommand struct {
+	Workspace   string
+	UserID      string
+	ChannelID   string
+	ChannelName string
+	Text        string
+}
+
+type commandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// slackCommandsPostHandler handles /ghep slash commands. The reply is only
+// shown to the user running the command.
+func (c *Client) slackCommandsPostHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxInteractionSize))
+	if err != nil {
+		c.log.Error("Reading body", "error", err)
+		http.Error(w, "error reading body", http.StatusInternalServerError)
+		return
+	}
+	defer r.Body.Close()
+
+	workspace, err := c.slackWorkspaces.VerifyInteraction(r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"), body, time.Now())
+	if err != nil {
+		c.log.Warn("Invalid Slack signature", "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	command := slashCommand{
+		Workspace:   workspace,
+		UserID:      form.Get("user_id"),
+		ChannelID:   form.Get("channel_id"),
+		ChannelName: form.Get("channel_name"),
+		Text:        strings.TrimSpace(form.Get("text")),
+	}
+
+	log := c.log.With("workspace", workspace, "slack_user", command.UserID, "channel", command.ChannelID, "command", command.Text)
+	reply := c.runCommand(r.Context(), log, command)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(commandResponse{ResponseType: "ephemeral", Text: reply}); err != nil {
+		log.Error("Writing command response", "error", err)
+	}
+}
+
+// runCommand runs the slash command, and returns the reply to the user.
+func (c *Client) runCommand(ctx context.Context, log *slog.Logger, command slashCommand) string {
+	args := strings.Fields(command.Text)
+	if len(args) == 0 || args[0] == "help" {
+		return commandUsage
+	}
+
+	if args[0] == "whoami" {
+		return c.whoamiCommand(ctx, command)
+	}
+
+	if args[0] == "notifications" {
+		reply, err := c.notificationsCommand(ctx, command, args[1:])
+		if err != nil {
+			log.Info("Slack command failed", "error", err)
+			return fmt.Sprintf("Sorry, that did not work: %s", err)
+		}
+
+		return reply
+	}
+
+	teams := c.teamsForChannel(command.Workspace, command.ChannelID, command.ChannelName)
+	if len(teams) == 0 {
+		return "No team posts to this channel, run the command in one of your team's channels."
+	}
+
+	if args[0] == "status" {
+		var replies []string
+		for _, team := range teams {
+			replies = append(replies, c.statusCommand(ctx, team))
+		}
+
+		return strings.Join(replies, "\n\n")
+	}
+
+	if len(teams) > 1 {
+		names := make([]string, 0, len(teams))
+		for _, team := range teams {
+			names = append(names, team.Name)
+		}
+
+		return fmt.Sprintf("Several teams post to this channel (%s), run the command in a channel only your team uses.", strings.Join(names, ", "))
+	}
+
+	team := teams[0]
+	login, err := c.teamMemberLogin(ctx, command.Workspace, command.UserID, team)
+	if err != nil {
+		return fmt.Sprintf("Sorry, that did not work: %s", err)
+	}
+
+	log = log.With("team", team.Name, "login", login)
+
+	var reply string
+	switch args[0] {
+	case "mute":
+		reply, err = c.muteCommand(ctx, team, login, args[1:])
+	case "unmute":
+		reply, err = c.unmuteCommand(ctx, team, args[1:])
+	case "digest":
+		reply, err = c.digestCommand(log, team, args[1:])
+	default:
+		return commandUsage
+	}
+	if err != nil {
+		log.Info("Slack command failed", "error", err)
+		return fmt.Sprintf("Sorry, that did not work: %s", err)
+	}
+
+	log.Info("Ran Slack command")
+	return reply
+}
+
+// teamsForChannel returns the teams in the workspace with a source or digest
+// posting to the channel, either by ID or by name.
+func (c *Client) teamsForChannel(workspace, channelID, channelName string) []github.Team {
+	matches := func(channel string) bool {
+		return channel != "" && (channel == channelID || strings.TrimPrefix(channel, "#") == channelName)
+	}
+
+	var teams []github.Team
+	for _, team := range c.teamConfig {
+		if team.SlackWorkspace != workspace {
+			continue
+		}
+
+		channels := make([]string, 0, len(team.Sources)+3)
+		for _, source := range team.Sources {
+			channels = append(channels, source.Channel)
+		}
+		if team.PullRequestDigest != nil {
+			channels = append(channels, team.PullRequestDigest.Channel)
+		}
+		if team.SecurityDigest != nil {
+			channels = append(channels, team.SecurityDigest.Channel)
+		}
+		if team.DependencyRollup != nil {
+			channels = append(channels, team.DependencyRollup.Channel)
+		}
+
+		if slices.ContainsFunc(channels, matches) {
+			teams = append(teams, team)
+		}
+	}
+
+	slices.SortFunc(teams, func(a, b github.Team) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return teams
+}
+
+func (c *Client) statusCommand(ctx context.Context, team github.Team) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%s*\n", team.Name)
+
+	for _, source := range team.Sources {
+		fmt.Fprintf(&sb, "• `%s` → %s\n", source.SourceType, source.Channel)
+	}
+	if team.PullRequestDigest != nil {
+		fmt.Fprintf(&sb, "• Pull request digest → %s, %s at %s\n", team.PullRequestDigest.Channel, team.PullRequestDigest.Day, team.PullRequestDigest.Time)
+	}
+	if team.SecurityDigest != nil {
+		fmt.Fprintf(&sb, "• Security digest → %s, %s at %s\n", team.SecurityDigest.Channel, team.SecurityDigest.Day, team.SecurityDigest.Time)
+	}
+	if team.DependencyRollup != nil {
+		fmt.Fprintf(&sb, "• Dependency roll-up → %s, daily at %s\n", team.DependencyRollup.Channel, team.DependencyRollup.Time)
+	}
+
+	repositories, err := c.db.ListTeamRepositories(ctx, team.Name)
+	if err != nil {
+		c.log.Error("Listing team repositories", "team", team.Name, "error", err)
+		fmt.Fprintf(&sb, "Could not list repositories: %s\n", err)
+	} else {
+		names := make([]string, 0, len(repositories))
+		for _, repository := range repositories {
+			names = append(names, repository.Name)
+		}
+		fmt.Fprintf(&sb, "Repositories (%d): %s\n", len(names), strings.Join(names, ", "))
+	}
+
+	if len(team.Config.IgnoreRepositories) > 0 {
+		fmt.Fprintf(&sb, "Ignored in teams.yaml: %s\n", strings.Join(team.Config.IgnoreRepositories, ", "))
+	}
+
+	mutes, err := c.db.ListActiveMutes(ctx, team.Name)
+	if err != nil {
+		c.log.Error("Listing mutes", "team", team.Name, "error", err)
+		fmt.Fprintf(&sb, "Could not list mutes: %s\n", err)
+	}
+	for _, mute := range mutes {
+		fmt.Fprintf(&sb, "Muted: %s until %s, by %s", slack.MuteTarget(mute), slackDate(mute.MutedUntil.Time), mute.CreatedBy)
+		if mute.Reason != "" {
+			fmt.Fprintf(&sb, " (%s)", mute.Reason)
+		}
+		fmt.Fprintf(&sb, ", %d suppressed so far\n", mute.Suppressed)
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (c *Client) muteCommand(ctx context.Context, team github.Team, login string, args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("usage is `/ghep mute <repository or source> <duration> [reason]`")
+	}
+
+	duration, err := parseMuteDuration(args[1])
+	if err != nil {
+		return "", err
+	}
+
+	repository, sourceType := parseMuteTarget(args[0])
+	mute, err := c.createMute(ctx, team, repository, sourceType, duration, strings.Join(args[2:], " "), login)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Muted %s for %s until %s", slack.MuteTarget(mute), team.Name, slackDate(mute.MutedUntil.Time)), nil
+}
+
+func (c *Client) unmuteCommand(ctx context.Context, team github.Team, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage is `/ghep unmute <repository or source>`")
+	}
+
+	repository, sourceType := parseMuteTarget(args[0])
+	if err := c.db.EndMutes(ctx, gensql.EndMutesParams{
+		TeamSlug:   team.Name,
+		Repository: repository,
+		SourceType: sourceType,
+	}); err != nil {
+		return "", fmt.Errorf("unmuting: %w", err)
+	}
+
+	return fmt.Sprintf("Unmuted %s for %s", slack.MuteTarget(gensql.Mute{Repository: repository, SourceType: sourceType}), team.Name), nil
+}
+
+// digestCommand sends the digest in the background, as it can take longer
+// than Slack waits for a reply.
+func (c *Client) digestCommand(log *slog.Logger, team github.Team, args []string) (string, error) {
+	if !slices.Equal(args, []string{"pr", "now"}) {
+		return "", fmt.Errorf("usage is `/ghep digest pr now`")
+	}
+
+	if team.PullRequestDigest == nil {
+		return "", fmt.Errorf("%s has no pull request digest configured", team.Name)
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		if err := c.digests.SendPullRequestDigest(ctx, team.Name); err != nil {
+			log.Error("Sending digest on demand", "error", err)
+		}
+	}()
+
+	return fmt.Sprintf("Sending the pull request digest to %s", team.PullRequestDigest.Channel), nil
+}
+
+func (c *Client) whoamiCommand(ctx context.Context, command slashCommand) string {
+	login, err := c.githubLogin(ctx, command.Workspace, command.UserID)
+	if err != nil {
+		return fmt.Sprintf("Sorry, that did not work: %s", err)
+	}
+
+	return fmt.Sprintf("<@%s> is `%s` on Github", command.UserID, login)
+}
+
+// slackDate formats the time in each reader's own timezone.
+func slackDate(t time.Time) string {
+	return fmt.Sprintf("<!date^%d^{date_short_pretty} {time}|%s>", t.Unix(), t.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,325 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/events"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/sql/gensql"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestTeamsForChannel(t *testing.T) {
+	teamConfig := map[string]github.Team{
+		"nada": {
+			Name:    "nada",
+			Sources: []github.Source{{SourceType: "pulls", Channel: "#nada-github"}},
+		},
+		"aura": {
+			Name:              "aura",
+			Sources:           []github.Source{{SourceType: "commits", Channel: "C0123"}},
+			PullRequestDigest: &github.DigestConfig{Channel: "#nada-github"},
+		},
+		"other": {
+			Name:           "other",
+			SlackWorkspace: "other",
+			Sources:        []github.Source{{SourceType: "pulls", Channel: "#nada-github"}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		channelID   string
+		channelName string
+		expected    []string
+	}{
+		{
+			name:        "channel by name",
+			channelID:   "C0456",
+			channelName: "nada-github",
+			expected:    []string{"aura", "nada"},
+		},
+		{
+			name:        "channel by ID",
+			channelID:   "C0123",
+			channelName: "aura-commits",
+			expected:    []string{"aura"},
+		},
+		{
+			name:        "unknown channel",
+			channelID:   "C0789",
+			channelName: "random",
+		},
+	}
+
+	apiClient := New(slog.New(slog.DiscardHandler), Options{TeamConfig: teamConfig})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, team := range apiClient.teamsForChannel("", tt.channelID, tt.channelName) {
+				got = append(got, team.Name)
+			}
+
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("teamsForChannel() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	member := func(mock pgxmock.PgxPoolIface) {
+		mock.ExpectQuery("FROM slack_ids").
+			WithArgs("", "U123").
+			WillReturnRows(pgxmock.NewRows([]string{"login"}).AddRow("Kyrremann"))
+		mock.ExpectQuery("FROM team_members").
+			WithArgs("nada", "Kyrremann").
+			WillReturnRows(pgxmock.NewRows([]string{"user_login"}).AddRow("Kyrremann"))
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		channel string
+		expect  func(mock pgxmock.PgxPoolIface)
+		reply   string
+	}{
+		{
+			name:    "help",
+			text:    "",
+			channel: "nada-github",
+			reply:   "Usage:",
+		},
+		{
+			name:    "channel without team",
+			text:    "status",
+			channel: "random",
+			reply:   "No team posts to this channel",
+		},
+		{
+			name:    "whoami",
+			text:    "whoami",
+			channel: "random",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("FROM slack_ids").
+					WithArgs("", "U123").
+					WillReturnRows(pgxmock.NewRows([]string{"login"}).AddRow("Kyrremann"))
+			},
+			reply: "<@U123> is `Kyrremann` on Github",
+		},
+		{
+			name:    "mute repository",
+			text:    "mute ghep 2d migrating to Kafka",
+			channel: "nada-github",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				member(mock)
+				mock.ExpectQuery("FROM team_repositories").
+					WithArgs("nada").
+					WillReturnRows(pgxmock.NewRows([]string{"id", "name", "org"}).AddRow(int32(1), "ghep", "navikt"))
+				mock.ExpectQuery("INSERT INTO mutes").
+					WithArgs("nada", "ghep", "", pgxmock.AnyArg(), "migrating to Kafka", "Kyrremann").
+					WillReturnRows(pgxmock.NewRows(muteColumns).
+						AddRow(int64(1), "nada", "ghep", pgtype.Timestamptz{Time: time.Now(), Valid: true}, "Kyrremann", pgtype.Timestamptz{}, "", "migrating to Kafka", int32(0), pgtype.Timestamptz{}))
+			},
+			reply: "Muted `ghep` for nada until",
+		},
+		{
+			name:    "mute source",
+			text:    "mute */workflows 3h",
+			channel: "nada-github",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				member(mock)
+				mock.ExpectQuery("INSERT INTO mutes").
+					WithArgs("nada", "", "workflows", pgxmock.AnyArg(), "", "Kyrremann").
+					WillReturnRows(pgxmock.NewRows(muteColumns).
+						AddRow(int64(1), "nada", "", pgtype.Timestamptz{Time: time.Now(), Valid: true}, "Kyrremann", pgtype.Timestamptz{}, "workflows", "", int32(0), pgtype.Timestamptz{}))
+			},
+			reply: "Muted `workflows` events for nada until",
+		},
+		{
+			name:    "mute unknown source",
+			text:    "mute ghep/deployments 3h",
+			channel: "nada-github",
+			expect:  member,
+			reply:   "invalid source \"deployments\"",
+		},
+		{
+			name:    "mute repository of another team",
+			text:    "mute other 2d",
+			channel: "nada-github",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				member(mock)
+				mock.ExpectQuery("FROM team_repositories").
+					WithArgs("nada").
+					WillReturnRows(pgxmock.NewRows([]string{"id", "name", "org"}).AddRow(int32(1), "ghep", "navikt"))
+			},
+			reply: "nada does not have a repository named other",
+		},
+		{
+			name:    "unmute repository",
+			text:    "unmute ghep",
+			channel: "nada-github",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				member(mock)
+				mock.ExpectExec("UPDATE mutes SET muted_until").
+					WithArgs("nada", "ghep", "").
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+			reply: "Unmuted `ghep` for nada",
+		},
+		{
+			name:    "digest without digest configured",
+			text:    "digest pr now",
+			channel: "nada-github",
+			expect:  member,
+			reply:   "nada has no pull request digest configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			if tt.expect != nil {
+				tt.expect(mock)
+			}
+
+			teamConfig := map[string]github.Team{
+				"nada": {
+					Name:    "nada",
+					Sources: []github.Source{{SourceType: "pulls", Channel: "#nada-github"}},
+				},
+			}
+			apiClient := New(slog.New(slog.DiscardHandler), Options{DB: gensql.New(mock), TeamConfig: teamConfig})
+
+			command := slashCommand{UserID: "U123", ChannelID: "C0123", ChannelName: tt.channel, Text: tt.text}
+			got := apiClient.runCommand(context.Background(), slog.New(slog.DiscardHandler), command)
+			if !strings.Contains(got, tt.reply) {
+				t.Errorf("expected reply containing %q, got %q", tt.reply, got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestNotificationsCommand(t *testing.T) {
+	notificationColumns := []string{"login", "slack_workspace", "enabled", "types", "quiet_from", "quiet_to", "timezone", "updated_at"}
+	slackID := func(mock pgxmock.PgxPoolIface) {
+		mock.ExpectQuery("FROM slack_ids").
+			WithArgs("", "U123").
+			WillReturnRows(pgxmock.NewRows([]string{"login"}).AddRow("Kyrremann"))
+	}
+
+	tests := []struct {
+		name   string
+		text   string
+		expect func(mock pgxmock.PgxPoolIface)
+		reply  string
+	}{
+		{
+			name: "not opted in",
+			text: "notifications",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				slackID(mock)
+				mock.ExpectQuery("FROM personal_notifications").
+					WithArgs("Kyrremann").
+					WillReturnRows(pgxmock.NewRows(notificationColumns))
+			},
+			reply: "Personal notifications are off",
+		},
+		{
+			name: "opt in",
+			text: "notifications on",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				slackID(mock)
+				mock.ExpectQuery("FROM personal_notifications").
+					WithArgs("Kyrremann").
+					WillReturnRows(pgxmock.NewRows(notificationColumns))
+				mock.ExpectExec("INSERT INTO personal_notifications").
+					WithArgs("Kyrremann", "", true, []string{}, "", "", "").
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			},
+			reply: "Personal notifications are on for `review_requested`, `reviewed`, `workflow_failed`, `mentioned`",
+		},
+		{
+			name: "turn off one type",
+			text: "notifications reviewed off",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				slackID(mock)
+				mock.ExpectQuery("FROM personal_notifications").
+					WithArgs("Kyrremann").
+					WillReturnRows(pgxmock.NewRows(notificationColumns).
+						AddRow("Kyrremann", "", true, []string{}, "", "", "", pgtype.Timestamptz{}))
+				mock.ExpectExec("INSERT INTO personal_notifications").
+					WithArgs("Kyrremann", "", true, []string{"review_requested", "workflow_failed", "mentioned"}, "", "", "").
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			},
+			reply: "on for `review_requested`, `workflow_failed`, `mentioned`",
+		},
+		{
+			name: "quiet hours",
+			text: "notifications quiet 18:00-08:00",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				slackID(mock)
+				mock.ExpectQuery("FROM personal_notifications").
+					WithArgs("Kyrremann").
+					WillReturnRows(pgxmock.NewRows(notificationColumns).
+						AddRow("Kyrremann", "", true, []string{}, "", "", "", pgtype.Timestamptz{}))
+				mock.ExpectExec("INSERT INTO personal_notifications").
+					WithArgs("Kyrremann", "", true, []string{}, "18:00", "08:00", "Europe/Oslo").
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+			},
+			reply: "with quiet hours 18:00-08:00 Europe/Oslo",
+		},
+		{
+			name: "invalid quiet hours",
+			text: "notifications quiet 6pm-8am",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				slackID(mock)
+				mock.ExpectQuery("FROM personal_notifications").
+					WithArgs("Kyrremann").
+					WillReturnRows(pgxmock.NewRows(notificationColumns))
+			},
+			reply: "must be in HH:MM format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.expect(mock)
+
+			db := gensql.New(mock)
+			personal := events.NewPersonalNotifier(db, nil, nil)
+			apiClient := New(slog.New(slog.DiscardHandler), Options{DB: db, Personal: personal})
+
+			command := slashCommand{UserID: "U123", ChannelID: "C0123", ChannelName: "random", Text: tt.text}
+			got := apiClient.runCommand(context.Background(), slog.New(slog.DiscardHandler), command)
+			if !strings.Contains(got, tt.reply) {
+				t.Errorf("expected reply containing %q, got %q", tt.reply, got)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
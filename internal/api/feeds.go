@@ -0,0 +1,226 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+const (
+	feedMaxEntries = 50
+	atomNamespace  = "http://www.w3.org/2005/Atom"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomSummary struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID       string       `xml:"id"`
+	Title    string       `xml:"title"`
+	Updated  string       `xml:"updated"`
+	Link     atomLink     `xml:"link"`
+	Author   *atomAuthor  `xml:"author,omitempty"`
+	Category atomCategory `xml:"category"`
+	Summary  *atomSummary `xml:"summary,omitempty"`
+}
+
+// feedGetHandler serves /feeds/{team}.atom and /feeds/{team}/{repo}.atom with
+// the latest events routed to the team. Teams in other organizations are
+// named org/slug, so the team is matched before splitting out the repository.
+// The feeds are read with the team's feed token, and are disabled without an
+// admin token to derive it from.
+func (c *Client) feedGetHandler(w http.ResponseWriter, r *http.Request) {
+	if c.adminToken == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	path, ok := strings.CutSuffix(r.PathValue("path"), ".atom")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	teamName, repository := path, ""
+	if _, ok := c.teamConfig[teamName]; !ok {
+		index := strings.LastIndex(path, "/")
+		if index < 0 {
+			http.NotFound(w, r)
+			return
+		}
+
+		teamName, repository = path[:index], path[index+1:]
+		if _, ok := c.teamConfig[teamName]; !ok || repository == "" {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	if !c.validFeedToken(r, teamName) {
+		c.log.Warn("Invalid feed token", "team", teamName)
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	var teamEvents []gensql.TeamEvent
+	var err error
+	if repository == "" {
+		teamEvents, err = c.db.ListTeamEvents(r.Context(), gensql.ListTeamEventsParams{
+			TeamSlug:  teamName,
+			MaxEvents: feedMaxEntries,
+		})
+	} else {
+		teamEvents, err = c.db.ListTeamRepositoryEvents(r.Context(), gensql.ListTeamRepositoryEventsParams{
+			TeamSlug:   teamName,
+			Repository: repository,
+			MaxEvents:  feedMaxEntries,
+		})
+	}
+	if err != nil {
+		c.log.Error("Listing team events", "team", teamName, "repository", repository, "error", err)
+		http.Error(w, "error listing team events", http.StatusInternalServerError)
+		return
+	}
+
+	title := "Ghep: " + teamName
+	if repository != "" {
+		title = fmt.Sprintf("Ghep: %s/%s", teamName, repository)
+	}
+
+	feed := createFeed(requestURL(r), title, c.teamConfig[teamName].Config.Redaction, teamEvents)
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	fmt.Fprint(w, xml.Header)
+	if err := xml.NewEncoder(w).Encode(feed); err != nil {
+		c.log.Error("Encoding feed", "team", teamName, "error", err)
+	}
+}
+
+// feedToken returns the token a team's feeds are read with. It is derived from
+// the admin token, so it does not have to be stored, and changes with it.
+func (c *Client) feedToken(teamName string) string {
+	mac := hmac.New(sha256.New, []byte(c.adminToken))
+	mac.Write([]byte("feed:" + teamName))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validFeedToken checks the token in the token query parameter, as most feed
+// readers can not set headers, or in the Authorization header.
+func (c *Client) validFeedToken(r *http.Request, teamName string) bool {
+	token := r.URL.Query().Get("token")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = bearer
+	}
+
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(c.feedToken(teamName))) == 1
+}
+
+type feedTokenResponse struct {
+	Team  string `json:"team"`
+	Token string `json:"token"`
+}
+
+// feedTokensGetHandler lists the feed token of every team, for admins to hand out.
+func (c *Client) feedTokensGetHandler(w http.ResponseWriter, r *http.Request) {
+	teamNames := make([]string, 0, len(c.teamConfig))
+	for name := range c.teamConfig {
+		teamNames = append(teamNames, name)
+	}
+	slices.Sort(teamNames)
+
+	tokens := make([]feedTokenResponse, 0, len(teamNames))
+	for _, name := range teamNames {
+		tokens = append(tokens, feedTokenResponse{Team: name, Token: c.feedToken(name)})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tokens); err != nil {
+		c.log.Error("Encoding feed tokens", "error", err)
+	}
+}
+
+// createFeed creates the feed, with secrets redacted from titles and summaries.
+func createFeed(selfURL, title string, redaction github.Redaction, teamEvents []gensql.TeamEvent) atomFeed {
+	updated := time.Now()
+	if len(teamEvents) > 0 {
+		updated = teamEvents[0].CreatedAt.Time
+	}
+
+	feed := atomFeed{
+		Xmlns:   atomNamespace,
+		ID:      selfURL,
+		Title:   title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links:   []atomLink{{Href: selfURL, Rel: "self"}},
+		Entries: make([]atomEntry, 0, len(teamEvents)),
+	}
+
+	for _, event := range teamEvents {
+		entryTitle, _ := redaction.Redact(event.Title)
+		entry := atomEntry{
+			ID:       "urn:ghep:team-event:" + strconv.FormatInt(event.ID, 10),
+			Title:    entryTitle,
+			Updated:  event.CreatedAt.Time.UTC().Format(time.RFC3339),
+			Link:     atomLink{Href: event.Url, Rel: "alternate"},
+			Category: atomCategory{Term: event.EventType},
+		}
+		if event.Author != "" {
+			entry.Author = &atomAuthor{Name: event.Author}
+		}
+		if event.Summary != "" {
+			summary, _ := redaction.Redact(event.Summary)
+			entry.Summary = &atomSummary{Type: "text", Text: summary}
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return feed
+}
+
+// requestURL returns the URL the client used, taking a TLS terminating ingress into account.
+func requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://%s%s", scheme, r.Host, r.URL.Path)
+}
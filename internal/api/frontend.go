@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+
+	"github.com/navikt/ghep/internal/github"
 )
 
 var indexHTML = `<!DOCTYPE html>
@@ -15,7 +17,7 @@ var indexHTML = `<!DOCTYPE html>
 <body>
     <h1>Teams with repositories!</h1>
     {{- range . }}
-        <h2 id="{{ .Name }}"><a href="https://github.com/orgs/navikt/teams/{{ .Name }}/repositories">{{ .Name }}</a>:</h2>
+        <h2 id="{{ .Name }}"><a href="{{ .URL }}">{{ .Name }}</a>:</h2>
         <ul>
             {{ range .Repositories }}
                 <li>{{ . }}</li>
@@ -29,6 +31,7 @@ var indexHTML = `<!DOCTYPE html>
 func (c *Client) frontendGetHandler(w http.ResponseWriter, r *http.Request) {
 	type Team struct {
 		Name         string
+		URL          string
 		Repositories []string
 	}
 
@@ -64,8 +67,14 @@ func (c *Client) frontendGetHandler(w http.ResponseWriter, r *http.Request) {
 			repos[j] = repository.Name
 		}
 
+		team, ok := c.teamConfig[teamName]
+		if !ok {
+			team = github.Team{Name: teamName}
+		}
+
 		teams[i] = Team{
 			Name:         teamName,
+			URL:          c.github.TeamURL(team) + "/repositories",
 			Repositories: repos,
 		}
 	}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+const maxInteractionSize = 1 << 20
+
+// slackInteractionsPostHandler handles clicks on buttons in messages. Slack
+// expects a response within three seconds, so the action is carried out in
+// the background, and the result is posted in the thread of the message.
+func (c *Client) slackInteractionsPostHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxInteractionSize))
+	if err != nil {
+		c.log.Error("Reading body", "error", err)
+		http.Error(w, "error reading body", http.StatusInternalServerError)
+		return
+	}
+	defer r.Body.Close()
+
+	workspace, err := c.slackWorkspaces.VerifyInteraction(r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"), body, time.Now())
+	if err != nil {
+		c.log.Warn("Invalid Slack signature", "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	interaction, err := slack.ParseInteraction(form.Get("payload"))
+	if err != nil {
+		c.log.Error("Parsing Slack interaction", "error", err)
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	if interaction.Type != "block_actions" || len(interaction.Actions) == 0 {
+		return
+	}
+
+	log := c.log.With("workspace", workspace, "slack_user", interaction.User.ID, "action", interaction.Actions[0].ActionID)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		c.handleInteraction(ctx, log, workspace, interaction)
+	}()
+}
+
+func (c *Client) handleInteraction(ctx context.Context, log *slog.Logger, workspace string, interaction slack.Interaction) {
+	slackClient, ok := c.slackWorkspaces.Get(workspace)
+	if !ok {
+		log.Error("No client for Slack workspace")
+		return
+	}
+
+	audit, err := c.runAction(ctx, log, workspace, interaction)
+	if err != nil {
+		log.Error("Running Slack action", "error", err)
+		if err := slackClient.PostEphemeral(interaction.Channel.ID, interaction.User.ID, fmt.Sprintf("Sorry, that did not work: %s", err)); err != nil {
+			log.Error("Posting ephemeral message", "error", err)
+		}
+		return
+	}
+
+	payload, err := json.Marshal(slack.Message{
+		Channel:         interaction.Channel.ID,
+		ThreadTimestamp: interaction.ThreadTimestamp(),
+		Text:            audit,
+	})
+	if err != nil {
+		log.Error("Marshalling audit message", "error", err)
+		return
+	}
+
+	if _, err := slackClient.PostMessage(payload); err != nil {
+		log.Error("Posting audit message", "error", err)
+	}
+}
+
+// runAction carries out the action as the Github App, after checking that the
+// Slack user is a member of the team the message was posted for. It returns
+// the audit line to post in the thread.
+func (c *Client) runAction(ctx context.Context, log *slog.Logger, workspace string, interaction slack.Interaction) (string, error) {
+	action := interaction.Actions[0]
+
+	target, err := slack.ParseActionTarget(action.BlockID)
+	if err != nil {
+		return "", err
+	}
+
+	team, ok := c.teamConfig[target.Team]
+	if !ok || team.SlackWorkspace != workspace {
+		return "", fmt.Errorf("unknown team %s", target.Team)
+	}
+
+	login, err := c.teamMemberLogin(ctx, workspace, interaction.User.ID, team)
+	if err != nil {
+		return "", err
+	}
+
+	githubClient, ok := c.github.ForTeam(team)
+	if !ok {
+		return "", fmt.Errorf("no Github client for team %s", team.Name)
+	}
+
+	log = log.With("team", team.Name, "login", login, "repository", target.Repository, "target_id", target.ID)
+
+	var audit string
+	switch action.ActionID {
+	case slack.ActionDismissDependabotAlert:
+		if action.SelectedOption == nil {
+			return "", fmt.Errorf("no dismiss reason selected")
+		}
+
+		reason := action.SelectedOption.Value
+		comment := fmt.Sprintf("Dismissed from Slack by @%s", login)
+		if err := githubClient.DismissDependabotAlert(ctx, target.Repository, target.ID, reason, comment); err != nil {
+			return "", fmt.Errorf("dismissing Dependabot alert: %w", err)
+		}
+
+		audit = fmt.Sprintf("<@%s> (`%s`) dismissed the alert as `%s`", interaction.User.ID, login, reason)
+	case slack.ActionRerunFailedJobs:
+		if err := githubClient.RerunFailedJobs(ctx, target.Repository, target.ID); err != nil {
+			return "", fmt.Errorf("re-running failed jobs: %w", err)
+		}
+
+		audit = fmt.Sprintf("<@%s> (`%s`) re-ran the failed jobs", interaction.User.ID, login)
+	default:
+		return "", fmt.Errorf("unknown action %s", action.ActionID)
+	}
+
+	log.Info("Ran Slack action")
+	return audit, nil
+}
+
+// githubLogin returns the Github user of a Slack user.
+func (c *Client) githubLogin(ctx context.Context, workspace, slackID string) (string, error) {
+	login, err := c.db.GetLoginBySlackID(ctx, gensql.GetLoginBySlackIDParams{Workspace: workspace, ID: slackID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("could not find your Github user, make sure your Github account has your work email")
+		}
+		return "", fmt.Errorf("getting Github user: %w", err)
+	}
+
+	return login, nil
+}
+
+// teamMemberLogin returns the Github user of a Slack user, if they are a member of the team.
+func (c *Client) teamMemberLogin(ctx context.Context, workspace, slackID string, team github.Team) (string, error) {
+	login, err := c.githubLogin(ctx, workspace, slackID)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := c.db.GetTeamMember(ctx, gensql.GetTeamMemberParams{TeamSlug: team.Name, UserLogin: login}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("only members of %s can do this", team.Name)
+		}
+		return "", fmt.Errorf("getting team member: %w", err)
+	}
+
+	return login, nil
+}
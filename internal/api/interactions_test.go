@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func signSlackRequest(secret string, timestamp time.Time, body string) (string, string) {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":" + body))
+	return ts, "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestSlackInteractionsPostHandler(t *testing.T) {
+	t.Setenv("SLACK_TOKEN", "xoxb-test")
+	t.Setenv("SLACK_SIGNING_SECRET", "signing-secret")
+
+	workspaces, err := slack.NewWorkspaces(slog.New(slog.DiscardHandler), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiClient := New(slog.New(slog.DiscardHandler), Options{TeamConfig: map[string]github.Team{}, SlackWorkspaces: workspaces})
+	body := url.Values{"payload": {`{"type": "view_closed"}`}}.Encode()
+
+	tests := []struct {
+		name       string
+		secret     string
+		timestamp  time.Time
+		statusCode int
+	}{
+		{
+			name:       "valid signature",
+			secret:     "signing-secret",
+			timestamp:  time.Now(),
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "wrong secret",
+			secret:     "other-secret",
+			timestamp:  time.Now(),
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "replayed request",
+			secret:     "signing-secret",
+			timestamp:  time.Now().Add(-10 * time.Minute),
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timestamp, signature := signSlackRequest(tt.secret, tt.timestamp, body)
+
+			req := httptest.NewRequest(http.MethodPost, "/slack/interactions", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+			req.Header.Set("X-Slack-Signature", signature)
+
+			recorder := httptest.NewRecorder()
+			apiClient.slackInteractionsPostHandler(recorder, req)
+
+			if recorder.Code != tt.statusCode {
+				t.Errorf("expected status code %d, got %d", tt.statusCode, recorder.Code)
+			}
+		})
+	}
+}
+
+func TestRunActionRequiresTeamMember(t *testing.T) {
+	interaction := func(blockID string) slack.Interaction {
+		payload := `{"type": "block_actions", "user": {"id": "U123"}, "actions": [{"action_id": "rerun_failed_jobs", "block_id": "` + blockID + `"}]}`
+		interaction, err := slack.ParseInteraction(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return interaction
+	}
+
+	tests := []struct {
+		name        string
+		interaction slack.Interaction
+		expect      func(mock pgxmock.PgxPoolIface)
+		err         string
+	}{
+		{
+			name:        "unknown team",
+			interaction: interaction("unknown|ghep|42"),
+			err:         "unknown team unknown",
+		},
+		{
+			name:        "Slack user without Github user",
+			interaction: interaction("nada|ghep|42"),
+			expect: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("FROM slack_ids").
+					WithArgs("", "U123").
+					WillReturnError(pgx.ErrNoRows)
+			},
+			err: "could not find your Github user",
+		},
+		{
+			name:        "Github user outside the team",
+			interaction: interaction("nada|ghep|42"),
+			expect: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("FROM slack_ids").
+					WithArgs("", "U123").
+					WillReturnRows(pgxmock.NewRows([]string{"login"}).AddRow("Kyrremann"))
+				mock.ExpectQuery("FROM team_members").
+					WithArgs("nada", "Kyrremann").
+					WillReturnError(pgx.ErrNoRows)
+			},
+			err: "only members of nada can do this",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			if tt.expect != nil {
+				tt.expect(mock)
+			}
+
+			teamConfig := map[string]github.Team{"nada": {Name: "nada"}}
+			apiClient := New(slog.New(slog.DiscardHandler), Options{DB: gensql.New(mock), TeamConfig: teamConfig})
+
+			_, err = apiClient.runAction(context.Background(), slog.New(slog.DiscardHandler), "", tt.interaction)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
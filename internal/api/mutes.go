@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+const maxMuteDuration = 30 * 24 * time.Hour
+
+type muteRequest struct {
+	Team       string `json:"team"`
+	Repository string `json:"repository"`
+	SourceType string `json:"source"`
+	Duration   string `json:"duration"`
+	Reason     string `json:"reason"`
+	CreatedBy  string `json:"createdBy"`
+}
+
+type muteResponse struct {
+	ID         int64     `json:"id"`
+	Team       string    `json:"team"`
+	Repository string    `json:"repository,omitempty"`
+	SourceType string    `json:"source,omitempty"`
+	MutedUntil time.Time `json:"mutedUntil"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedBy  string    `json:"createdBy"`
+	Suppressed int32     `json:"suppressed"`
+}
+
+func toMuteResponse(mute gensql.Mute) muteResponse {
+	return muteResponse{
+		ID:         mute.ID,
+		Team:       mute.TeamSlug,
+		Repository: mute.Repository,
+		SourceType: mute.SourceType,
+		MutedUntil: mute.MutedUntil.Time,
+		Reason:     mute.Reason,
+		CreatedBy:  mute.CreatedBy,
+		Suppressed: mute.Suppressed,
+	}
+}
+
+func (c *Client) mutesGetHandler(w http.ResponseWriter, r *http.Request) {
+	mutes, err := c.db.ListAllActiveMutes(r.Context())
+	if err != nil {
+		c.log.Error("Listing mutes", "error", err)
+		http.Error(w, "error listing mutes", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]muteResponse, 0, len(mutes))
+	for _, mute := range mutes {
+		response = append(response, toMuteResponse(mute))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		c.log.Error("Writing mutes", "error", err)
+	}
+}
+
+func (c *Client) mutesPostHandler(w http.ResponseWriter, r *http.Request) {
+	var request muteRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	team, ok := c.teamConfig[request.Team]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown team %s", request.Team), http.StatusBadRequest)
+		return
+	}
+
+	duration, err := parseMuteDuration(request.Duration)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.CreatedBy == "" {
+		request.CreatedBy = "admin"
+	}
+
+	mute, err := c.createMute(r.Context(), team, request.Repository, request.SourceType, duration, request.Reason, request.CreatedBy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.log.Info("Mute created from admin endpoint", "team", team.Name, "repository", mute.Repository, "source_type", mute.SourceType, "muted_until", mute.MutedUntil.Time)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toMuteResponse(mute)); err != nil {
+		c.log.Error("Writing mute", "error", err)
+	}
+}
+
+// muteDeleteHandler ends the mute right away, so the suppressed events are
+// reported as if it had expired.
+func (c *Client) muteDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid mute ID", http.StatusBadRequest)
+		return
+	}
+
+	ended, err := c.db.EndMute(r.Context(), id)
+	if err != nil {
+		c.log.Error("Ending mute", "id", id, "error", err)
+		http.Error(w, "error ending mute", http.StatusInternalServerError)
+		return
+	}
+
+	if ended == 0 {
+		http.Error(w, "no active mute with that ID", http.StatusNotFound)
+		return
+	}
+
+	c.log.Info("Mute ended from admin endpoint", "id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// createMute mutes a repository, a source type, or a source type in one
+// repository, for the team.
+func (c *Client) createMute(ctx context.Context, team github.Team, repository, sourceType string, duration time.Duration, reason, createdBy string) (gensql.Mute, error) {
+	if repository == "" && sourceType == "" {
+		return gensql.Mute{}, fmt.Errorf("a mute needs a repository, a source, or both")
+	}
+
+	if sourceType != "" && !slices.Contains(github.SourceTypes, sourceType) {
+		return gensql.Mute{}, fmt.Errorf("invalid source %q, must be one of %s", sourceType, strings.Join(github.SourceTypes, ", "))
+	}
+
+	if repository != "" {
+		if err := c.ensureTeamRepository(ctx, team, repository); err != nil {
+			return gensql.Mute{}, err
+		}
+	}
+
+	mute, err := c.db.CreateMute(ctx, gensql.CreateMuteParams{
+		TeamSlug:   team.Name,
+		Repository: repository,
+		SourceType: sourceType,
+		MutedUntil: pgtype.Timestamptz{Time: time.Now().Add(duration), Valid: true},
+		Reason:     reason,
+		CreatedBy:  createdBy,
+	})
+	if err != nil {
+		return gensql.Mute{}, fmt.Errorf("creating mute: %w", err)
+	}
+
+	return mute, nil
+}
+
+// ensureTeamRepository returns an error if the repository does not belong to the team.
+func (c *Client) ensureTeamRepository(ctx context.Context, team github.Team, repository string) error {
+	repositories, err := c.db.ListTeamRepositories(ctx, team.Name)
+	if err != nil {
+		return fmt.Errorf("listing team repositories: %w", err)
+	}
+
+	if !slices.ContainsFunc(repositories, func(r gensql.Repository) bool { return r.Name == repository }) {
+		return fmt.Errorf("%s does not have a repository named %s", team.Name, repository)
+	}
+
+	return nil
+}
+
+// allRepositories is the repository in a slash command target that mutes a
+// source type in all of the team's repositories.
+const allRepositories = "*"
+
+// parseMuteTarget parses what to mute from a slash command, either a
+// repository, a source type in one repository as "repository/source", or a
+// source type in all repositories as "*/source". A target without a slash is
+// always a repository, so repositories named like a source type can be muted.
+func parseMuteTarget(target string) (string, string) {
+	repository, sourceType, ok := strings.Cut(target, "/")
+	if !ok {
+		return target, ""
+	}
+
+	if repository == allRepositories {
+		return "", sourceType
+	}
+
+	return repository, sourceType
+}
+
+// parseMuteDuration parses a duration like 2d, 1w or 3h, up to maxMuteDuration.
+func parseMuteDuration(value string) (time.Duration, error) {
+	var duration time.Duration
+	switch {
+	case strings.HasSuffix(value, "d"), strings.HasSuffix(value, "w"):
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+
+		duration = time.Duration(n) * 24 * time.Hour
+		if strings.HasSuffix(value, "w") {
+			duration *= 7
+		}
+	default:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		duration = d
+	}
+
+	if duration < time.Minute || duration > maxMuteDuration {
+		return 0, fmt.Errorf("duration must be between 1 minute and %d days", int(maxMuteDuration.Hours()/24))
+	}
+
+	return duration, nil
+}
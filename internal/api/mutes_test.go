@@ -0,0 +1,202 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/sql/gensql"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var muteColumns = []string{"id", "team_slug", "repository", "muted_until", "created_by", "created_at", "source_type", "reason", "suppressed", "reported_at"}
+
+func TestParseMuteDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		err      bool
+	}{
+		{value: "2d", expected: 48 * time.Hour},
+		{value: "1w", expected: 7 * 24 * time.Hour},
+		{value: "3h", expected: 3 * time.Hour},
+		{value: "90m", expected: 90 * time.Minute},
+		{value: "10s", err: true},
+		{value: "31d", err: true},
+		{value: "-1d", err: true},
+		{value: "forever", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseMuteDuration(tt.value)
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("parseMuteDuration() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseMuteTarget(t *testing.T) {
+	tests := []struct {
+		target     string
+		repository string
+		sourceType string
+	}{
+		{target: "ghep", repository: "ghep"},
+		{target: "pulls", repository: "pulls"},
+		{target: "*/pulls", sourceType: "pulls"},
+		{target: "ghep/pulls", repository: "ghep", sourceType: "pulls"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			repository, sourceType := parseMuteTarget(tt.target)
+			if repository != tt.repository || sourceType != tt.sourceType {
+				t.Errorf("parseMuteTarget() = (%q, %q), want (%q, %q)", repository, sourceType, tt.repository, tt.sourceType)
+			}
+		})
+	}
+}
+
+func TestMutesPostHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		statusCode int
+		response   string
+	}{
+		{
+			name:       "unknown team",
+			body:       `{"team": "unknown", "source": "pulls", "duration": "2d"}`,
+			statusCode: http.StatusBadRequest,
+			response:   "unknown team unknown",
+		},
+		{
+			name:       "invalid duration",
+			body:       `{"team": "nada", "source": "pulls", "duration": "forever"}`,
+			statusCode: http.StatusBadRequest,
+			response:   "invalid duration",
+		},
+		{
+			name:       "nothing to mute",
+			body:       `{"team": "nada", "duration": "2d"}`,
+			statusCode: http.StatusBadRequest,
+			response:   "a mute needs a repository, a source, or both",
+		},
+		{
+			name:       "mute source",
+			body:       `{"team": "nada", "source": "pulls", "duration": "2d", "reason": "Renovate migration"}`,
+			statusCode: http.StatusCreated,
+			response:   `"createdBy":"admin","suppressed":0`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			if tt.statusCode == http.StatusCreated {
+				mock.ExpectQuery("INSERT INTO mutes").
+					WithArgs("nada", "", "pulls", pgxmock.AnyArg(), "Renovate migration", "admin").
+					WillReturnRows(pgxmock.NewRows(muteColumns).
+						AddRow(int64(1), "nada", "", pgtype.Timestamptz{Time: time.Now(), Valid: true}, "admin", pgtype.Timestamptz{}, "pulls", "Renovate migration", int32(0), pgtype.Timestamptz{}))
+			}
+
+			teamConfig := map[string]github.Team{"nada": {Name: "nada"}}
+			apiClient := New(slog.New(slog.DiscardHandler), Options{DB: gensql.New(mock), TeamConfig: teamConfig})
+
+			req := httptest.NewRequest(http.MethodPost, "/internal/mutes", strings.NewReader(tt.body))
+			recorder := httptest.NewRecorder()
+			apiClient.mutesPostHandler(recorder, req)
+
+			if recorder.Code != tt.statusCode {
+				t.Errorf("expected status %d, got %d: %s", tt.statusCode, recorder.Code, recorder.Body)
+			}
+
+			if !strings.Contains(recorder.Body.String(), tt.response) {
+				t.Errorf("expected response containing %q, got %q", tt.response, recorder.Body)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestMuteDeleteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		ended      int64
+		statusCode int
+	}{
+		{
+			name:       "active mute",
+			id:         "1",
+			ended:      1,
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "no active mute",
+			id:         "2",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "invalid ID",
+			id:         "ghep",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			if tt.statusCode != http.StatusBadRequest {
+				mock.ExpectExec("UPDATE mutes SET muted_until").
+					WithArgs(pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("UPDATE", tt.ended))
+			}
+
+			apiClient := New(slog.New(slog.DiscardHandler), Options{DB: gensql.New(mock), TeamConfig: map[string]github.Team{}})
+
+			req := httptest.NewRequest(http.MethodDelete, "/internal/mutes/"+tt.id, nil)
+			req.SetPathValue("id", tt.id)
+			recorder := httptest.NewRecorder()
+			apiClient.muteDeleteHandler(recorder, req)
+
+			if recorder.Code != tt.statusCode {
+				t.Errorf("expected status %d, got %d: %s", tt.statusCode, recorder.Code, recorder.Body)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
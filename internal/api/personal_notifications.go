@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+const notificationsUsage = "Usage:\n" +
+	"• `/ghep notifications` shows your settings\n" +
+	"• `/ghep notifications on` or `off` turns DMs about your own pull requests, commits and mentions on or off\n" +
+	"• `/ghep notifications <type> on` or `off` toggles one type, where types are `review_requested`, `reviewed`, `workflow_failed` and `mentioned`\n" +
+	"• `/ghep notifications quiet 18:00-08:00 [timezone]` sets quiet hours without DMs, and `quiet off` removes them"
+
+// notificationsCommand shows or changes the preferences for personal
+// notifications, which are stored in the database on top of teams.yaml.
+func (c *Client) notificationsCommand(ctx context.Context, command slashCommand, args []string) (string, error) {
+	if c.personal == nil {
+		return "", fmt.Errorf("personal notifications are not available")
+	}
+
+	login, err := c.githubLogin(ctx, command.Workspace, command.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	preferences, enabled, err := c.personal.Preferences(ctx, login)
+	if err != nil {
+		return "", fmt.Errorf("getting preferences: %w", err)
+	}
+	preferences.Login = login
+	preferences.SlackWorkspace = command.Workspace
+
+	switch {
+	case len(args) == 0:
+		return describeNotifications(preferences, enabled), nil
+	case len(args) == 1 && (args[0] == "on" || args[0] == "off"):
+		enabled = args[0] == "on"
+	case args[0] == "quiet" && len(args) == 2 && args[1] == "off":
+		preferences.QuietHours = nil
+	case args[0] == "quiet" && (len(args) == 2 || len(args) == 3):
+		from, to, ok := strings.Cut(args[1], "-")
+		if !ok {
+			return "", fmt.Errorf("quiet hours must be like `18:00-08:00`")
+		}
+
+		preferences.QuietHours = &github.QuietHours{From: from, To: to}
+		if len(args) == 3 {
+			preferences.QuietHours.Timezone = args[2]
+		}
+	case len(args) == 2 && slices.Contains(github.PersonalNotificationTypes, args[0]) && (args[1] == "on" || args[1] == "off"):
+		types := preferences.Types
+		if len(types) == 0 {
+			types = slices.Clone(github.PersonalNotificationTypes)
+		}
+
+		types = slices.DeleteFunc(types, func(t string) bool { return t == args[0] })
+		if args[1] == "on" {
+			types = append(types, args[0])
+			enabled = true
+		}
+
+		// No types means all types, so turning off the last one turns off notifications
+		if len(types) == 0 {
+			enabled = false
+			types = nil
+		}
+		preferences.Types = types
+	default:
+		return notificationsUsage, nil
+	}
+
+	if err := github.ApplyPersonalNotificationsDefaults(&preferences); err != nil {
+		return "", err
+	}
+
+	params := gensql.UpsertPersonalNotificationsParams{
+		Login:          login,
+		SlackWorkspace: preferences.SlackWorkspace,
+		Enabled:        enabled,
+		Types:          preferences.Types,
+	}
+	if params.Types == nil {
+		params.Types = []string{}
+	}
+	if preferences.QuietHours != nil {
+		params.QuietFrom = preferences.QuietHours.From
+		params.QuietTo = preferences.QuietHours.To
+		params.Timezone = preferences.QuietHours.Timezone
+	}
+
+	if err := c.db.UpsertPersonalNotifications(ctx, params); err != nil {
+		return "", fmt.Errorf("saving preferences: %w", err)
+	}
+
+	return describeNotifications(preferences, enabled), nil
+}
+
+func describeNotifications(preferences github.PersonalNotifications, enabled bool) string {
+	if !enabled {
+		return "Personal notifications are off, turn them on with `/ghep notifications on`"
+	}
+
+	types := preferences.Types
+	if len(types) == 0 {
+		types = github.PersonalNotificationTypes
+	}
+
+	text := fmt.Sprintf("Personal notifications are on for `%s`", strings.Join(types, "`, `"))
+	if preferences.QuietHours != nil {
+		text += fmt.Sprintf(", with quiet hours %s-%s %s", preferences.QuietHours.From, preferences.QuietHours.To, preferences.QuietHours.Timezone)
+	}
+
+	return text
+}
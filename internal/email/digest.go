@@ -0,0 +1,192 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+var months = []string{
+	"", "januar", "februar", "mars", "april", "mai", "juni",
+	"juli", "august", "september", "oktober", "november", "desember",
+}
+
+func dateString(now time.Time) string {
+	return fmt.Sprintf("%d. %s %d", now.Day(), months[now.Month()], now.Year())
+}
+
+func plural(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+var funcs = map[string]any{
+	"plural": plural,
+	"int":    func(i int32) int { return int(i) },
+	"days": func(createdAt time.Time) int {
+		return int(time.Since(createdAt).Hours() / 24)
+	},
+	"criticals": func(alerts []github.SecurityAlert) int {
+		var criticals int
+		for _, a := range alerts {
+			if github.AsSeverityType(a.Severity) == github.SeverityCritical {
+				criticals++
+			}
+		}
+		return criticals
+	},
+}
+
+const layoutHTML = `{{ define "layout" }}<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif; color: #1f2328;">
+<h2>{{ .Title }}</h2>
+<p>{{ .Summary }}</p>
+{{ template "content" . }}
+<p style="color: #59636e; font-size: small;">Sendt av Ghep</p>
+</body>
+</html>
+{{ end }}`
+
+var (
+	pullRequestText = template.Must(template.New("pulls").Funcs(funcs).Parse(`{{ .Title }}
+{{ .Summary }}
+{{ range .Repos }}
+{{ .RepoName }} ({{ len .PRs }} {{ plural (len .PRs) "åpen" "åpne" }})
+{{ range .PRs }}- #{{ .Number }} {{ .Title }} ({{ days .CreatedAt }} {{ plural (days .CreatedAt) "dag" "dager" }})
+  {{ .URL }}
+{{ end }}{{ end }}`))
+
+	pullRequestHTML = htmltemplate.Must(htmltemplate.New("pulls").Funcs(funcs).Parse(layoutHTML + `{{ define "content" }}{{ range .Repos }}
+<h3>{{ .RepoName }} ({{ len .PRs }} {{ plural (len .PRs) "åpen" "åpne" }})</h3>
+<ul>
+{{ range .PRs }}<li><a href="{{ .URL }}">#{{ .Number }} {{ .Title }}</a> ({{ days .CreatedAt }} {{ plural (days .CreatedAt) "dag" "dager" }})</li>
+{{ end }}</ul>
+{{ end }}{{ end }}{{ template "layout" . }}`))
+
+	securityText = template.Must(template.New("security").Funcs(funcs).Parse(`{{ .Title }}
+{{ .Summary }}
+{{ with .Trend }}{{ with .NewlyCritical }}
+Nye critical siden forrige digest:
+{{ range . }}- {{ .Repository }}: {{ .Description }}
+  {{ .URL }}
+{{ end }}{{ end }}{{ end }}{{ range .Repos }}
+{{ .Repository.Name }}{{ $name := .Repository.Name }}{{ with $.Trend }}{{ with .Delta $name }} {{ . }}{{ end }}{{ end }} ({{ .Repository.URL }}/security){{ if not .Oldest.IsZero }}, eldste: {{ days .Oldest }} {{ plural (days .Oldest) "dag" "dager" }}{{ end }}
+- Secret scanning: {{ len .SecretScanning }}
+- Code scanning: {{ len .CodeScanning }}{{ with criticals .CodeScanning }} ({{ . }} critical){{ end }}
+- Dependabot: {{ len .Dependabot }}{{ with criticals .Dependabot }} ({{ . }} critical){{ end }}
+{{ end }}`))
+
+	securityHTML = htmltemplate.Must(htmltemplate.New("security").Funcs(funcs).Parse(layoutHTML + `{{ define "content" }}<table style="border-collapse: collapse;">
+<tr><th align="left">Repo</th><th>Secret scanning</th><th>Code scanning</th><th>Dependabot</th><th>Eldste</th></tr>
+{{ range .Repos }}<tr>
+<td style="padding: 4px 8px;"><a href="{{ .Repository.URL }}/security">{{ .Repository.Name }}</a>{{ $name := .Repository.Name }}{{ with $.Trend }}{{ with .Delta $name }} {{ . }}{{ end }}{{ end }}</td>
+<td align="center"><a href="{{ .Repository.URL }}/security/secret-scanning">{{ len .SecretScanning }}</a></td>
+<td align="center"><a href="{{ .Repository.URL }}/security/code-scanning">{{ len .CodeScanning }}</a>{{ with criticals .CodeScanning }} <strong style="color: #d1242f;">({{ . }} critical)</strong>{{ end }}</td>
+<td align="center"><a href="{{ .Repository.URL }}/security/dependabot">{{ len .Dependabot }}</a>{{ with criticals .Dependabot }} <strong style="color: #d1242f;">({{ . }} critical)</strong>{{ end }}</td>
+<td align="center">{{ if not .Oldest.IsZero }}{{ days .Oldest }} {{ plural (days .Oldest) "dag" "dager" }}{{ end }}</td>
+</tr>
+{{ end }}</table>{{ with .Trend }}{{ with .NewlyCritical }}
+<h3>Nye critical siden forrige digest</h3>
+<ul>
+{{ range . }}<li>{{ .Repository }}: <a href="{{ .URL }}">{{ .Description }}</a></li>
+{{ end }}</ul>{{ end }}{{ end }}{{ end }}{{ template "layout" . }}`))
+
+	personalText = template.Must(template.New("personal").Funcs(funcs).Parse(`{{ .Title }}
+{{ .Summary }}
+
+{{ range .Repos }}- {{ .Repo }}: {{ .CommitCount }} {{ plural (int .CommitCount) "commit" "commits" }}
+{{ end }}`))
+
+	personalHTML = htmltemplate.Must(htmltemplate.New("personal").Funcs(funcs).Parse(layoutHTML + `{{ define "content" }}<ul>
+{{ range .Repos }}<li><strong>{{ .Repo }}</strong> — {{ .CommitCount }} {{ plural (int .CommitCount) "commit" "commits" }}</li>
+{{ end }}</ul>{{ end }}{{ template "layout" . }}`))
+)
+
+type digestData[T any] struct {
+	Title   string
+	Summary string
+	Repos   []T
+	// Trend is only set for security digests with a previous digest to compare with.
+	Trend *github.SecurityTrend
+}
+
+func render[T any](to []string, data digestData[T], text *template.Template, html *htmltemplate.Template) (Message, error) {
+	var textBuf, htmlBuf bytes.Buffer
+	if err := text.Execute(&textBuf, data); err != nil {
+		return Message{}, fmt.Errorf("rendering text: %w", err)
+	}
+	if err := html.Execute(&htmlBuf, data); err != nil {
+		return Message{}, fmt.Errorf("rendering HTML: %w", err)
+	}
+
+	return Message{
+		To:      to,
+		Subject: data.Title,
+		Text:    textBuf.String(),
+		HTML:    htmlBuf.String(),
+	}, nil
+}
+
+// CreatePullRequestDigest renders the weekly pull request digest, with the same content as in Slack.
+func CreatePullRequestDigest(to []string, teamName string, repoPRs []github.RepoPRs, now time.Time) (Message, error) {
+	title := "Ukentlig PR-oversikt — " + dateString(now)
+	if teamName != "" {
+		title = fmt.Sprintf("Ukentlig PR-oversikt for %s — %s", teamName, dateString(now))
+	}
+
+	totalPRs := 0
+	for _, repo := range repoPRs {
+		totalPRs += len(repo.PRs)
+	}
+
+	summary := fmt.Sprintf("%d %s med %d %s", len(repoPRs), plural(len(repoPRs), "repo", "repos"), totalPRs, plural(totalPRs, "åpen pull request", "åpne pull requests"))
+	if len(repoPRs) == 0 {
+		summary = "Gratulerer! Alle pull requests er merget – dere er helt à jour!"
+	}
+
+	return render(to, digestData[github.RepoPRs]{Title: title, Summary: summary, Repos: repoPRs}, pullRequestText, pullRequestHTML)
+}
+
+// CreateSecurityDigest renders the weekly security digest, with the same content as in Slack.
+func CreateSecurityDigest(to []string, teamName string, repoAlerts []github.RepoSecurityAlerts, trend *github.SecurityTrend, now time.Time) (Message, error) {
+	title := "Ukentlig sikkerhetsdigest — " + dateString(now)
+	if teamName != "" {
+		title = fmt.Sprintf("Ukentlig sikkerhetsdigest for %s — %s", teamName, dateString(now))
+	}
+
+	total := 0
+	for _, r := range repoAlerts {
+		total += r.Total()
+	}
+
+	summary := fmt.Sprintf("%d %s på tvers av %d %s", total, plural(total, "åpent sikkerhetsvarsel", "åpne sikkerhetsvarsler"), len(repoAlerts), plural(len(repoAlerts), "repo", "repos"))
+	if len(repoAlerts) == 0 {
+		summary = "Gratulerer! Ingen åpne sikkerhetsvarsler – dere er helt sikre!"
+	}
+	if trend != nil {
+		summary += fmt.Sprintf(" (%d nye, %d lukket siden forrige digest)", trend.New, trend.Fixed)
+	}
+
+	return render(to, digestData[github.RepoSecurityAlerts]{Title: title, Summary: summary, Repos: repoAlerts, Trend: trend}, securityText, securityHTML)
+}
+
+// CreatePersonalDigest renders the weekly personal commit digest, with the same content as in Slack.
+func CreatePersonalDigest(to []string, repos []gensql.GetUserCommitsSinceRow, now time.Time) (Message, error) {
+	var totalCommits int
+	for _, r := range repos {
+		totalCommits += int(r.CommitCount)
+	}
+
+	title := "Din ukentlige commit-oversikt — " + dateString(now)
+	summary := fmt.Sprintf("%d %s med %d %s totalt", len(repos), plural(len(repos), "repo", "repos"), totalCommits, plural(totalCommits, "commit", "commits"))
+
+	return render(to, digestData[gensql.GetUserCommitsSinceRow]{Title: title, Summary: summary, Repos: repos}, personalText, personalHTML)
+}
@@ -0,0 +1,291 @@
+// Package email sends digests as multipart HTML and plain-text emails over SMTP.
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+)
+
+const (
+	TLSStartTLS = "starttls"
+	TLSImplicit = "implicit"
+	TLSNone     = "none"
+
+	defaultPort = "587"
+
+	// smtpDialTimeout is how long connecting to the SMTP server may take, and
+	// smtpSessionTimeout how long sending one email may take in total.
+	smtpDialTimeout    = 30 * time.Second
+	smtpSessionTimeout = 2 * time.Minute
+)
+
+// Message is an email with both an HTML and a plain-text body.
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Redact returns the message with secrets redacted from its subject and bodies.
+func (m Message) Redact(redaction github.Redaction) Message {
+	m.Subject, _ = redaction.Redact(m.Subject)
+	m.Text, _ = redaction.Redact(m.Text)
+	m.HTML, _ = redaction.Redact(m.HTML)
+	return m
+}
+
+type Client struct {
+	log      *slog.Logger
+	host     string
+	addr     string
+	from     string
+	username string
+	password string
+	tlsMode  string
+}
+
+// New configures SMTP from SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD
+// (or SMTP_PASSWORD_FILE), SMTP_FROM and SMTP_TLS. SMTP is only required when
+// a digest has email recipients.
+func New(log *slog.Logger, teams map[string]github.Team, personalDigestUsers []github.PersonalDigestUserEntry) (*Client, error) {
+	password, err := passwordFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("reading SMTP password: %w", err)
+	}
+
+	host := os.Getenv("SMTP_HOST")
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = defaultPort
+	}
+
+	tlsMode := strings.ToLower(os.Getenv("SMTP_TLS"))
+	if tlsMode == "" {
+		tlsMode = TLSStartTLS
+	}
+	if tlsMode != TLSStartTLS && tlsMode != TLSImplicit && tlsMode != TLSNone {
+		return nil, fmt.Errorf("SMTP_TLS must be one of %s, %s or %s, got %q", TLSStartTLS, TLSImplicit, TLSNone, tlsMode)
+	}
+
+	client := &Client{
+		log:      log,
+		host:     host,
+		addr:     net.JoinHostPort(host, port),
+		from:     os.Getenv("SMTP_FROM"),
+		username: os.Getenv("SMTP_USERNAME"),
+		password: password,
+		tlsMode:  tlsMode,
+	}
+
+	if !usesEmail(teams, personalDigestUsers) {
+		return client, nil
+	}
+
+	if host == "" {
+		return nil, fmt.Errorf("SMTP_HOST is required when digests have email recipients")
+	}
+	if _, err := mail.ParseAddress(client.from); err != nil {
+		return nil, fmt.Errorf("SMTP_FROM %q is not a valid address: %w", client.from, err)
+	}
+
+	return client, nil
+}
+
+func usesEmail(teams map[string]github.Team, personalDigestUsers []github.PersonalDigestUserEntry) bool {
+	for _, team := range teams {
+		if team.PullRequestDigest != nil && len(team.PullRequestDigest.Email) > 0 {
+			return true
+		}
+		if team.SecurityDigest != nil && len(team.SecurityDigest.Email) > 0 {
+			return true
+		}
+	}
+
+	for _, user := range personalDigestUsers {
+		if len(user.Email) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func passwordFromEnv() (string, error) {
+	if password := os.Getenv("SMTP_PASSWORD"); password != "" {
+		return password, nil
+	}
+
+	path := os.Getenv("SMTP_PASSWORD_FILE")
+	if path == "" {
+		return "", nil
+	}
+
+	password, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(password)), nil
+}
+
+// Send delivers the message to all recipients in one SMTP transaction.
+func (c *Client) Send(message Message) error {
+	if c.host == "" {
+		return fmt.Errorf("SMTP is not configured")
+	}
+
+	body, err := c.build(message, time.Now())
+	if err != nil {
+		return fmt.Errorf("building email: %w", err)
+	}
+
+	if err := c.send(message.To, body); err != nil {
+		return fmt.Errorf("sending email: %w", err)
+	}
+
+	c.log.Info("Email sent", "subject", message.Subject, "recipients", len(message.To))
+	return nil
+}
+
+func (c *Client) send(to []string, body []byte) error {
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+
+	var conn net.Conn
+	var err error
+	if c.tlsMode == TLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", c.addr, &tls.Config{ServerName: c.host, MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", c.addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	// A stalled server would otherwise hang the digest forever
+	if err := conn.SetDeadline(time.Now().Add(smtpSessionTimeout)); err != nil {
+		conn.Close() // #nosec G104 -- closing connection after failing to set the deadline, error intentionally ignored
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		conn.Close() // #nosec G104 -- closing connection after failed handshake, error intentionally ignored
+		return err
+	}
+	defer client.Close()
+
+	// Without STARTTLS the credentials and the digest would be sent in clear text
+	if c.tlsMode == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server %s does not offer STARTTLS, set SMTP_TLS to %s or %s", c.host, TLSImplicit, TLSNone)
+		}
+
+		if err := client.StartTLS(&tls.Config{ServerName: c.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+
+	if c.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.username, c.password, c.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(c.from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// build renders the message as multipart/alternative, with the plain-text
+// part first so clients prefer the HTML part.
+func (c *Client) build(message Message, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	domain := "ghep"
+	if _, d, ok := strings.Cut(c.from, "@"); ok {
+		domain = strings.TrimSuffix(d, ">")
+	}
+
+	headers := []string{
+		"From: " + c.from,
+		"To: " + strings.Join(message.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: " + now.Format(time.RFC1123Z),
+		"Message-ID: <" + hex.EncodeToString(id) + "@" + domain + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + writer.Boundary(),
+	}
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
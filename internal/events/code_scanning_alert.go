@@ -2,17 +2,15 @@ package events
 
 import (
 	"context"
-	"errors"
 	"log/slog"
+	"slices"
 
-	"github.com/jackc/pgx/v5"
 	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/slack"
-	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
 func (h *Handler) handleCodeScanningAlertEvent(ctx context.Context, log *slog.Logger, team github.Team, source github.Source, event github.Event) (*slack.Message, error) {
-	if event.Action == "appeared_in_branch" {
+	if !slices.Contains([]string{"created", "fixed", "closed_by_user", "dismissed", "reopened", "reopened_by_user"}, event.Action) {
 		return nil, nil
 	}
 
@@ -20,20 +18,12 @@ func (h *Handler) handleCodeScanningAlertEvent(ctx context.Context, log *slog.Lo
 		return nil, nil
 	}
 
-	message, err := h.db.GetSlackMessage(ctx, gensql.GetSlackMessageParams{
-		TeamSlug: team.Name,
-		EventID:  event.Alert.URL,
-		Channel:  source.Channel,
-	})
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		log.Error("Getting slack message", "error", err, "event_id", event.Alert.URL)
-	}
-
 	channel := source.Channel
-	if message.Channel != "" {
-		channel = message.Channel
+	var timestamp string
+	if event.Action != "created" {
+		channel, timestamp = h.securityThread(ctx, log, team, source, event.Alert.URL, event.Action)
 	}
 
 	log.Info("Received code scanning alert")
-	return slack.CreateCodeScanningAlertMessage(channel, message.ThreadTs, event), nil
+	return slack.CreateCodeScanningAlertMessage(channel, timestamp, usesBlockKit(team, channel), event), nil
 }
@@ -102,7 +102,7 @@ func TestHandleCommitEventBranchFilter(t *testing.T) {
 				}
 			}
 
-			msg, err := handleCommitEvent(context.Background(), slog.Default(), tt.source, tt.event, &gensql.Queries{})
+			msg, err := handleCommitEvent(context.Background(), slog.Default(), github.Team{}, tt.source, tt.event, &gensql.Queries{})
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -131,7 +131,7 @@ func TestHandleCommitEvents(t *testing.T) {
 			},
 		},
 	}
-	handler := NewHandler(db, slackClient, map[string]github.Team{"test": team})
+	handler := NewHandler(db, &mock.Github{}, slackClient.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team})
 
 	t.Run("Simple commit event", func(t *testing.T) {
 		event, err := testdata.AsEvent("commit-1.json")
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// isHeldBack returns true if the source's delivery window is closed, and the
+// event is not important enough to be posted anyway. Events are posted right
+// away if the window is invalid, rather than held back until it is fixed.
+func (h *Handler) isHeldBack(log *slog.Logger, source github.Source, eventType github.EventType, event github.Event) bool {
+	window := source.Config.DeliveryWindow
+	if window == nil {
+		return false
+	}
+
+	open, err := window.IsOpen(h.now())
+	if err != nil {
+		log.Error("Checking delivery window", "error", err)
+		return false
+	}
+
+	return !open && !window.Bypasses(eventType, event)
+}
+
+// queueMessage stores the message until the delivery window opens, when it is
+// posted in the thread of a summary.
+func (h *Handler) queueMessage(ctx context.Context, log *slog.Logger, team github.Team, source github.Source, eventType github.EventType, event github.Event, channel string, payload []byte) error {
+	log.Info("Queueing message outside delivery window")
+
+	return h.db.QueueEvent(ctx, gensql.QueueEventParams{
+		TeamSlug:   team.Name,
+		Channel:    channel,
+		SourceType: source.SourceType,
+		EventType:  eventType.Name(),
+		EventID:    getEventID(event),
+		Payload:    payload,
+	})
+}
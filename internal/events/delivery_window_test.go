@@ -0,0 +1,83 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/mock"
+	"github.com/navikt/ghep/internal/testdata"
+)
+
+func TestHandleDeliveryWindow(t *testing.T) {
+	saturday := time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		bypass   []string
+		messages int
+		queued   int
+	}{
+		{
+			name:     "inside window",
+			now:      monday,
+			messages: 1,
+		},
+		{
+			name:   "outside window",
+			now:    saturday,
+			queued: 1,
+		},
+		{
+			name:     "bypassing window",
+			now:      saturday,
+			bypass:   []string{"repository_renamed"},
+			messages: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &mock.Database{Members: []string{}}
+			slack := &mock.Slack{}
+			team := github.Team{
+				Name: "test",
+				Sources: []github.Source{
+					{
+						SourceType: "commits",
+						Channel:    "#test",
+						Config: github.SourceConfig{
+							DeliveryWindow: &github.DeliveryWindow{
+								Timezone: "Europe/Oslo",
+								Days:     []string{"monday", "tuesday", "wednesday", "thursday", "friday"},
+								From:     "08:00",
+								To:       "16:00",
+								Bypass:   tt.bypass,
+							},
+						},
+					},
+				},
+			}
+			handler := NewHandler(db, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team})
+			handler.now = func() time.Time { return tt.now }
+
+			event, err := testdata.AsEvent("renamed-1.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := handler.handleSource(context.TODO(), slog.Default(), team, team.Sources[0], event); err != nil {
+				t.Fatal(err)
+			}
+
+			slack.EnsureMessages(t, event.GetEventType(), tt.messages)
+			if len(db.QueuedEvents) != tt.queued {
+				t.Errorf("expected %d queued events, got %d", tt.queued, len(db.QueuedEvents))
+			}
+		})
+	}
+}
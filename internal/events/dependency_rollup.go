@@ -0,0 +1,149 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// DependencyRollupEventID returns the ID the roll-up posted at the time is stored with.
+func DependencyRollupEventID(rolledUpAt time.Time) string {
+	return "dependency-rollup/" + strconv.FormatInt(rolledUpAt.UnixMicro(), 10)
+}
+
+func eventIsFromDependencyBot(event github.Event) bool {
+	if event.Sender.IsDependencyBot() {
+		return true
+	}
+
+	if event.PullRequest != nil && event.PullRequest.User.IsDependencyBot() {
+		return true
+	}
+
+	if event.IsCommit() {
+		for _, commit := range event.Commits {
+			if commit.Author.IsDependencyBot() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// rollUpDependencyEvent collects the dependency pull requests, and their
+// failing workflows, for the team's daily roll-up instead of posting them.
+// Other events from the bots are dropped, as when Dependabot is silenced.
+func (h *Handler) rollUpDependencyEvent(ctx context.Context, log *slog.Logger, team github.Team, event github.Event) {
+	var prs []gensql.DependencyPullRequest
+
+	switch event.GetEventType() {
+	case github.TypePullRequest:
+		if !event.PullRequest.User.IsDependencyBot() {
+			return
+		}
+
+		state := "open"
+		if event.PullRequest.Merged {
+			state = "merged"
+		} else if event.PullRequest.State == "closed" {
+			state = "closed"
+		}
+
+		pr, err := h.db.UpsertDependencyPullRequest(ctx, gensql.UpsertDependencyPullRequestParams{
+			TeamSlug:   team.Name,
+			Repository: event.GetRepositoryName(),
+			Number:     int32(event.PullRequest.Number), // #nosec G115 - pull request numbers fit in an int32
+			Title:      event.PullRequest.Title,
+			Url:        event.PullRequest.URL,
+			Ecosystem:  event.PullRequest.Ecosystem(),
+			State:      state,
+			// New commits get their checks run again
+			ResetCi: event.Action == "synchronize" || event.Action == "reopened",
+		})
+		if err != nil {
+			log.Error("Storing dependency pull request", "error", err, "number", event.PullRequest.Number)
+			return
+		}
+		prs = append(prs, pr)
+	case github.TypeWorkflow:
+		if event.Action != "completed" || event.Workflow.Conclusion != "failure" {
+			return
+		}
+
+		for _, workflowPR := range event.Workflow.PullRequests {
+			pr, err := h.db.MarkDependencyPullRequestCIFailed(ctx, gensql.MarkDependencyPullRequestCIFailedParams{
+				TeamSlug:   team.Name,
+				Repository: event.GetRepositoryName(),
+				Number:     int32(workflowPR.Number), // #nosec G115 - pull request numbers fit in an int32
+			})
+			if err != nil {
+				// Not a dependency pull request, or already failing
+				if !errors.Is(err, pgx.ErrNoRows) {
+					log.Error("Marking dependency pull request as failing", "error", err, "number", workflowPR.Number)
+				}
+				continue
+			}
+			prs = append(prs, pr)
+		}
+	default:
+		return
+	}
+
+	for _, pr := range prs {
+		if pr.RolledUpAt.Valid {
+			h.updateDependencyRollup(ctx, log, team, pr.RolledUpAt.Time)
+		}
+	}
+}
+
+// updateDependencyRollup edits the already posted roll-up in place, so it
+// shows the current status of its pull requests.
+func (h *Handler) updateDependencyRollup(ctx context.Context, log *slog.Logger, team github.Team, rolledUpAt time.Time) {
+	if team.DependencyRollup == nil {
+		return
+	}
+
+	id := DependencyRollupEventID(rolledUpAt)
+	messages, err := h.db.ListSlackMessagesByEvent(ctx, gensql.ListSlackMessagesByEventParams{
+		TeamSlug: team.Name,
+		EventID:  id,
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Error("Getting dependency roll-up", "error", err, "id", id)
+		}
+		return
+	}
+
+	prs, err := h.db.ListRolledUpDependencyPullRequests(ctx, gensql.ListRolledUpDependencyPullRequestsParams{
+		TeamSlug:   team.Name,
+		RolledUpAt: pgtype.Timestamptz{Time: rolledUpAt, Valid: true},
+	})
+	if err != nil {
+		log.Error("Listing dependency pull requests", "error", err, "id", id)
+		return
+	}
+
+	notifier := team.DependencyRollup.Notifier
+	for _, message := range messages {
+		updatedMessage := slack.Redact(slack.CreateDependencyRollupMessage(message.Channel, prs), team.Config.Redaction)
+		if team.UsesBlockKit(notifier) {
+			updatedMessage = slack.ToBlockKit(updatedMessage)
+		}
+		updatedMessage.Timestamp = message.ThreadTs
+
+		log.Info("Posting update of dependency roll-up", "channel", updatedMessage.Channel, "timestamp", updatedMessage.Timestamp)
+		if err := h.notifiers.Slack(team).PostUpdatedMessage(*updatedMessage); err != nil {
+			log.Error("Posting updated message", "error", err, "channel", updatedMessage.Channel, "timestamp", updatedMessage.Timestamp)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/mock"
+	"github.com/navikt/ghep/internal/sql/gensql"
+	"github.com/navikt/ghep/internal/testdata"
+)
+
+func TestDependencyRollup(t *testing.T) {
+	dependabot := github.User{Login: "dependabot[bot]", Type: "Bot"}
+
+	opened, err := testdata.AsEvent("pull-opened-1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := *opened.PullRequest
+	pr.User = dependabot
+	pr.Head = github.IssueBase{Ref: "dependabot/npm_and_yarn/vite-6.0.1"}
+	opened.PullRequest = &pr
+
+	failed, err := testdata.AsEvent("workflow-run-failure-1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	workflow := *failed.Workflow
+	workflow.PullRequests = []github.WorkflowPR{{Number: pr.Number}}
+	failed.Workflow = &workflow
+	failed.Repository = opened.Repository
+	failed.Sender = dependabot
+
+	merged, err := testdata.AsEvent("pull-closed-1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mergedPR := *merged.PullRequest
+	mergedPR.User = dependabot
+	merged.PullRequest = &mergedPR
+
+	team := github.Team{
+		Name:             "test",
+		Sources:          []github.Source{{SourceType: "pulls", Channel: "#pulls"}, {SourceType: "workflows", Channel: "#workflows"}},
+		Config:           github.Config{SilenceDependabot: github.DependabotConfigRollup},
+		DependencyRollup: &github.DependencyRollupConfig{Channel: "#dependencies", Time: "09:00"},
+	}
+
+	db := &mock.Database{}
+	slack := &mock.Slack{}
+	handler := NewHandler(db, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team})
+
+	if err := handler.Handle(context.TODO(), slog.Default(), team, opened); err != nil {
+		t.Fatal(err)
+	}
+	slack.EnsureMessages(t, github.TypePullRequest, 0)
+
+	if len(db.DependencyPullRequests) != 1 {
+		t.Fatalf("expected 1 dependency pull request, got %d", len(db.DependencyPullRequests))
+	}
+	if got := db.DependencyPullRequests[0]; got.Ecosystem != "npm_and_yarn" || got.State != "open" {
+		t.Errorf("expected open npm_and_yarn pull request, got %+v", got)
+	}
+
+	// Roll it up, as the scheduler does
+	rolledUpAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	db.DependencyPullRequests[0].RolledUpAt = pgtype.Timestamptz{Time: rolledUpAt, Valid: true}
+	db.SlackMessages = append(db.SlackMessages, gensql.CreateSlackMessageParams{
+		TeamSlug: team.Name,
+		EventID:  DependencyRollupEventID(rolledUpAt),
+		ThreadTs: "1234567890.123456",
+		Channel:  "C123",
+	})
+
+	if err := handler.Handle(context.TODO(), slog.Default(), team, failed); err != nil {
+		t.Fatal(err)
+	}
+	if !db.DependencyPullRequests[0].CiFailed {
+		t.Error("expected the pull request to be failing CI")
+	}
+	slack.Ensure(t, github.TypeWorkflow, 0, 0, 1)
+
+	if err := handler.Handle(context.TODO(), slog.Default(), team, merged); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.DependencyPullRequests[0].State; got != "merged" {
+		t.Errorf("expected the pull request to be merged, got %q", got)
+	}
+	slack.Ensure(t, github.TypePullRequest, 0, 0, 2)
+
+	// Only the posting is rolled up, the failed workflow and the merge are
+	// still recorded in the feed
+	if len(db.TeamEvents) != 2 {
+		t.Errorf("expected 2 team events, got %d", len(db.TeamEvents))
+	}
+}
+
+func TestEventIsFromDependencyBot(t *testing.T) {
+	tests := []struct {
+		name  string
+		event github.Event
+		want  bool
+	}{
+		{name: "dependabot", event: github.Event{Sender: github.User{Login: "dependabot[bot]"}}, want: true},
+		{name: "renovate", event: github.Event{Sender: github.User{Login: "renovate[bot]"}}, want: true},
+		{name: "merged by a person", event: github.Event{Sender: github.User{Login: "someone"}, PullRequest: &github.Issue{User: github.User{Login: "renovate[bot]"}}}, want: true},
+		{name: "person", event: github.Event{Sender: github.User{Login: "someone"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := eventIsFromDependencyBot(tt.event); got != tt.want {
+				t.Errorf("eventIsFromDependencyBot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
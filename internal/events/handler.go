@@ -7,6 +7,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -14,19 +15,79 @@ import (
 	"github.com/navikt/ghep/internal/slack"
 	"github.com/navikt/ghep/internal/sql"
 	"github.com/navikt/ghep/internal/sql/gensql"
+	"github.com/navikt/ghep/internal/webhook"
 )
 
 type Handler struct {
 	db          sql.Database
-	slack       slack.Slacker
+	github      github.Githubber
+	notifiers   slack.Notifiers
+	webhooks    webhook.Sender
 	teamsConfig map[string]github.Team
+	now         func() time.Time
+	// channelPrivacy caches whether channels are private, by workspace and channel.
+	channelPrivacy *sync.Map
 }
 
-func NewHandler(db sql.Database, slackClient slack.Slacker, teamsConfig map[string]github.Team) Handler {
+// NewHandler creates a handler posting to the Slack workspace or Microsoft
+// Teams channel, and the outgoing webhook, each source is configured with.
+func NewHandler(db sql.Database, githubClient github.Githubber, notifiers slack.Notifiers, webhooks webhook.Sender, teamsConfig map[string]github.Team) Handler {
 	return Handler{
-		db:          db,
-		slack:       slackClient,
-		teamsConfig: teamsConfig,
+		db:             db,
+		github:         githubClient,
+		notifiers:      notifiers,
+		webhooks:       webhooks,
+		teamsConfig:    teamsConfig,
+		now:            time.Now,
+		channelPrivacy: &sync.Map{},
+	}
+}
+
+// usesBlockKit returns true if messages to the channel are built with Block
+// Kit, as the team has opted in, and the channel is posted to through Slack.
+func usesBlockKit(team github.Team, channel string) bool {
+	return team.UsesBlockKit(team.NotifierForChannel(channel))
+}
+
+// renderFor redacts secrets from the message, and renders the messages not
+// built with Block Kit with it, when the channel uses Block Kit.
+func renderFor(team github.Team, channel string, message *slack.Message) *slack.Message {
+	message = slack.Redact(message, team.Config.Redaction)
+	if !usesBlockKit(team, channel) {
+		return message
+	}
+
+	return slack.ToBlockKit(message)
+}
+
+// activeMutes returns the team's mutes set from Slack or the admin API.
+// Events are handled as usual if the mutes can't be looked up.
+func (h *Handler) activeMutes(ctx context.Context, log *slog.Logger, team github.Team) []gensql.Mute {
+	mutes, err := h.db.ListActiveMutes(ctx, team.Name)
+	if err != nil {
+		log.Error("Listing mutes", "error", err)
+		return nil
+	}
+
+	return mutes
+}
+
+// mutedBy returns the mute covering events from the repository to a source of
+// the given type. An empty source type only matches mutes of all source types.
+func mutedBy(mutes []gensql.Mute, repository, sourceType string) (gensql.Mute, bool) {
+	for _, mute := range mutes {
+		if (mute.Repository == "" || mute.Repository == repository) && (mute.SourceType == "" || mute.SourceType == sourceType) {
+			return mute, true
+		}
+	}
+
+	return gensql.Mute{}, false
+}
+
+// countSuppressed counts the event, so it can be reported when the mute ends.
+func (h *Handler) countSuppressed(ctx context.Context, log *slog.Logger, mute gensql.Mute) {
+	if err := h.db.IncrementMuteSuppressed(ctx, mute.ID); err != nil {
+		log.Error("Counting suppressed event", "error", err, "mute_id", mute.ID)
 	}
 }
 
@@ -76,38 +137,93 @@ func (h *Handler) Handle(ctx context.Context, log *slog.Logger, team github.Team
 	case github.TypeRepositoryRenamed:
 		if err := h.db.UpdateRepository(ctx, gensql.UpdateRepositoryParams{
 			Name:    event.Repository.Name,
+			Org:     event.GetOrg(),
 			OldName: event.Changes.Repository.Name.From,
 		}); err != nil {
 			return err
 		}
 	case github.TypeTeam:
-		if err := h.handleTeamSideEffects(ctx, log, event); err != nil {
+		if err := h.handleTeamSideEffects(ctx, log, team, event); err != nil {
 			return err
 		}
 	case github.TypePullRequest:
 		if event.PullRequest.Merged {
 			event.Action = "merged"
 		}
+	case github.TypeCodeScanningAlert, github.TypeDependabotAlert, github.TypeSecretScanningAlert:
+		h.trackSecurityAlert(ctx, log, team, event)
+		h.storeSecurityAlert(ctx, log, event)
 	}
 
-	sources := team.SourcesForType(eventType)
-	for _, source := range sources {
-		if err := h.handleSource(ctx, log, team, source, event); err != nil {
-			log.Error("Handling source", "error", err, "source_type", source.SourceType, "channel", source.Channel)
+	h.recordTeamEvent(ctx, log, team, event)
+
+	// Rolled up events are posted in the daily roll-up instead of the sources
+	if team.Config.ShouldRollUpDependencies() && eventIsFromDependencyBot(event) {
+		h.rollUpDependencyEvent(ctx, log, team, event)
+		return nil
+	}
+
+	// Mutes only stop posting, the side effects above still apply
+	mutes := h.activeMutes(ctx, log, team)
+	suppressedBy := map[int64]bool{}
+	handleSources := func(sources []github.Source, eventType github.EventType) {
+		for _, source := range sources {
+			if mute, ok := mutedBy(mutes, event.GetRepositoryName(), source.SourceType); ok {
+				if !suppressedBy[mute.ID] {
+					h.countSuppressed(ctx, log, mute)
+					suppressedBy[mute.ID] = true
+				}
+				continue
+			}
+
+			if err := h.handleSourceAs(ctx, log, team, source, eventType, event); err != nil {
+				log.Error("Handling source", "error", err, "source_type", source.SourceType, "channel", source.Channel)
+			}
 		}
 	}
 
+	sources := team.SourcesForType(eventType)
+	handleSources(sources, eventType)
+
+	// A force push to the default branch is also posted to the team's security sources
+	if eventType == github.TypeCommit && event.IsForcePushToDefaultBranch() {
+		handleSources(team.SourcesForType(github.TypeForcePush), github.TypeForcePush)
+	}
+
+	// After the sources, so the alert's threads can be replied to
+	if eventType == github.TypeSecretScanningAlert && len(sources) > 0 {
+		h.notifySecretAuthor(ctx, log, team, event)
+	}
+
 	return nil
 }
 
 func (h *Handler) handleSource(ctx context.Context, log *slog.Logger, team github.Team, source github.Source, event github.Event) error {
+	return h.handleSourceAs(ctx, log, team, source, event.GetEventType(), event)
+}
+
+// handleSourceAs handles the event for the source as the given event type, as
+// some events, like force pushes, are handled as more than one type.
+func (h *Handler) handleSourceAs(ctx context.Context, log *slog.Logger, team github.Team, source github.Source, eventType github.EventType, event github.Event) error {
+	// Webhooks get the event as received from Github, so only once
+	if source.Webhook != "" && eventType == event.GetEventType() && matchesBranches(source, eventType, event) {
+		if err := h.webhooks.Send(log, team, source, event); err != nil {
+			log.Error("Sending event to webhook", "error", err, "webhook", source.Webhook)
+		}
+	}
+
 	if source.Channel == "" {
 		return nil
 	}
 
 	log = log.With("channel", source.Channel)
 
-	message, err := h.handleForSource(ctx, log, team, source, event)
+	event, ok := h.guardPrivateContent(log, team, source, eventType, event)
+	if !ok {
+		return nil
+	}
+
+	message, err := h.handleForSource(ctx, log, team, source, eventType, event)
 	if err != nil {
 		return err
 	}
@@ -116,26 +232,39 @@ func (h *Handler) handleSource(ctx context.Context, log *slog.Logger, team githu
 		return nil
 	}
 
+	message = renderFor(team, source.Channel, message)
 	payload, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
 
-	resp, err := h.slack.PostMessage(payload)
+	if h.isHeldBack(log, source, eventType, event) {
+		return h.queueMessage(ctx, log, team, source, eventType, event, message.Channel, payload)
+	}
+
+	resp, err := h.notifiers.Post(team, team.NotifierForChannel(source.Channel), source.SourceType, message)
 	if err != nil {
 		log.Error("Posting message", "error", err, "channel", message.Channel, "timestamp", message.ThreadTimestamp)
 		return err
 	}
 
-	if err := h.storeEvent(ctx, log, event, team, resp, payload); err != nil {
-		log.Error("Storing event", "error", err, "event_id", getEventID(event), "team", team.Name)
+	// Only Slack messages can be threaded, updated and reacted to
+	if resp == nil {
+		return nil
+	}
+
+	// Only the message for the event's own type is threaded
+	if eventType == event.GetEventType() {
+		if err := h.storeEvent(ctx, log, event, team, *resp, payload); err != nil {
+			log.Error("Storing event", "error", err, "event_id", getEventID(event), "team", team.Name)
+		}
 	}
 
 	// Update source channel name to ID if Slack returned a different channel identifier
 	if message.Channel != resp.Channel {
 		h.updateSourceChannelID(team, message.Channel, resp.Channel)
 
-		if err := h.slack.JoinChannel(resp.Channel); err != nil {
+		if err := h.notifiers.Slack(team).JoinChannel(resp.Channel); err != nil {
 			log.Error("Joining channel", "error", err, "channel", message.Channel, "channel_id", resp.Channel)
 		}
 	}
@@ -143,19 +272,14 @@ func (h *Handler) handleSource(ctx context.Context, log *slog.Logger, team githu
 	return nil
 }
 
-func (h *Handler) handleForSource(ctx context.Context, log *slog.Logger, team github.Team, source github.Source, event github.Event) (*slack.Message, error) {
-	eventType := event.GetEventType()
-
-	if len(source.Config.Branches) > 0 {
-		branch := eventBranch(event, eventType)
-		if branch != "" && !slices.Contains(source.Config.Branches, branch) {
-			return nil, nil
-		}
+func (h *Handler) handleForSource(ctx context.Context, log *slog.Logger, team github.Team, source github.Source, eventType github.EventType, event github.Event) (*slack.Message, error) {
+	if !matchesBranches(source, eventType, event) {
+		return nil, nil
 	}
 
 	switch eventType {
 	case github.TypeCommit:
-		return handleCommitEvent(ctx, log, source, event, h.db)
+		return handleCommitEvent(ctx, log, team, source, event, h.db)
 	case github.TypeCodeScanningAlert:
 		return h.handleCodeScanningAlertEvent(ctx, log, team, source, event)
 	case github.TypeDependabotAlert:
@@ -176,6 +300,10 @@ func (h *Handler) handleForSource(ctx context.Context, log *slog.Logger, team gi
 		return h.handleSecurityAdvisoryEvent(ctx, log, team, source, event)
 	case github.TypeSecretScanningAlert:
 		return h.handleSecretScanningAlertEvent(ctx, log, team, source, event)
+	case github.TypeRepositoryAdvisory:
+		return h.handleRepositoryAdvisoryEvent(ctx, log, team, source, event)
+	case github.TypeSecurityAndAnalysis, github.TypeBranchProtectionRule, github.TypeRepositoryRuleset, github.TypeForcePush:
+		return handleRegressionEvent(log, source, eventType, event), nil
 	case github.TypeTeam:
 		return handleTeamEvent(log, source.Channel, event)
 	case github.TypeWorkflow:
@@ -203,6 +331,9 @@ func getEventID(event github.Event) string {
 		return strconv.Itoa(event.Workflow.ID)
 	} else if event.Release != nil && event.Action == "published" {
 		return strconv.Itoa(event.Release.ID)
+	} else if event.RepositoryAdvisory != nil && (event.Action == "reported" || !event.RepositoryAdvisory.IsReport()) {
+		// Published reports are posted in the thread of the report
+		return event.RepositoryAdvisory.URL
 	}
 
 	return ""
@@ -249,11 +380,22 @@ func (h *Handler) updateSourceChannelID(team github.Team, oldChannel, newChannel
 	}
 }
 
+// matchesBranches reports whether the event is on one of the source's branches,
+// or has no branch context, or the source is not limited to any branches.
+func matchesBranches(source github.Source, eventType github.EventType, event github.Event) bool {
+	if len(source.Config.Branches) == 0 {
+		return true
+	}
+
+	branch := eventBranch(event, eventType)
+	return branch == "" || slices.Contains(source.Config.Branches, branch)
+}
+
 // eventBranch returns the branch associated with an event for a given event type.
 // Returns an empty string for event types that have no branch context.
 func eventBranch(event github.Event, eventType github.EventType) string {
 	switch eventType {
-	case github.TypeCommit:
+	case github.TypeCommit, github.TypeForcePush:
 		return strings.TrimPrefix(event.Ref, github.RefHeadsPrefix)
 	case github.TypeWorkflow:
 		if event.Workflow != nil {
@@ -0,0 +1,111 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// recordTeamEvent stores events worth following in the team's feed.
+func (h *Handler) recordTeamEvent(ctx context.Context, log *slog.Logger, team github.Team, event github.Event) {
+	if team.Name == github.TeamNameExternalContributors {
+		return
+	}
+
+	params, ok := teamEventFor(team, event)
+	if !ok {
+		return
+	}
+
+	if err := h.db.CreateTeamEvent(ctx, params); err != nil {
+		log.Error("Storing team event", "error", err, "team", team.Name)
+	}
+}
+
+// teamEventFor returns the feed entry for published releases, merged pull
+// requests, failed workflows and new security alerts. Feeds are read outside
+// of Slack, so security alerts and the bodies of releases and pull requests
+// are left out for repositories that are not public.
+func teamEventFor(team github.Team, event github.Event) (gensql.CreateTeamEventParams, bool) {
+	if event.Repository == nil {
+		return gensql.CreateTeamEventParams{}, false
+	}
+
+	public := event.Repository.IsPublic()
+
+	eventType := event.GetEventType()
+	repository := event.Repository.Name
+	params := gensql.CreateTeamEventParams{
+		TeamSlug:   team.Name,
+		Repository: repository,
+		EventType:  eventType.Name(),
+		Author:     event.Sender.Login,
+	}
+
+	switch eventType {
+	case github.TypeRelease:
+		if event.Action != "published" {
+			return params, false
+		}
+
+		name := event.Release.Name
+		if name == "" {
+			name = event.Release.Tag
+		}
+		params.Title = fmt.Sprintf("Release %s in %s", name, repository)
+		if public {
+			params.Summary = event.Release.Body
+		}
+		params.Url = event.Release.URL
+		params.Author = event.Release.User.Login
+	case github.TypePullRequest:
+		if event.Action != "merged" {
+			return params, false
+		}
+
+		params.Title = fmt.Sprintf("Pull request #%d merged in %s: %s", event.PullRequest.Number, repository, event.PullRequest.Title)
+		if public {
+			params.Summary = event.PullRequest.Body
+		}
+		params.Url = event.PullRequest.URL
+		params.Author = event.PullRequest.User.Login
+	case github.TypeWorkflow:
+		if event.Action != "completed" || event.Workflow.Conclusion != "failure" {
+			return params, false
+		}
+
+		params.Title = fmt.Sprintf("Workflow %s failed in %s", event.Workflow.Name, repository)
+		params.Summary = fmt.Sprintf("#%d %s on %s", event.Workflow.RunNumber, event.Workflow.Title, event.Workflow.HeadBranch)
+		params.Url = event.Workflow.URL
+	case github.TypeCodeScanningAlert:
+		if event.Action != "created" || !public {
+			return params, false
+		}
+
+		params.Title = fmt.Sprintf("Code scanning alert in %s: %s", repository, event.Alert.Rule.Description)
+		params.Summary = event.Alert.Rule.FullDescription
+		params.Url = event.Alert.URL
+	case github.TypeDependabotAlert:
+		if event.Action != "created" || !public {
+			return params, false
+		}
+
+		params.Title = fmt.Sprintf("Dependabot alert in %s: %s", repository, event.Alert.SecurityAdvisory.Summary)
+		params.Summary = event.Alert.SecurityAdvisory.Description
+		params.Url = event.Alert.URL
+	case github.TypeSecretScanningAlert:
+		if event.Action != "created" || !public {
+			return params, false
+		}
+
+		params.Title = fmt.Sprintf("Secret scanning alert in %s: %s", repository, *event.Alert.SecretType)
+		params.Url = event.Alert.URL
+	default:
+		return params, false
+	}
+
+	return params, true
+}
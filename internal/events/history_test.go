@@ -0,0 +1,162 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/mock"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+func TestTeamEventFor(t *testing.T) {
+	team := github.Team{Name: "nada"}
+	repository := &github.Repository{Name: "ghep"}
+	private := &github.Repository{Name: "secret", Private: true, Visibility: "private"}
+
+	tests := []struct {
+		name     string
+		event    github.Event
+		expected gensql.CreateTeamEventParams
+		ok       bool
+	}{
+		{
+			name: "published release",
+			event: github.Event{
+				Action:     "published",
+				Repository: repository,
+				Sender:     github.User{Login: "bot"},
+				Release: &github.Release{
+					Tag:  "v1.0.0",
+					Body: "First release",
+					URL:  "https://github.com/navikt/ghep/releases/v1.0.0",
+					User: github.User{Login: "Kyrremann"},
+				},
+			},
+			expected: gensql.CreateTeamEventParams{
+				TeamSlug:   "nada",
+				Repository: "ghep",
+				EventType:  "release",
+				Title:      "Release v1.0.0 in ghep",
+				Summary:    "First release",
+				Url:        "https://github.com/navikt/ghep/releases/v1.0.0",
+				Author:     "Kyrremann",
+			},
+			ok: true,
+		},
+		{
+			name: "merged pull request",
+			event: github.Event{
+				Action:     "merged",
+				Repository: repository,
+				PullRequest: &github.Issue{
+					Number: 42,
+					Title:  "Add feeds",
+					URL:    "https://github.com/navikt/ghep/pull/42",
+					User:   github.User{Login: "Kyrremann"},
+				},
+			},
+			expected: gensql.CreateTeamEventParams{
+				TeamSlug:   "nada",
+				Repository: "ghep",
+				EventType:  "pull_request",
+				Title:      "Pull request #42 merged in ghep: Add feeds",
+				Url:        "https://github.com/navikt/ghep/pull/42",
+				Author:     "Kyrremann",
+			},
+			ok: true,
+		},
+		{
+			name: "release in private repository without body",
+			event: github.Event{
+				Action:     "published",
+				Repository: private,
+				Release: &github.Release{
+					Tag:  "v1.0.0",
+					Body: "Fixes the leak in the billing service",
+					URL:  "https://github.com/navikt/secret/releases/v1.0.0",
+					User: github.User{Login: "Kyrremann"},
+				},
+			},
+			expected: gensql.CreateTeamEventParams{
+				TeamSlug:   "nada",
+				Repository: "secret",
+				EventType:  "release",
+				Title:      "Release v1.0.0 in secret",
+				Url:        "https://github.com/navikt/secret/releases/v1.0.0",
+				Author:     "Kyrremann",
+			},
+			ok: true,
+		},
+		{
+			name: "security alert in private repository is not recorded",
+			event: github.Event{
+				Action:     "created",
+				Repository: private,
+				Alert: &github.Alert{
+					URL:              "https://github.com/navikt/secret/security/dependabot/1",
+					SecurityAdvisory: &github.SecurityAdvisory{Summary: "Leaky dependency"},
+				},
+			},
+		},
+		{
+			name: "opened pull request is not recorded",
+			event: github.Event{
+				Action:      "opened",
+				Repository:  repository,
+				PullRequest: &github.Issue{Number: 42},
+			},
+		},
+		{
+			name: "successful workflow is not recorded",
+			event: github.Event{
+				Action:     "completed",
+				Repository: repository,
+				Workflow:   &github.Workflow{Conclusion: "success"},
+			},
+		},
+		{
+			name: "event without repository",
+			event: github.Event{
+				Action: "published",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, ok := teamEventFor(team, tt.event)
+			if ok != tt.ok {
+				t.Fatalf("expected ok to be %v, got %v", tt.ok, ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			if diff := cmp.Diff(tt.expected, params); diff != "" {
+				t.Errorf("teamEventFor() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecordTeamEventSkipsExternalContributors(t *testing.T) {
+	db := &mock.Database{}
+	handler := NewHandler(db, &mock.Github{}, (&mock.Slack{}).Notifiers(), &mock.Webhook{}, map[string]github.Team{})
+
+	event := github.Event{
+		Action:      "merged",
+		Repository:  &github.Repository{Name: "ghep"},
+		PullRequest: &github.Issue{Number: 1},
+	}
+
+	handler.recordTeamEvent(context.TODO(), slog.Default(), github.Team{Name: github.TeamNameExternalContributors}, event)
+	handler.recordTeamEvent(context.TODO(), slog.Default(), github.Team{Name: "nada"}, event)
+
+	if len(db.TeamEvents) != 1 || db.TeamEvents[0].TeamSlug != "nada" {
+		t.Errorf("expected one team event for nada, got %v", db.TeamEvents)
+	}
+}
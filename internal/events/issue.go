@@ -37,11 +37,12 @@ func (h *Handler) handleIssueEvent(ctx context.Context, log *slog.Logger, team g
 					log.Error("Unmarshalling message", "error", err)
 				}
 
-				updatedMessage := slack.CreateIssueMessage(ctx, log, h.db, oldMessage.Channel, timestamp, team.Config.PingSlackUsers, event)
+				updatedMessage := slack.CreateIssueMessage(ctx, log, h.db, oldMessage.Channel, timestamp, team.SlackWorkspace, team.Config.PingSlackUsers, usesBlockKit(team, oldMessage.Channel), event)
+				updatedMessage = renderFor(team, oldMessage.Channel, updatedMessage)
 				updatedMessage.Timestamp = timestamp
 
 				log.Info("Posting update of issue", "channel", updatedMessage.Channel, "timestamp", updatedMessage.Timestamp)
-				if err = h.slack.PostUpdatedMessage(*updatedMessage); err != nil {
+				if err = h.notifiers.Slack(team).PostUpdatedMessage(*updatedMessage); err != nil {
 					log.Error("Posting updated message", "error", err, "channel", updatedMessage.Channel, "timestamp", timestamp)
 				}
 
@@ -75,5 +76,5 @@ func handleIssueEvent(ctx context.Context, log *slog.Logger, db sql.Database, te
 	}
 
 	log.Info("Received issue", "channel", channel)
-	return slack.CreateIssueMessage(ctx, log, db, channel, threadTimestamp, team.Config.PingSlackUsers, event), nil
+	return slack.CreateIssueMessage(ctx, log, db, channel, threadTimestamp, team.SlackWorkspace, team.Config.PingSlackUsers, usesBlockKit(team, channel), event), nil
 }
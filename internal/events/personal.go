@@ -0,0 +1,273 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// mentionPattern matches @login, see mentions for what is not counted as a mention.
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)`)
+
+// PersonalNotifier sends real-time DMs about events concerning a user, to the
+// users who have opted in through teams.yaml or the /ghep command.
+type PersonalNotifier struct {
+	db        sql.Database
+	slack     map[string]slack.DirectMessenger
+	preferred map[string]github.PersonalNotifications
+	now       func() time.Time
+}
+
+// personalNotification is a DM to send to a Github user.
+type personalNotification struct {
+	login            string
+	notificationType string
+	message          func(channel string) *slack.Message
+}
+
+func NewPersonalNotifier(db sql.Database, workspaces map[string]slack.DirectMessenger, preferences []github.PersonalNotifications) *PersonalNotifier {
+	// Github logins are case-insensitive, so preferences are keyed by the lowercased login
+	preferred := make(map[string]github.PersonalNotifications, len(preferences))
+	for _, p := range preferences {
+		preferred[strings.ToLower(p.Login)] = p
+	}
+
+	return &PersonalNotifier{
+		db:        db,
+		slack:     workspaces,
+		preferred: preferred,
+		now:       time.Now,
+	}
+}
+
+// Preferences returns the user's preferences, where those set from Slack
+// override teams.yaml. It returns false if the user has not opted in.
+func (p *PersonalNotifier) Preferences(ctx context.Context, login string) (github.PersonalNotifications, bool, error) {
+	row, err := p.db.GetPersonalNotifications(ctx, login)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return github.PersonalNotifications{}, false, err
+		}
+
+		preferences, ok := p.preferred[strings.ToLower(login)]
+		return preferences, ok, nil
+	}
+
+	preferences := github.PersonalNotifications{
+		Login:          row.Login,
+		SlackWorkspace: row.SlackWorkspace,
+		Types:          row.Types,
+	}
+	if row.QuietFrom != "" && row.QuietTo != "" {
+		preferences.QuietHours = &github.QuietHours{From: row.QuietFrom, To: row.QuietTo, Timezone: row.Timezone}
+	}
+
+	return preferences, row.Enabled, nil
+}
+
+// Notify sends the DMs for the event, once per user for each webhook delivery,
+// so redeliveries from Github do not send them again.
+func (p *PersonalNotifier) Notify(ctx context.Context, log *slog.Logger, deliveryID string, event github.Event) {
+	for _, notification := range p.notificationsFor(ctx, log, event) {
+		log := log.With("login", notification.login, "notification_type", notification.notificationType)
+		if err := p.send(ctx, log, deliveryID, notification); err != nil {
+			log.Error("Sending personal notification", "error", err)
+		}
+	}
+}
+
+func (p *PersonalNotifier) send(ctx context.Context, log *slog.Logger, deliveryID string, notification personalNotification) error {
+	preferences, ok, err := p.Preferences(ctx, notification.login)
+	if err != nil {
+		return fmt.Errorf("getting preferences: %w", err)
+	}
+
+	if !ok || !preferences.Wants(notification.notificationType) {
+		return nil
+	}
+
+	if preferences.IsQuiet(p.now()) {
+		log.Debug("Skipping personal notification during quiet hours")
+		return nil
+	}
+
+	slackClient, ok := p.slack[preferences.SlackWorkspace]
+	if !ok {
+		return fmt.Errorf("unknown Slack workspace %q", preferences.SlackWorkspace)
+	}
+
+	slackID, err := p.db.GetUserSlackID(ctx, gensql.GetUserSlackIDParams{
+		Workspace: preferences.SlackWorkspace,
+		Login:     notification.login,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Debug("No Slack ID for user, skipping personal notification")
+			return nil
+		}
+		return fmt.Errorf("getting Slack ID: %w", err)
+	}
+
+	if slackID == "" {
+		return nil
+	}
+
+	claimed, err := p.db.ClaimPersonalNotification(ctx, gensql.ClaimPersonalNotificationParams{
+		DeliveryID: deliveryID,
+		Login:      notification.login,
+	})
+	if err != nil {
+		return fmt.Errorf("claiming personal notification: %w", err)
+	}
+
+	if claimed == 0 {
+		log.Debug("Personal notification already sent for delivery", "delivery_id", deliveryID)
+		return nil
+	}
+
+	if err := p.post(slackClient, slackID, notification); err != nil {
+		// Released, so a redelivery can try again
+		if err := p.db.ReleasePersonalNotification(ctx, gensql.ReleasePersonalNotificationParams{
+			DeliveryID: deliveryID,
+			Login:      notification.login,
+		}); err != nil {
+			log.Error("Releasing personal notification", "error", err)
+		}
+
+		return err
+	}
+
+	log.Info("Sent personal notification")
+	return nil
+}
+
+func (p *PersonalNotifier) post(slackClient slack.DirectMessenger, slackID string, notification personalNotification) error {
+	dmChannel, err := slackClient.OpenDM(slackID)
+	if err != nil {
+		return fmt.Errorf("opening DM: %w", err)
+	}
+
+	// Comments are redacted with the built-in patterns, as they are not posted for a team
+	payload, err := json.Marshal(slack.Redact(notification.message(dmChannel), github.Redaction{}))
+	if err != nil {
+		return err
+	}
+
+	if _, err := slackClient.PostMessage(payload); err != nil {
+		return fmt.Errorf("posting DM: %w", err)
+	}
+
+	return nil
+}
+
+// notificationsFor returns who to notify about the event.
+func (p *PersonalNotifier) notificationsFor(ctx context.Context, log *slog.Logger, event github.Event) []personalNotification {
+	var notifications []personalNotification
+	add := func(login, notificationType string, message func(channel string) *slack.Message) {
+		if login == "" || slices.ContainsFunc(notifications, func(n personalNotification) bool { return strings.EqualFold(n.login, login) }) {
+			return
+		}
+
+		notifications = append(notifications, personalNotification{login: login, notificationType: notificationType, message: message})
+	}
+
+	switch {
+	case event.Comment != nil && event.Action == "created" && (event.Issue != nil || event.PullRequest != nil):
+		for _, login := range mentions(event.Comment.Body) {
+			if strings.EqualFold(login, event.Comment.User.Login) {
+				continue
+			}
+
+			add(login, github.NotificationMentioned, func(channel string) *slack.Message {
+				return slack.CreateMentionedDM(channel, event)
+			})
+		}
+	case event.Review != nil && event.PullRequest != nil && event.Action == "submitted":
+		if event.Review.State != "approved" && event.Review.State != "changes_requested" {
+			return nil
+		}
+
+		if strings.EqualFold(event.PullRequest.User.Login, event.Review.User.Login) {
+			return nil
+		}
+
+		add(event.PullRequest.User.Login, github.NotificationReviewed, func(channel string) *slack.Message {
+			return slack.CreateReviewedDM(channel, event)
+		})
+	case event.PullRequest != nil && event.Action == "review_requested" && event.RequestedReviewer != nil:
+		add(event.RequestedReviewer.Login, github.NotificationReviewRequested, func(channel string) *slack.Message {
+			return slack.CreateReviewRequestedDM(channel, event)
+		})
+	case event.Workflow != nil && event.Action == "completed" && event.Workflow.Conclusion == "failure" && event.Workflow.HeadCommit != nil:
+		login, err := p.commitAuthor(ctx, event.Workflow.HeadCommit.Author)
+		if err != nil {
+			log.Error("Finding commit author", "error", err)
+			return nil
+		}
+
+		add(login, github.NotificationWorkflowFailed, func(channel string) *slack.Message {
+			return slack.CreateWorkflowFailedDM(channel, event)
+		})
+	}
+
+	return notifications
+}
+
+// commitAuthor returns the Github login of a commit author, from their email.
+func (p *PersonalNotifier) commitAuthor(ctx context.Context, author github.Author) (string, error) {
+	if author.Username != "" {
+		return author.Username, nil
+	}
+
+	if author.Email == "" {
+		return "", nil
+	}
+
+	login, err := p.db.GetUserByEmail(ctx, author.Email)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+
+	return login, nil
+}
+
+// mentions returns the logins mentioned in a comment, in order and without
+// duplicates. Email addresses and team mentions like @org/team are skipped.
+func mentions(body string) []string {
+	var logins []string
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(body, -1) {
+		start, end := match[0], match[1]
+		if start > 0 && isMentionPrefix(body[start-1]) {
+			continue
+		}
+		if end < len(body) && (body[end] == '/' || body[end] == '-' || body[end] == '@') {
+			continue
+		}
+
+		login := body[match[2]:match[3]]
+		if !slices.Contains(logins, login) {
+			logins = append(logins, login)
+		}
+	}
+
+	return logins
+}
+
+// isMentionPrefix returns true if the character can't come right before a
+// mention, as in email addresses and paths.
+func isMentionPrefix(c byte) bool {
+	return c == '/' || c == '@' || c == '_' || c == '`' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
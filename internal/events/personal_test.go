@@ -0,0 +1,218 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/mock"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []string
+	}{
+		{
+			name:     "single mention",
+			body:     "@Kyrremann can you take a look?",
+			expected: []string{"Kyrremann"},
+		},
+		{
+			name:     "duplicates and punctuation",
+			body:     "Thanks @androa, @thokra-nav. And @androa again!",
+			expected: []string{"androa", "thokra-nav"},
+		},
+		{
+			name: "email and team mentions",
+			body: "Mail kyrre@nav.no or ping @navikt/nada",
+		},
+		{
+			name: "code",
+			body: "Use `@latest` and uses: actions/checkout@v4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, mentions(tt.body)); diff != "" {
+				t.Errorf("mentions() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPersonalNotifierNotify(t *testing.T) {
+	repository := &github.Repository{Name: "ghep"}
+	pullRequest := &github.Issue{Number: 1, Title: "Add DMs", User: github.User{Login: "Kyrremann"}}
+	noon := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		event       github.Event
+		preferences []github.PersonalNotifications
+		stored      map[string]gensql.PersonalNotification
+		now         time.Time
+		messages    int
+	}{
+		{
+			name: "review requested",
+			event: github.Event{
+				Action:            "review_requested",
+				Repository:        repository,
+				PullRequest:       pullRequest,
+				RequestedReviewer: &github.User{Login: "Kyrremann"},
+				Sender:            github.User{Login: "androa"},
+			},
+			preferences: []github.PersonalNotifications{{Login: "Kyrremann"}},
+			messages:    1,
+		},
+		{
+			name: "review requested with login in other case",
+			event: github.Event{
+				Action:            "review_requested",
+				Repository:        repository,
+				PullRequest:       pullRequest,
+				RequestedReviewer: &github.User{Login: "Kyrremann"},
+				Sender:            github.User{Login: "androa"},
+			},
+			preferences: []github.PersonalNotifications{{Login: "kyrremann"}},
+			messages:    1,
+		},
+		{
+			name: "review requested without opting in",
+			event: github.Event{
+				Action:            "review_requested",
+				Repository:        repository,
+				PullRequest:       pullRequest,
+				RequestedReviewer: &github.User{Login: "Kyrremann"},
+				Sender:            github.User{Login: "androa"},
+			},
+		},
+		{
+			name: "approved",
+			event: github.Event{
+				Action:      "submitted",
+				Repository:  repository,
+				PullRequest: pullRequest,
+				Review:      &github.Review{State: "approved", User: github.User{Login: "androa"}},
+			},
+			preferences: []github.PersonalNotifications{{Login: "Kyrremann", Types: []string{github.NotificationReviewed}}},
+			messages:    1,
+		},
+		{
+			name: "commented review",
+			event: github.Event{
+				Action:      "submitted",
+				Repository:  repository,
+				PullRequest: pullRequest,
+				Review:      &github.Review{State: "commented", User: github.User{Login: "androa"}},
+			},
+			preferences: []github.PersonalNotifications{{Login: "Kyrremann"}},
+		},
+		{
+			name: "type turned off",
+			event: github.Event{
+				Action:      "submitted",
+				Repository:  repository,
+				PullRequest: pullRequest,
+				Review:      &github.Review{State: "approved", User: github.User{Login: "androa"}},
+			},
+			preferences: []github.PersonalNotifications{{Login: "Kyrremann", Types: []string{github.NotificationMentioned}}},
+		},
+		{
+			name: "workflow failed on commit",
+			event: github.Event{
+				Action:     "completed",
+				Repository: repository,
+				Workflow: &github.Workflow{
+					Name:       "build",
+					Conclusion: "failure",
+					HeadSHA:    "0123456789abcdef",
+					HeadBranch: "main",
+					HeadCommit: &github.HeadCommit{ID: "0123456789abcdef", Author: github.Author{Email: "kyrre.havik@nav.no"}},
+				},
+			},
+			preferences: []github.PersonalNotifications{{Login: "Kyrremann"}},
+			messages:    1,
+		},
+		{
+			name: "mentioned by someone else",
+			event: github.Event{
+				Action:     "created",
+				Repository: repository,
+				Issue:      &github.Issue{Number: 2, Title: "Bug"},
+				Comment:    &github.Comment{Body: "@Kyrremann and @androa, thoughts?", User: github.User{Login: "thokra-nav"}},
+			},
+			preferences: []github.PersonalNotifications{{Login: "Kyrremann"}},
+			messages:    1,
+		},
+		{
+			name: "mentioned during quiet hours",
+			event: github.Event{
+				Action:     "created",
+				Repository: repository,
+				Issue:      &github.Issue{Number: 2, Title: "Bug"},
+				Comment:    &github.Comment{Body: "@Kyrremann thoughts?", User: github.User{Login: "thokra-nav"}},
+			},
+			preferences: []github.PersonalNotifications{{Login: "Kyrremann", QuietHours: &github.QuietHours{From: "22:00", To: "07:00", Timezone: "UTC"}}},
+			now:         time.Date(2026, 10, 19, 23, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "turned off from Slack",
+			event: github.Event{
+				Action:            "review_requested",
+				Repository:        repository,
+				PullRequest:       pullRequest,
+				RequestedReviewer: &github.User{Login: "Kyrremann"},
+				Sender:            github.User{Login: "androa"},
+			},
+			preferences: []github.PersonalNotifications{{Login: "Kyrremann"}},
+			stored:      map[string]gensql.PersonalNotification{"Kyrremann": {Login: "Kyrremann", Enabled: false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slackClient := &mock.Slack{}
+			notifier := NewPersonalNotifier(&mock.Database{PersonalNotifications: tt.stored}, map[string]slack.DirectMessenger{"": slackClient}, tt.preferences)
+			notifier.now = func() time.Time {
+				if tt.now.IsZero() {
+					return noon
+				}
+				return tt.now
+			}
+
+			notifier.Notify(context.Background(), slog.New(slog.DiscardHandler), "delivery", tt.event)
+			slackClient.EnsureMessages(t, tt.event.GetEventType(), tt.messages)
+		})
+	}
+}
+
+func TestPersonalNotifierNotifyOncePerDelivery(t *testing.T) {
+	event := github.Event{
+		Action:            "review_requested",
+		Repository:        &github.Repository{Name: "ghep"},
+		PullRequest:       &github.Issue{Number: 1, Title: "Add DMs", User: github.User{Login: "androa"}},
+		RequestedReviewer: &github.User{Login: "Kyrremann"},
+		Sender:            github.User{Login: "androa"},
+	}
+
+	slackClient := &mock.Slack{}
+	notifier := NewPersonalNotifier(&mock.Database{}, map[string]slack.DirectMessenger{"": slackClient}, []github.PersonalNotifications{{Login: "Kyrremann"}})
+	notifier.now = func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) }
+
+	log := slog.New(slog.DiscardHandler)
+	notifier.Notify(context.Background(), log, "delivery", event)
+	notifier.Notify(context.Background(), log, "delivery", event)
+	slackClient.EnsureMessages(t, event.GetEventType(), 1)
+
+	notifier.Notify(context.Background(), log, "other-delivery", event)
+	slackClient.EnsureMessages(t, event.GetEventType(), 2)
+}
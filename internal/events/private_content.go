@@ -0,0 +1,78 @@
+package events
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+)
+
+// channelPrivacyMaxAge is how long a channel's privacy is remembered, as
+// channels can be converted to private.
+const channelPrivacyMaxAge = time.Hour
+
+type channelPrivacy struct {
+	private   bool
+	checkedAt time.Time
+}
+
+// isPublicChannel returns true if anyone in the workspace can read the channel.
+// Channels that can't be looked up are treated as public, while channels posted
+// to by notifiers that can't tell, like Microsoft Teams, are not.
+func (h *Handler) isPublicChannel(log *slog.Logger, team github.Team, channel string) bool {
+	inspector, ok := h.notifiers.Slack(team).(slack.ChannelInspector)
+	if !ok {
+		return false
+	}
+
+	key := team.SlackWorkspace + "/" + channel
+	if h.channelPrivacy != nil {
+		if cached, ok := h.channelPrivacy.Load(key); ok && h.now().Sub(cached.(channelPrivacy).checkedAt) < channelPrivacyMaxAge {
+			return !cached.(channelPrivacy).private
+		}
+	}
+
+	private, err := inspector.IsPrivateChannel(channel)
+	if err != nil {
+		log.Error("Looking up channel privacy, treating it as public", "error", err, "channel", channel)
+		return true
+	}
+
+	if h.channelPrivacy != nil {
+		h.channelPrivacy.Store(key, channelPrivacy{private: private, checkedAt: h.now()})
+	}
+
+	return !private
+}
+
+// guardPrivateContent applies the team's policy for posting events from private
+// and internal repositories to public channels. It returns false if the event
+// should not be posted at all.
+func (h *Handler) guardPrivateContent(log *slog.Logger, team github.Team, source github.Source, eventType github.EventType, event github.Event) (github.Event, bool) {
+	policy := team.Config.PrivateContent
+	if policy == "" || policy == github.PrivateContentFull || !event.HasPrivateContent() {
+		return event, true
+	}
+
+	channels := []string{source.Channel}
+	// Issues and pull requests from external contributors are posted to their own channel
+	if (eventType == github.TypeIssue || eventType == github.TypePullRequest) && team.Config.ExternalContributorsChannel != "" {
+		channels = append(channels, team.Config.ExternalContributorsChannel)
+	}
+
+	for _, channel := range channels {
+		if !h.isPublicChannel(log, team, channel) {
+			continue
+		}
+
+		log.Info("Guarding content from private repository in public channel", "policy", policy, "public_channel", channel)
+		if policy == github.PrivateContentBlock {
+			return event, false
+		}
+
+		return event.WithTitlesOnly(), true
+	}
+
+	return event, true
+}
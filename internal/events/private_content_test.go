@@ -0,0 +1,116 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/mock"
+	"github.com/navikt/ghep/internal/testdata"
+)
+
+func TestGuardPrivateContent(t *testing.T) {
+	internal, err := testdata.AsEvent("issue-opened-1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	public := internal
+	repository := *internal.Repository
+	repository.Visibility = "public"
+	repository.Private = false
+	public.Repository = &repository
+
+	tests := []struct {
+		name     string
+		policy   github.PrivateContentPolicy
+		channel  string
+		event    github.Event
+		posted   bool
+		withBody bool
+	}{
+		{
+			name:     "no policy",
+			channel:  "#public",
+			event:    internal,
+			posted:   true,
+			withBody: true,
+		},
+		{
+			name:     "titles only in public channel",
+			policy:   github.PrivateContentTitle,
+			channel:  "#public",
+			event:    internal,
+			posted:   true,
+			withBody: false,
+		},
+		{
+			name:     "titles only in private channel",
+			policy:   github.PrivateContentTitle,
+			channel:  "#private",
+			event:    internal,
+			posted:   true,
+			withBody: true,
+		},
+		{
+			name:     "titles only from public repository",
+			policy:   github.PrivateContentTitle,
+			channel:  "#public",
+			event:    public,
+			posted:   true,
+			withBody: true,
+		},
+		{
+			name:    "blocked in public channel",
+			policy:  github.PrivateContentBlock,
+			channel: "#public",
+			event:   internal,
+			posted:  false,
+		},
+		{
+			name:     "blocked in private channel",
+			policy:   github.PrivateContentBlock,
+			channel:  "#private",
+			event:    internal,
+			posted:   true,
+			withBody: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			team := github.Team{
+				Name:    "test",
+				Sources: []github.Source{{SourceType: "issues", Channel: tt.channel}},
+				Config:  github.Config{PrivateContent: tt.policy},
+			}
+
+			slack := &mock.Slack{PrivateChannels: []string{"#private"}}
+			handler := NewHandler(&mock.Database{}, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team})
+
+			guarded, posted := handler.guardPrivateContent(slog.Default(), team, team.Sources[0], github.TypeIssue, tt.event)
+			if posted != tt.posted {
+				t.Errorf("guardPrivateContent() posted = %v, want %v", posted, tt.posted)
+			}
+
+			if posted && (guarded.Issue.Body != "") != tt.withBody {
+				t.Errorf("expected body to be kept: %v, got %q", tt.withBody, guarded.Issue.Body)
+			}
+
+			if tt.event.Issue.Body == "" {
+				t.Error("expected the original event to keep its body")
+			}
+
+			if err := handler.handleSource(context.TODO(), slog.Default(), team, team.Sources[0], tt.event); err != nil {
+				t.Fatal(err)
+			}
+
+			expected := 0
+			if tt.posted {
+				expected = 1
+			}
+			slack.EnsureMessages(t, github.TypeIssue, expected)
+		})
+	}
+}
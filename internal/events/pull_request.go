@@ -37,11 +37,12 @@ func (h *Handler) handlePullRequestEvent(ctx context.Context, log *slog.Logger,
 					log.Error("Unmarshalling message", "error", err)
 				}
 
-				updatedMessage := slack.CreatePullRequestMessage(ctx, log, h.db, oldMessage.Channel, timestamp, team.Config.PingSlackUsers, source.Config.Pulls.Minimalist, event)
+				updatedMessage := slack.CreatePullRequestMessage(ctx, log, h.db, oldMessage.Channel, timestamp, team.SlackWorkspace, team.Config.PingSlackUsers, source.Config.Pulls.Minimalist, usesBlockKit(team, oldMessage.Channel), event)
+				updatedMessage = renderFor(team, oldMessage.Channel, updatedMessage)
 				updatedMessage.Timestamp = timestamp
 
 				log.Info("Posting update of pull request", "channel", updatedMessage.Channel, "timestamp", updatedMessage.Timestamp)
-				if err = h.slack.PostUpdatedMessage(*updatedMessage); err != nil {
+				if err = h.notifiers.Slack(team).PostUpdatedMessage(*updatedMessage); err != nil {
 					log.Error("Posting updated message", "error", err)
 				}
 
@@ -93,5 +94,5 @@ func handlePullRequestEvent(ctx context.Context, log *slog.Logger, db sql.Databa
 	}
 
 	log.Info("Received pull request", "channel", channel)
-	return slack.CreatePullRequestMessage(ctx, log, db, channel, threadTimestamp, team.Config.PingSlackUsers, source.Config.Pulls.Minimalist, event), nil
+	return slack.CreatePullRequestMessage(ctx, log, db, channel, threadTimestamp, team.SlackWorkspace, team.Config.PingSlackUsers, source.Config.Pulls.Minimalist, usesBlockKit(team, channel), event), nil
 }
@@ -27,7 +27,7 @@ func (h *Handler) handlePullRequestReviewEvent(ctx context.Context, log *slog.Lo
 	}
 
 	for _, pullRequest := range pullRequests {
-		h.slack.PostPullRequestReaction(log, event.Review.State, pullRequest.Channel, pullRequest.ThreadTs)
+		h.notifiers.Slack(team).PostPullRequestReaction(log, event.Review.State, pullRequest.Channel, pullRequest.ThreadTs)
 	}
 
 	return nil, nil
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/mock"
 )
@@ -184,3 +185,47 @@ func TestHandlePullRequestEventsFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlePullRequestWithBlockKit(t *testing.T) {
+	team := github.Team{Name: "test", Config: github.Config{BlockKit: true}}
+	source := github.Source{SourceType: "pulls", Channel: "#test"}
+	db := &mock.Database{Members: []string{"human"}}
+
+	event := github.Event{
+		Action: "opened",
+		Sender: github.User{Login: "human", Type: "User"},
+		PullRequest: &github.Issue{
+			Number:             1,
+			Title:              "Add Block Kit",
+			URL:                "https://github.com/navikt/ghep/pull/1",
+			Body:               "Builds the messages with blocks",
+			User:               github.User{Login: "human", Type: "User"},
+			RequestedReviewers: []github.User{{Login: "Kyrremann"}},
+		},
+		Repository: &github.Repository{Name: "ghep", FullName: "navikt/ghep", URL: "https://github.com/navikt/ghep"},
+	}
+
+	msg, err := handlePullRequestEvent(context.Background(), slog.Default(), db, team, source, "", event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(msg.Attachments) > 0 {
+		t.Errorf("expected no attachments, got %+v", msg.Attachments)
+	}
+
+	var types []string
+	for _, block := range msg.Blocks {
+		types = append(types, block.Type)
+	}
+	if diff := cmp.Diff([]string{"section", "section", "context", "context"}, types); diff != "" {
+		t.Errorf("block types mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := msg.Blocks[2].Elements[0].Text; got != "*Requested reviewers:* @Kyrremann" {
+		t.Errorf("unexpected reviewers %q", got)
+	}
+	if got := msg.Blocks[3].BlockID; got != "footer-0" {
+		t.Errorf("expected the repository in the footer, got block %q", got)
+	}
+}
@@ -0,0 +1,20 @@
+package events
+
+import (
+	"log/slog"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+)
+
+// handleRegressionEvent posts when someone turns off protections of one of the
+// team's repositories, like secret scanning or the default branch's protection.
+func handleRegressionEvent(log *slog.Logger, source github.Source, eventType github.EventType, event github.Event) *slack.Message {
+	severity, ok := event.RegressionSeverity(eventType)
+	if !ok || severity < source.Config.Security.SeverityType() {
+		return nil
+	}
+
+	log.Info("Posting security regression message", "severity", severity.Name())
+	return slack.CreateRegressionMessage(source.Channel, eventType, severity, event)
+}
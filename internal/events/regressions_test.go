@@ -0,0 +1,71 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/mock"
+	"github.com/navikt/ghep/internal/testdata"
+)
+
+func TestRegressions(t *testing.T) {
+	t.Run("force push to the default branch", func(t *testing.T) {
+		event, err := testdata.AsEvent("commit-1.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		event.Forced = true
+
+		team := github.Team{
+			Name:    "test",
+			Sources: []github.Source{{SourceType: "security", Channel: "#security"}},
+		}
+
+		slack := &mock.Slack{}
+		handler := NewHandler(&mock.Database{}, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team})
+		if err := handler.handleSourceAs(context.TODO(), slog.Default(), team, team.Sources[0], github.TypeForcePush, event); err != nil {
+			t.Fatal(err)
+		}
+
+		slack.EnsureMessages(t, github.TypeForcePush, 1)
+
+		event.Ref = github.RefHeadsPrefix + "feature"
+		if err := handler.handleSourceAs(context.TODO(), slog.Default(), team, team.Sources[0], github.TypeForcePush, event); err != nil {
+			t.Fatal(err)
+		}
+
+		slack.EnsureMessages(t, github.TypeForcePush, 1)
+	})
+
+	t.Run("severity filter", func(t *testing.T) {
+		event, err := testdata.AsEvent("branch-protection-rule-deleted-1.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		source := github.Source{SourceType: "security", Channel: "#security"}
+		if message := handleRegressionEvent(slog.Default(), source, github.TypeBranchProtectionRule, event); message == nil {
+			t.Error("expected deleted branch protection to be posted")
+		}
+
+		source.Config.Security.SeverityFilter = "critical"
+		if message := handleRegressionEvent(slog.Default(), source, github.TypeBranchProtectionRule, event); message != nil {
+			t.Errorf("expected high severity regression to be filtered, got %+v", message)
+		}
+	})
+
+	t.Run("rule for other branch", func(t *testing.T) {
+		event, err := testdata.AsEvent("branch-protection-rule-deleted-1.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		event.BranchProtectionRule.Name = "release/*"
+
+		source := github.Source{SourceType: "security", Channel: "#security"}
+		if message := handleRegressionEvent(slog.Default(), source, github.TypeBranchProtectionRule, event); message != nil {
+			t.Errorf("expected rule for other branches to be ignored, got %+v", message)
+		}
+	})
+}
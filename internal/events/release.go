@@ -29,25 +29,25 @@ func (h *Handler) handleReleaseEvent(ctx context.Context, log *slog.Logger, team
 			return nil, nil
 		}
 
-		updatedMessage := slack.CreateReleaseMessage(source.Channel, event)
+		updatedMessage := renderFor(team, source.Channel, slack.CreateReleaseMessage(source.Channel, usesBlockKit(team, source.Channel), event))
 		updatedMessage.Timestamp = message.ThreadTs
 
 		log.Info("Posting update of release", "channel", updatedMessage.Channel, "timestamp", updatedMessage.Timestamp)
-		if err = h.slack.PostUpdatedMessage(*updatedMessage); err != nil {
+		if err = h.notifiers.Slack(team).PostUpdatedMessage(*updatedMessage); err != nil {
 			log.Error("Posting updated message", "error", err, "channel", updatedMessage.Channel, "timestamp", updatedMessage.Timestamp)
 		}
 
 		return nil, nil
 	}
 
-	return handleReleaseEvent(log, source, event)
+	return handleReleaseEvent(log, team, source, event)
 }
 
-func handleReleaseEvent(log *slog.Logger, source github.Source, event github.Event) (*slack.Message, error) {
+func handleReleaseEvent(log *slog.Logger, team github.Team, source github.Source, event github.Event) (*slack.Message, error) {
 	if !slices.Contains([]string{"published"}, event.Action) {
 		return nil, nil
 	}
 
 	log.Info("Received release", "channel", source.Channel)
-	return slack.CreateReleaseMessage(source.Channel, event), nil
+	return slack.CreateReleaseMessage(source.Channel, usesBlockKit(team, source.Channel), event), nil
 }
@@ -24,7 +24,7 @@ func TestHandleRename(t *testing.T) {
 			},
 		},
 	}
-	handler := NewHandler(db, slack, map[string]github.Team{"test": team})
+	handler := NewHandler(db, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team})
 
 	t.Run("Simple rename event", func(t *testing.T) {
 		event, err := testdata.AsEvent("renamed-1.json")
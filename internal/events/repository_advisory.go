@@ -0,0 +1,32 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+)
+
+func (h *Handler) handleRepositoryAdvisoryEvent(ctx context.Context, log *slog.Logger, team github.Team, source github.Source, event github.Event) (*slack.Message, error) {
+	if !slices.Contains([]string{"reported", "published"}, event.Action) {
+		return nil, nil
+	}
+
+	advisory := event.RepositoryAdvisory
+
+	// Reporters don't have to set a severity, and those reports are always posted
+	if advisory.Severity != "" && source.Config.Security.IgnoreThreshold(advisory.Severity) {
+		return nil, nil
+	}
+
+	channel := source.Channel
+	var timestamp string
+	if event.Action == "published" && advisory.IsReport() {
+		channel, timestamp = h.securityThread(ctx, log, team, source, advisory.URL, event.Action)
+	}
+
+	log.Info("Received repository advisory", "ghsa_id", advisory.GHSAID, "severity", advisory.Severity)
+	return slack.CreateRepositoryAdvisoryMessage(channel, timestamp, usesBlockKit(team, channel), event), nil
+}
@@ -0,0 +1,82 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/mock"
+	"github.com/navikt/ghep/internal/sql/gensql"
+	"github.com/navikt/ghep/internal/testdata"
+)
+
+func TestRepositoryAdvisory(t *testing.T) {
+	reported, err := testdata.AsEvent("repository-advisory-reported-1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	team := github.Team{
+		Name:    "test",
+		Sources: []github.Source{{SourceType: "security", Channel: "#security"}},
+	}
+
+	t.Run("severity filter", func(t *testing.T) {
+		source := github.Source{SourceType: "security", Channel: "#security"}
+		source.Config.Security.SeverityFilter = "critical"
+
+		handler := NewHandler(&mock.Database{}, &mock.Github{}, (&mock.Slack{}).Notifiers(), &mock.Webhook{}, map[string]github.Team{})
+		message, err := handler.handleRepositoryAdvisoryEvent(context.TODO(), slog.Default(), team, source, reported)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if message != nil {
+			t.Errorf("expected high severity report to be filtered, got %+v", message)
+		}
+
+		unrated := reported
+		advisory := *reported.RepositoryAdvisory
+		advisory.Severity = ""
+		unrated.RepositoryAdvisory = &advisory
+
+		message, err = handler.handleRepositoryAdvisoryEvent(context.TODO(), slog.Default(), team, source, unrated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if message == nil {
+			t.Error("expected report without severity to be posted")
+		}
+	})
+
+	t.Run("published report is threaded", func(t *testing.T) {
+		db := &mock.Database{}
+		if err := db.CreateSlackMessage(context.TODO(), gensql.CreateSlackMessageParams{
+			TeamSlug: team.Name,
+			EventID:  getEventID(reported),
+			ThreadTs: "1719220032.123456",
+			Channel:  "C123",
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		slack := &mock.Slack{}
+		handler := NewHandler(db, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team})
+
+		published := reported
+		published.Action = "published"
+		if getEventID(published) != "" {
+			t.Errorf("expected published report not to replace the report's thread, got event ID %q", getEventID(published))
+		}
+
+		message, err := handler.handleRepositoryAdvisoryEvent(context.TODO(), slog.Default(), team, team.Sources[0], published)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if message.Channel != "C123" || message.ThreadTimestamp != "1719220032.123456" {
+			t.Errorf("expected message in the report's thread, got channel %q and thread %q", message.Channel, message.ThreadTimestamp)
+		}
+		slack.EnsureReactions(t, github.TypeRepositoryAdvisory, 1)
+	})
+}
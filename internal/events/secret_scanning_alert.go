@@ -2,7 +2,9 @@ package events
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"slices"
 
@@ -27,5 +29,123 @@ func (h *Handler) handleSecretScanningAlertEvent(ctx context.Context, log *slog.
 	}
 
 	log.Info("Received secret scanning alert", "secret_type", event.Alert.SecretType)
-	return slack.CreateSecretScanningAlertMessage(source.Channel, message.ThreadTs, event), nil
+	return slack.CreateSecretScanningAlertMessage(source.Channel, message.ThreadTs, usesBlockKit(team, source.Channel), event), nil
+}
+
+// notifySecretAuthor sends whoever committed the secret a DM with how to
+// remediate it, once per alert, and notes it in the team's alert threads.
+func (h *Handler) notifySecretAuthor(ctx context.Context, log *slog.Logger, team github.Team, event github.Event) {
+	if event.Action != "created" || event.Repository == nil {
+		return
+	}
+
+	location, ok, err := h.github.FindSecretLocation(ctx, team.Org, event.Repository.Name, event.Alert.Number)
+	if err != nil {
+		log.Error("Finding secret location", "error", err, "alert", event.Alert.URL)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	login := location.AuthorLogin
+	if login == "" && location.AuthorEmail != "" {
+		login, err = h.db.GetUserByEmail(ctx, location.AuthorEmail)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			log.Error("Getting user by email", "error", err)
+			return
+		}
+	}
+	if login == "" {
+		log.Info("No Github user for secret author, skipping DM", "alert", event.Alert.URL)
+		return
+	}
+
+	log = log.With("login", login)
+
+	slackID, err := h.db.GetUserSlackID(ctx, gensql.GetUserSlackIDParams{
+		Workspace: team.SlackWorkspace,
+		Login:     login,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Error("Getting Slack ID", "error", err)
+		return
+	}
+	if slackID == "" {
+		log.Info("No Slack ID for secret author, skipping DM")
+		return
+	}
+
+	messenger, ok := h.notifiers.Slack(team).(slack.DirectMessenger)
+	if !ok {
+		return
+	}
+
+	// Claimed per alert and team, so redeliveries are skipped, and only the
+	// first team sends the DM while the others note it in their threads
+	claim := gensql.ClaimSecretAuthorNotificationParams{
+		AlertUrl: event.Alert.URL,
+		TeamSlug: team.Name,
+	}
+	first, err := h.db.ClaimSecretAuthorNotification(ctx, claim)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Error("Claiming secret author notification", "error", err)
+		}
+		return
+	}
+
+	if first {
+		if err := sendSecretAuthorDM(messenger, slackID, event, location); err != nil {
+			log.Error("Sending DM to secret author", "error", err)
+
+			// Released, so the next team or a redelivery can try again
+			if err := h.db.ReleaseSecretAuthorNotification(ctx, gensql.ReleaseSecretAuthorNotificationParams(claim)); err != nil {
+				log.Error("Releasing secret author notification", "error", err)
+			}
+			return
+		}
+
+		log.Info("Sent DM to secret author", "alert", event.Alert.URL)
+	}
+
+	threads, err := h.db.ListSlackMessagesByEvent(ctx, gensql.ListSlackMessagesByEventParams{
+		TeamSlug: team.Name,
+		EventID:  event.Alert.URL,
+	})
+	if err != nil {
+		log.Error("Listing alert threads", "error", err)
+		return
+	}
+
+	for _, thread := range threads {
+		message := renderFor(team, thread.Channel, slack.CreateSecretAuthorNotifiedMessage(thread.Channel, thread.ThreadTs, slackID))
+		payload, err := json.Marshal(message)
+		if err != nil {
+			log.Error("Marshalling message", "error", err)
+			continue
+		}
+
+		if _, err := h.notifiers.Slack(team).PostMessage(payload); err != nil {
+			log.Error("Posting message", "error", err, "channel", thread.Channel)
+		}
+	}
+}
+
+func sendSecretAuthorDM(messenger slack.DirectMessenger, slackID string, event github.Event, location github.SecretLocation) error {
+	dmChannel, err := messenger.OpenDM(slackID)
+	if err != nil {
+		return fmt.Errorf("opening DM: %w", err)
+	}
+
+	payload, err := json.Marshal(slack.CreateSecretAuthorDM(dmChannel, event, location))
+	if err != nil {
+		return err
+	}
+
+	if _, err := messenger.PostMessage(payload); err != nil {
+		return fmt.Errorf("posting DM: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,123 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+var (
+	openedAlertActions = []string{"created", "reopened", "reopened_by_user", "reintroduced"}
+	closedAlertActions = []string{"fixed", "dismissed", "auto_dismissed", "resolved", "closed_by_user"}
+)
+
+// trackSecurityAlert keeps the team's open security alerts, so they can be
+// escalated when left open past the team's SLA.
+func (h *Handler) trackSecurityAlert(ctx context.Context, log *slog.Logger, team github.Team, event github.Event) {
+	switch {
+	case slices.Contains(openedAlertActions, event.Action):
+		if err := h.db.CreateSecurityAlert(ctx, gensql.CreateSecurityAlertParams{
+			TeamSlug:   team.Name,
+			AlertUrl:   event.Alert.URL,
+			AlertType:  event.GetEventType().Name(),
+			Repository: event.GetRepositoryName(),
+			Severity:   alertSeverity(event).Name(),
+			Summary:    alertSummary(event),
+		}); err != nil {
+			log.Error("Tracking security alert", "error", err, "alert", event.Alert.URL)
+		}
+	case slices.Contains(closedAlertActions, event.Action):
+		if err := h.db.DeleteSecurityAlert(ctx, gensql.DeleteSecurityAlertParams{
+			TeamSlug: team.Name,
+			AlertUrl: event.Alert.URL,
+		}); err != nil {
+			log.Error("Clearing security alert", "error", err, "alert", event.Alert.URL)
+		}
+	}
+}
+
+// storeSecurityAlert keeps the organization's stored alerts up to date between
+// the daily refreshes, for the security digests.
+func (h *Handler) storeSecurityAlert(ctx context.Context, log *slog.Logger, event github.Event) {
+	alert, ok := event.StoredSecurityAlert()
+	if !ok {
+		return
+	}
+
+	switch {
+	case slices.Contains(openedAlertActions, event.Action):
+		if err := h.db.UpsertOrgSecurityAlert(ctx, alert); err != nil {
+			log.Error("Storing security alert", "error", err, "alert", alert.AlertUrl)
+		}
+	case slices.Contains(closedAlertActions, event.Action):
+		if err := h.db.DeleteOrgSecurityAlert(ctx, gensql.DeleteOrgSecurityAlertParams{
+			Org:      alert.Org,
+			AlertUrl: alert.AlertUrl,
+		}); err != nil {
+			log.Error("Removing stored security alert", "error", err, "alert", alert.AlertUrl)
+		}
+	}
+}
+
+// securityThread returns the channel and thread of the message already posted
+// about a security alert or advisory, and reacts to that message with the new
+// state the action leaves it in. The timestamp is empty if there is no such message.
+func (h *Handler) securityThread(ctx context.Context, log *slog.Logger, team github.Team, source github.Source, eventID, action string) (string, string) {
+	message, err := h.db.GetSlackMessage(ctx, gensql.GetSlackMessageParams{
+		TeamSlug: team.Name,
+		EventID:  eventID,
+		Channel:  source.Channel,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Error("Getting thread timestamp", "error", err, "id", eventID)
+	}
+
+	channel := source.Channel
+	if message.Channel != "" {
+		channel = message.Channel
+	}
+
+	if message.ThreadTs != "" {
+		reaction := slack.ReactionDefault
+		switch action {
+		case "dismissed", "auto_dismissed", "closed_by_user":
+			reaction = slack.ReactionCancelled
+		case "fixed", "published":
+			reaction = slack.ReactionSuccess
+		}
+
+		log.Info("Posting reaction to security alert", "action", action, "timestamp", message.ThreadTs, "reaction", reaction)
+		if err := h.notifiers.Slack(team).PostReaction(channel, message.ThreadTs, reaction); err != nil {
+			log.Error("Posting reaction", "error", err, "channel", channel, "timestamp", message.ThreadTs, "reaction", reaction)
+		}
+	}
+
+	return channel, message.ThreadTs
+}
+
+// alertSeverity returns the severity of the alert, where secret scanning
+// alerts are always critical.
+func alertSeverity(event github.Event) github.SeverityType {
+	if severity, ok := event.AlertSeverity(); ok {
+		return severity
+	}
+
+	return github.SeverityCritical
+}
+
+func alertSummary(event github.Event) string {
+	switch {
+	case event.Alert.SecretType != nil:
+		return *event.Alert.SecretType
+	case event.Alert.SecurityAdvisory != nil:
+		return event.Alert.SecurityAdvisory.Summary
+	default:
+		return event.Alert.Rule.Description
+	}
+}
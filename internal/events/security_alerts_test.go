@@ -0,0 +1,214 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/mock"
+	"github.com/navikt/ghep/internal/sql/gensql"
+	"github.com/navikt/ghep/internal/testdata"
+)
+
+func TestTrackSecurityAlert(t *testing.T) {
+	db := &mock.Database{}
+	slack := &mock.Slack{}
+	team := github.Team{Name: "test"}
+	handler := NewHandler(db, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team})
+
+	tests := []struct {
+		file     string
+		expected []string
+	}{
+		{file: "dependabot-created-1.json", expected: []string{"dependabot_alert"}},
+		{file: "secret-scanning-created-1.json", expected: []string{"dependabot_alert", "secret_scanning_alert"}},
+		{file: "code-scanning-created-1.json", expected: []string{"dependabot_alert", "secret_scanning_alert", "code_scanning_alert"}},
+		{file: "dependabot-fixed-1.json", expected: []string{"secret_scanning_alert", "code_scanning_alert"}},
+		{file: "secret-scanning-resolved-1.json", expected: []string{"code_scanning_alert"}},
+		// Another alert than the one created
+		{file: "code-scanning-fixed-1.json", expected: []string{"code_scanning_alert"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			event, err := testdata.AsEvent(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler.trackSecurityAlert(context.TODO(), slog.Default(), team, event)
+
+			var got []string
+			for _, alert := range db.SecurityAlerts {
+				got = append(got, alert.AlertType)
+			}
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("tracked alerts mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("severity", func(t *testing.T) {
+		db.SecurityAlerts = nil
+		for _, file := range []string{"dependabot-created-1.json", "secret-scanning-created-1.json"} {
+			event, err := testdata.AsEvent(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			handler.trackSecurityAlert(context.TODO(), slog.Default(), team, event)
+		}
+
+		if db.SecurityAlerts[1].Severity != "critical" {
+			t.Errorf("expected secret scanning alert to be critical, got %q", db.SecurityAlerts[1].Severity)
+		}
+		if db.SecurityAlerts[0].Severity == "" || db.SecurityAlerts[0].Summary == "" {
+			t.Errorf("expected severity and summary of Dependabot alert, got %+v", db.SecurityAlerts[0])
+		}
+	})
+}
+
+func TestStoreSecurityAlert(t *testing.T) {
+	db := &mock.Database{}
+	handler := NewHandler(db, &mock.Github{}, (&mock.Slack{}).Notifiers(), &mock.Webhook{}, map[string]github.Team{})
+
+	for _, file := range []string{"dependabot-created-1.json", "secret-scanning-created-1.json"} {
+		event, err := testdata.AsEvent(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handler.storeSecurityAlert(context.TODO(), slog.Default(), event)
+	}
+
+	if len(db.OrgSecurityAlerts) != 2 {
+		t.Fatalf("expected 2 stored alerts, got %d", len(db.OrgSecurityAlerts))
+	}
+
+	dependabot := db.OrgSecurityAlerts[0]
+	if dependabot.Org != "navikt" || dependabot.AlertType != "dependabot" || dependabot.Repository != "k9-sak-web" || dependabot.Severity == "" || !dependabot.CreatedAt.Valid {
+		t.Errorf("unexpected stored Dependabot alert %+v", dependabot)
+	}
+
+	secret := db.OrgSecurityAlerts[1]
+	if secret.Org != "nais" || secret.AlertType != "secret_scanning" || secret.Description == "" {
+		t.Errorf("unexpected stored secret scanning alert %+v", secret)
+	}
+
+	event, err := testdata.AsEvent("dependabot-fixed-1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.storeSecurityAlert(context.TODO(), slog.Default(), event)
+
+	if len(db.OrgSecurityAlerts) != 1 || db.OrgSecurityAlerts[0].AlertType != "secret_scanning" {
+		t.Errorf("expected only the secret scanning alert to be stored, got %+v", db.OrgSecurityAlerts)
+	}
+}
+
+func TestNotifySecretAuthor(t *testing.T) {
+	event, err := testdata.AsEvent("secret-scanning-created-1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	team := github.Team{
+		Name:    "test",
+		Sources: []github.Source{{SourceType: "security", Channel: "#security"}},
+	}
+
+	tests := []struct {
+		name     string
+		location *github.SecretLocation
+		expected int
+	}{
+		// The alert, the DM and the note in the alert's thread
+		{name: "known author", location: &github.SecretLocation{AuthorLogin: "Kyrremann", Path: "config.yaml", StartLine: 3}, expected: 3},
+		{name: "author by email", location: &github.SecretLocation{AuthorEmail: "kyrre.havik@nav.no"}, expected: 3},
+		{name: "author without Slack ID", location: &github.SecretLocation{AuthorLogin: "someone-else"}, expected: 1},
+		{name: "not found in a commit", expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &mock.Database{}
+			slack := &mock.Slack{}
+			handler := NewHandler(db, &mock.Github{SecretLocation: tt.location}, slack.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team})
+
+			if err := handler.Handle(context.TODO(), slog.Default(), team, event); err != nil {
+				t.Fatal(err)
+			}
+
+			slack.EnsureMessages(t, github.TypeSecretScanningAlert, tt.expected)
+		})
+	}
+}
+
+func TestNotifySecretAuthorOnce(t *testing.T) {
+	event, err := testdata.AsEvent("secret-scanning-created-1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources := []github.Source{{SourceType: "security", Channel: "#security"}}
+	team := github.Team{Name: "test", Sources: sources}
+	other := github.Team{Name: "other", Sources: sources}
+
+	db := &mock.Database{}
+	slack := &mock.Slack{}
+	location := &github.SecretLocation{AuthorLogin: "Kyrremann", Path: "config.yaml", StartLine: 3}
+	handler := NewHandler(db, &mock.Github{SecretLocation: location}, slack.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team, "other": other})
+
+	// The alert, the DM and the note in the alert's thread
+	if err := handler.Handle(context.TODO(), slog.Default(), team, event); err != nil {
+		t.Fatal(err)
+	}
+	slack.EnsureMessages(t, github.TypeSecretScanningAlert, 3)
+
+	// A redelivery posts the alert, but does not send the DM or note again
+	if err := handler.Handle(context.TODO(), slog.Default(), team, event); err != nil {
+		t.Fatal(err)
+	}
+	slack.EnsureMessages(t, github.TypeSecretScanningAlert, 4)
+
+	// Another team notes the DM in its own thread, without sending it again
+	if err := handler.Handle(context.TODO(), slog.Default(), other, event); err != nil {
+		t.Fatal(err)
+	}
+	slack.EnsureMessages(t, github.TypeSecretScanningAlert, 6)
+}
+
+func TestCodeScanningAlertThread(t *testing.T) {
+	event, err := testdata.AsEvent("code-scanning-fixed-1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	team := github.Team{
+		Name:    "test",
+		Sources: []github.Source{{SourceType: "security", Channel: "#security"}},
+	}
+
+	db := &mock.Database{}
+	if err := db.CreateSlackMessage(context.TODO(), gensql.CreateSlackMessageParams{
+		TeamSlug: team.Name,
+		EventID:  event.Alert.URL,
+		ThreadTs: "1719220032.123456",
+		Channel:  "C123",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	slack := &mock.Slack{}
+	handler := NewHandler(db, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team})
+
+	message, err := handler.handleCodeScanningAlertEvent(context.TODO(), slog.Default(), team, team.Sources[0], event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message.Channel != "C123" || message.ThreadTimestamp != "1719220032.123456" {
+		t.Errorf("expected message in the alert's thread, got channel %q and thread %q", message.Channel, message.ThreadTimestamp)
+	}
+	slack.EnsureReactions(t, github.TypeCodeScanningAlert, 1)
+}
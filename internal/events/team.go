@@ -12,33 +12,37 @@ import (
 )
 
 // handleTeamSideEffects performs DB operations for team events (add/remove repos/members).
-func (h *Handler) handleTeamSideEffects(ctx context.Context, log *slog.Logger, event github.Event) error {
+func (h *Handler) handleTeamSideEffects(ctx context.Context, log *slog.Logger, team github.Team, event github.Event) error {
 	if !slices.Contains([]string{"added_to_repository", "removed_from_repository", "added", "removed"}, event.Action) {
 		return nil
 	}
 
-	team := event.Team.Name
+	if team.IsExternalContributor() {
+		return nil
+	}
+
 	log.Info("Received team event", "triggered_by", event.Sender.Login)
 
 	switch event.Action {
 	case "added_to_repository":
-		if err := sql.AddRepositoryToTeam(ctx, h.db, team, event.Repository.Name); err != nil {
+		if err := sql.AddRepositoryToTeam(ctx, h.db, team.Name, event.GetOrg(), event.Repository.Name); err != nil {
 			return err
 		}
 	case "removed_from_repository":
 		if err := h.db.RemoveTeamRepository(ctx, gensql.RemoveTeamRepositoryParams{
-			TeamSlug: team,
+			TeamSlug: team.Name,
+			Org:      event.GetOrg(),
 			Name:     event.Repository.Name,
 		}); err != nil {
 			return err
 		}
 	case "added":
-		if err := sql.AddMemberToTeam(ctx, h.db, team, event.Member.Login); err != nil {
+		if err := sql.AddMemberToTeam(ctx, h.db, team.Name, event.Member.Login); err != nil {
 			return err
 		}
 	case "removed":
 		if err := h.db.RemoveTeamMember(ctx, gensql.RemoveTeamMemberParams{
-			TeamSlug:  team,
+			TeamSlug:  team.Name,
 			UserLogin: event.Member.Login,
 		}); err != nil {
 			return err
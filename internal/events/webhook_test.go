@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/mock"
+)
+
+func TestHandleWebhookSource(t *testing.T) {
+	team := github.Team{
+		Name: "test",
+		Sources: []github.Source{
+			{
+				SourceType: "commits",
+				Webhook:    "dashboard",
+				Config:     github.SourceConfig{Branches: []string{"main"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		ref  string
+		sent int
+	}{
+		{name: "commit on configured branch", ref: "refs/heads/main", sent: 1},
+		{name: "commit on other branch", ref: "refs/heads/dev", sent: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slack := &mock.Slack{}
+			webhooks := &mock.Webhook{}
+			handler := NewHandler(&mock.Database{}, &mock.Github{}, slack.Notifiers(), webhooks, map[string]github.Team{"test": team})
+
+			event := github.Event{Ref: tt.ref, Repository: &github.Repository{Name: "ghep"}}
+			if err := handler.handleSource(context.TODO(), slog.Default(), team, team.Sources[0], event); err != nil {
+				t.Fatal(err)
+			}
+
+			if webhooks.Sent != tt.sent {
+				t.Errorf("expected %d events sent to webhook, got %d", tt.sent, webhooks.Sent)
+			}
+
+			slack.EnsureMessages(t, event.GetEventType(), 0)
+		})
+	}
+}
@@ -27,7 +27,7 @@ func (h *Handler) handleWorkflowEvent(ctx context.Context, log *slog.Logger, tea
 
 	for _, commitMessage := range commitMessages {
 		if !event.Sender.IsBot() {
-			if err := h.slack.PostWorkflowReaction(log, event, commitMessage.Channel, commitMessage.ThreadTs); err != nil {
+			if err := h.notifiers.Slack(team).PostWorkflowReaction(log, event, commitMessage.Channel, commitMessage.ThreadTs); err != nil {
 				log.Error("Posting workflow reaction", "error", err, "channel", commitMessage.Channel, "timestamp", commitMessage.ThreadTs)
 			}
 
@@ -37,10 +37,14 @@ func (h *Handler) handleWorkflowEvent(ctx context.Context, log *slog.Logger, tea
 					log.Error("Updating message", "error", err, "timestamp", commitMessage.ThreadTs)
 					continue
 				}
+				// Nothing to update, like when the commit message already has a footer
+				if updatedCommitMessage == nil {
+					continue
+				}
 				updatedCommitMessage.Timestamp = commitMessage.ThreadTs
 
 				log.Info("Posting update of commit", "channel", updatedCommitMessage.Channel, "timestamp", updatedCommitMessage.Timestamp)
-				if err = h.slack.PostUpdatedMessage(*updatedCommitMessage); err != nil {
+				if err = h.notifiers.Slack(team).PostUpdatedMessage(*updatedCommitMessage); err != nil {
 					log.Error("Posting updated commit message", "error", err)
 				}
 			}
@@ -59,7 +63,7 @@ func (h *Handler) handleWorkflowEvent(ctx context.Context, log *slog.Logger, tea
 
 		for _, message := range pullRequestMessages {
 			log.Info("Reacting to pull request that triggered workflow", "action", event.Action, "workflow_status", event.Workflow.Status, "workflow_conclusion", event.Workflow.Conclusion)
-			if err := h.slack.PostWorkflowReaction(log, event, message.Channel, message.ThreadTs); err != nil {
+			if err := h.notifiers.Slack(team).PostWorkflowReaction(log, event, message.Channel, message.ThreadTs); err != nil {
 				log.Error("Posting workflow pull request reaction", "error", err, "channel", message.Channel, "timestamp", message.ThreadTs)
 			}
 		}
@@ -79,20 +83,21 @@ func (h *Handler) handleWorkflowEvent(ctx context.Context, log *slog.Logger, tea
 		workflowTimestamp := workflowMessage.ThreadTs
 		if event.Action == "completed" && event.Workflow.Conclusion == "success" {
 			log.Info("Reacting to workflow", "action", event.Action, "workflow_status", event.Workflow.Status, "workflow_conclusion", event.Workflow.Conclusion)
-			if err := h.slack.PostReaction(source.Channel, workflowTimestamp, slack.ReactionSuccess); err != nil {
+			if err := h.notifiers.Slack(team).PostReaction(source.Channel, workflowTimestamp, slack.ReactionSuccess); err != nil {
 				log.Error("Posting reaction", "error", err, "channel", source.Channel, "timestamp", workflowTimestamp)
 			}
 		}
 	}
 
-	if err := event.Workflow.UpdateFailedJob(); err != nil {
+	if err := h.github.UpdateFailedJob(ctx, team.Org, event.Workflow); err != nil {
 		log.Error("Updating failed job", "error", err)
 	}
 
-	return handleWorkflowEvent(log, source, event)
+	interactive := h.notifiers.Interactive(team, team.NotifierForChannel(source.Channel))
+	return handleWorkflowEvent(log, team, interactive, source, event)
 }
 
-func handleWorkflowEvent(log *slog.Logger, source github.Source, event github.Event) (*slack.Message, error) {
+func handleWorkflowEvent(log *slog.Logger, team github.Team, interactive bool, source github.Source, event github.Event) (*slack.Message, error) {
 	if source.Config.Workflows.IgnoreBots && event.Sender.IsBot() {
 		return nil, nil
 	}
@@ -110,5 +115,5 @@ func handleWorkflowEvent(log *slog.Logger, source github.Source, event github.Ev
 	}
 
 	log.Info("Received workflow run", "conclusion", event.Workflow.Conclusion, "channel", source.Channel)
-	return slack.CreateWorkflowMessage(source.Channel, event), nil
+	return slack.CreateWorkflowMessage(source.Channel, team.Name, interactive, usesBlockKit(team, source.Channel), event), nil
 }
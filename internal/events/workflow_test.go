@@ -8,6 +8,8 @@ import (
 
 	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/mock"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
 	"github.com/navikt/ghep/internal/testdata"
 )
 
@@ -217,7 +219,7 @@ func TestHandleWorkflow(t *testing.T) {
 				}
 			}
 
-			got, err := handleWorkflowEvent(slog.Default(), tt.source, tt.event)
+			got, err := handleWorkflowEvent(slog.Default(), github.Team{}, false, tt.source, tt.event)
 			if err != nil && !tt.err {
 				t.Error(err)
 			}
@@ -261,7 +263,7 @@ func TestHandleWorkflowEvents(t *testing.T) {
 
 	t.Run("Simple workflow event", func(t *testing.T) {
 		slack := &mock.Slack{}
-		handler := NewHandler(&mock.Database{}, slack, teamConfig)
+		handler := NewHandler(&mock.Database{}, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, teamConfig)
 
 		workflowEvent, err := testdata.AsEvent("workflow-run-failure-1.json")
 		if err != nil {
@@ -288,7 +290,99 @@ func TestHandleWorkflowEvents(t *testing.T) {
 
 	t.Run("Workflow event with commit", func(t *testing.T) {
 		slack := &mock.Slack{}
-		handler := NewHandler(&mock.Database{}, slack, teamConfig)
+		handler := NewHandler(&mock.Database{}, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, teamConfig)
+
+		commitEvent, err := testdata.AsEvent("commit-2.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sources := team.SourcesForType(commitEvent.GetEventType())
+		if sources == nil {
+			t.Errorf("No source found for %s", commitEvent.GetEventType())
+		}
+
+		// prepopulate db with a commit event
+		if err := handler.handleSource(
+			context.TODO(),
+			slog.Default(),
+			team,
+			sources[0],
+			commitEvent,
+		); err != nil {
+			t.Error(err)
+		}
+
+		slack.EnsureMessages(t, commitEvent.GetEventType(), 1)
+
+		workflowEvent, err := testdata.AsEvent("workflow-run-failure-1.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sources = team.SourcesForType(workflowEvent.GetEventType())
+		if sources == nil {
+			t.Errorf("No source found for %s", workflowEvent.GetEventType())
+		}
+
+		// ensure events are connected
+		workflowEvent.Workflow.HeadSHA = commitEvent.After
+
+		if err := handler.handleSource(
+			context.TODO(),
+			slog.Default(),
+			team,
+			sources[0],
+			workflowEvent,
+		); err != nil {
+			t.Error(err)
+		}
+
+		slack.Ensure(t, workflowEvent.GetEventType(), 2, 1, 1)
+	})
+
+	t.Run("Workflow event with commit that already has a footer", func(t *testing.T) {
+		workflowEvent, err := testdata.AsEvent("workflow-run-failure-1.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		db := &mock.Database{
+			SlackMessages: []gensql.CreateSlackMessageParams{
+				{
+					TeamSlug: team.Name,
+					EventID:  workflowEvent.Workflow.HeadSHA,
+					ThreadTs: "1",
+					Channel:  "#test",
+					Payload:  []byte(`{"channel":"#test","text":"1 new commits","attachments":[{"text":"commit","footer":"<https://github.com|build>"}]}`),
+				},
+			},
+		}
+
+		slack := &mock.Slack{}
+		handler := NewHandler(db, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, teamConfig)
+
+		sources := team.SourcesForType(workflowEvent.GetEventType())
+		if err := handler.handleSource(
+			context.TODO(),
+			slog.Default(),
+			team,
+			sources[0],
+			workflowEvent,
+		); err != nil {
+			t.Error(err)
+		}
+
+		// The commit is reacted to, but not updated
+		slack.Ensure(t, workflowEvent.GetEventType(), 1, 1, 0)
+	})
+
+	t.Run("Workflow event with commit rendered with Block Kit", func(t *testing.T) {
+		team := team
+		team.Config.BlockKit = true
+
+		slack := &mock.Slack{}
+		handler := NewHandler(&mock.Database{}, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, map[string]github.Team{"test": team})
 
 		commitEvent, err := testdata.AsEvent("commit-2.json")
 		if err != nil {
@@ -341,7 +435,7 @@ func TestHandleWorkflowEvents(t *testing.T) {
 
 	t.Run("Successful workflow with pull request", func(t *testing.T) {
 		slack := &mock.Slack{}
-		handler := NewHandler(&mock.Database{}, slack, teamConfig)
+		handler := NewHandler(&mock.Database{}, &mock.Github{}, slack.Notifiers(), &mock.Webhook{}, teamConfig)
 
 		pullRequestEvent, err := testdata.AsEvent("pull-opened-1.json")
 		if err != nil {
@@ -389,3 +483,42 @@ func TestHandleWorkflowEvents(t *testing.T) {
 		slack.Ensure(t, workflowEvent.GetEventType(), 1, 1, 0)
 	})
 }
+
+func TestHandleWorkflowInteractive(t *testing.T) {
+	source := github.Source{SourceType: "workflows", Channel: "#test"}
+	event := github.Event{
+		Action: "completed",
+		Workflow: &github.Workflow{
+			ID:         42,
+			Conclusion: "failure",
+		},
+		Repository: &github.Repository{Name: "ghep"},
+	}
+
+	for _, interactive := range []bool{false, true} {
+		message, err := handleWorkflowEvent(slog.Default(), github.Team{Name: "nada"}, interactive, source, event)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var accessory *slack.Accessory
+		for _, attachment := range message.Attachments {
+			for _, block := range attachment.Blocks {
+				if block.Accessory != nil && block.BlockID == "nada|ghep|42" {
+					accessory = block.Accessory
+				}
+			}
+		}
+
+		if !interactive {
+			if accessory != nil {
+				t.Errorf("expected no button without interactivity, got %v", accessory)
+			}
+			continue
+		}
+
+		if accessory == nil || accessory.ActionID != slack.ActionRerunFailedJobs {
+			t.Errorf("expected re-run button, got %v", accessory)
+		}
+	}
+}
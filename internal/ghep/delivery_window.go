@@ -0,0 +1,171 @@
+package ghep
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// RunDeliveryWindowScheduler posts the events held back outside a delivery
+// window, as a summary with the events in its thread, when the window opens.
+func RunDeliveryWindowScheduler(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, notifiers slack.Notifiers) {
+	log.Info("Starting delivery window scheduler")
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticker.C:
+			if err := postQueuedEvents(ctx, log, db, t, teamConfig, notifiers); err != nil {
+				log.Error("Posting queued events", "error", err)
+			}
+		}
+	}
+}
+
+func postQueuedEvents(ctx context.Context, log *slog.Logger, db *gensql.Queries, now time.Time, teamConfig map[string]github.Team, notifiers slack.Notifiers) error {
+	rows, err := db.ListQueuedChannels(ctx)
+	if err != nil {
+		return err
+	}
+
+	// A channel can get events from several sources, and is posted to when all their windows are open
+	type queue struct {
+		teamSlug string
+		channel  string
+	}
+	var ready []queue
+	closed := map[queue]bool{}
+	for _, row := range rows {
+		q := queue{teamSlug: row.TeamSlug, channel: row.Channel}
+		if window := deliveryWindowFor(teamConfig[row.TeamSlug], row.Channel, row.SourceType); window != nil {
+			open, err := window.IsOpen(now)
+			if err != nil {
+				log.Error("Checking delivery window", "error", err, "team", row.TeamSlug, "channel", row.Channel, "source_type", row.SourceType)
+			}
+			// Invalid windows do not hold back events, so their queued events are posted
+			if err == nil && !open {
+				closed[q] = true
+			}
+		}
+		if !slices.Contains(ready, q) {
+			ready = append(ready, q)
+		}
+	}
+
+	for _, q := range ready {
+		if closed[q] {
+			continue
+		}
+
+		log := log.With("team", q.teamSlug, "channel", q.channel)
+
+		// Claimed in a transaction, so the events stay queued if the summary can not be posted
+		err := db.InTx(ctx, func(db *gensql.Queries) error {
+			queued, err := db.ClaimQueuedEvents(ctx, gensql.ClaimQueuedEventsParams{TeamSlug: q.teamSlug, Channel: q.channel})
+			if err != nil {
+				return err
+			}
+
+			team, ok := teamConfig[q.teamSlug]
+			if !ok {
+				log.Warn("Dropping queued events for team no longer configured", "events", len(queued))
+				return nil
+			}
+
+			log.Info("Posting queued events", "events", len(queued))
+			return postQueuedChannel(ctx, log, db, team, q.channel, queued, notifiers)
+		})
+		if err != nil {
+			log.Error("Posting queued events", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// postQueuedChannel posts the summary, and the queued messages in its thread.
+// Messages replying to an earlier thread are posted there instead. It only
+// returns an error if the summary could not be posted, as the messages are
+// posted one by one after it.
+func postQueuedChannel(ctx context.Context, log *slog.Logger, db *gensql.Queries, team github.Team, channel string, queued []gensql.QueuedEvent, notifiers slack.Notifiers) error {
+	slices.SortFunc(queued, func(a, b gensql.QueuedEvent) int { return cmp.Compare(a.ID, b.ID) })
+
+	notifier := team.NotifierForChannel(channel)
+
+	summary := slack.CreateQueuedEventsSummary(channel, queued)
+	if team.UsesBlockKit(notifier) {
+		summary = slack.ToBlockKit(summary)
+	}
+
+	resp, err := notifiers.Post(team, notifier, queued[0].SourceType, summary)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range queued {
+		var message slack.Message
+		if err := json.Unmarshal(event.Payload, &message); err != nil {
+			log.Error("Unmarshalling queued message", "error", err, "id", event.ID)
+			continue
+		}
+
+		// Messages outside of Slack can not be threaded, and are posted after the summary
+		if resp != nil {
+			message.Channel = resp.Channel
+			if message.ThreadTimestamp == "" {
+				message.ThreadTimestamp = resp.Timestamp
+			}
+		}
+
+		messageResp, err := notifiers.Post(team, notifier, event.SourceType, &message)
+		if err != nil {
+			log.Error("Posting queued message", "error", err, "id", event.ID)
+			continue
+		}
+
+		if event.EventID == "" || messageResp == nil {
+			continue
+		}
+
+		payload, err := json.Marshal(message)
+		if err != nil {
+			log.Error("Marshalling queued message", "error", err, "id", event.ID)
+			continue
+		}
+
+		if err := db.CreateSlackMessage(ctx, gensql.CreateSlackMessageParams{
+			TeamSlug: team.Name,
+			EventID:  event.EventID,
+			ThreadTs: messageResp.Timestamp,
+			Channel:  messageResp.Channel,
+			Payload:  payload,
+		}); err != nil {
+			log.Error("Storing message", "error", err, "event_id", event.EventID)
+		}
+	}
+
+	return nil
+}
+
+// deliveryWindowFor returns the window of the team's source posting to the
+// channel, falling back to the team's window if the source is no longer configured.
+func deliveryWindowFor(team github.Team, channel, sourceType string) *github.DeliveryWindow {
+	for _, source := range team.Sources {
+		if source.SourceType == sourceType && source.Channel == channel {
+			return source.Config.DeliveryWindow
+		}
+	}
+
+	return team.Config.DeliveryWindow
+}
@@ -0,0 +1,154 @@
+package ghep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/events"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// dependencyRollupRetention is how long merged and closed pull requests are
+// kept after being rolled up, so their roll-up can still be updated.
+const dependencyRollupRetention = 7 * 24 * time.Hour
+
+// RunDependencyRollupScheduler posts the daily roll-up of dependency pull
+// requests, for teams with silenceDependabot set to rollup.
+func RunDependencyRollupScheduler(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, notifiers slack.Notifiers) {
+	var teams []github.Team
+	for _, team := range teamConfig {
+		if team.DependencyRollup != nil {
+			teams = append(teams, team)
+		}
+	}
+
+	if len(teams) == 0 {
+		log.Info("No teams configured for dependency roll-up, scheduler not running")
+		return
+	}
+
+	log.Info("Starting dependency roll-up scheduler", "teams", len(teams))
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticker.C:
+			// Truncate to microsecond precision so the value round-trips
+			// through Postgres timestamptz (microsecond) without mismatch.
+			now := t.Truncate(time.Microsecond)
+			for _, team := range teams {
+				if err := maybeRollUpDependencies(ctx, log.With("team", team.Name), db, now, team, notifiers); err != nil {
+					log.Error("Posting dependency roll-up", "team", team.Name, "error", err)
+				}
+			}
+		}
+	}
+}
+
+func maybeRollUpDependencies(ctx context.Context, log *slog.Logger, db *gensql.Queries, now time.Time, team github.Team, notifiers slack.Notifiers) error {
+	rollup := team.DependencyRollup
+
+	scheduledAt, err := rollup.ScheduledAt(now)
+	if err != nil {
+		return err
+	}
+
+	if now.Before(scheduledAt) {
+		return nil
+	}
+
+	claimedAt, err := db.ClaimTeamDigestSlot(ctx, gensql.ClaimTeamDigestSlotParams{
+		Type:        "dependency-rollup",
+		TeamSlug:    team.Name,
+		SentAt:      pgtype.Timestamptz{Time: now, Valid: true},
+		ScheduledAt: pgtype.Timestamptz{Time: scheduledAt, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // already posted today
+		}
+		return err
+	}
+	if !claimedAt.Time.Equal(now) {
+		return nil
+	}
+
+	if err := db.DeleteClosedDependencyPullRequests(ctx, gensql.DeleteClosedDependencyPullRequestsParams{
+		TeamSlug: team.Name,
+		Before:   pgtype.Timestamptz{Time: now.Add(-dependencyRollupRetention), Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	// Stamped in a transaction, so the pull requests are rolled up again
+	// tomorrow if posting fails
+	var prs []gensql.DependencyPullRequest
+	var resp *slack.MessageResponse
+	var payload []byte
+	rolledUpAt := pgtype.Timestamptz{Time: now, Valid: true}
+	if err := db.InTx(ctx, func(db *gensql.Queries) error {
+		if err := db.RollUpDependencyPullRequests(ctx, gensql.RollUpDependencyPullRequestsParams{
+			RolledUpAt: rolledUpAt,
+			TeamSlug:   team.Name,
+		}); err != nil {
+			return err
+		}
+
+		prs, err = db.ListRolledUpDependencyPullRequests(ctx, gensql.ListRolledUpDependencyPullRequestsParams{
+			TeamSlug:   team.Name,
+			RolledUpAt: rolledUpAt,
+		})
+		if err != nil || len(prs) == 0 {
+			return err
+		}
+
+		message := slack.Redact(slack.CreateDependencyRollupMessage(rollup.Channel, prs), team.Config.Redaction)
+		if team.UsesBlockKit(rollup.Notifier) {
+			message = slack.ToBlockKit(message)
+		}
+
+		payload, err = json.Marshal(message)
+		if err != nil {
+			return err
+		}
+
+		resp, err = notifiers.Post(team, rollup.Notifier, github.NotifyKeyDependencyRollup, message)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if len(prs) == 0 {
+		log.Info("No dependency pull requests to roll up", "channel", rollup.Channel)
+		return nil
+	}
+
+	// Stored so the roll-up can be edited in place as its pull requests
+	// change, which only Slack messages can be
+	if resp != nil {
+		if err := db.CreateSlackMessage(ctx, gensql.CreateSlackMessageParams{
+			TeamSlug: team.Name,
+			EventID:  events.DependencyRollupEventID(now),
+			ThreadTs: resp.Timestamp,
+			Channel:  resp.Channel,
+			Payload:  payload,
+		}); err != nil {
+			log.Error("Storing dependency roll-up", "error", err)
+		}
+	}
+
+	log.Info("Dependency roll-up sent", "channel", rollup.Channel, "pull_requests", len(prs))
+
+	return nil
+}
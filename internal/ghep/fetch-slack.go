@@ -2,26 +2,30 @@ package ghep
 
 import (
 	"context"
-	"errors"
 	"log/slog"
-	"os"
+	"slices"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql"
 	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
-func FetchSlackUsers(ctx context.Context, log *slog.Logger, db *gensql.Queries) {
-	log.Info("Creating Slack client")
-	slackAPI, err := slack.New(
-		log.With("client", "slack"),
-		os.Getenv("SLACK_TOKEN"),
-	)
-	if err != nil {
-		log.Error("Creating Slack client", "error", err)
-		return
+// FetchSlackUsers maps Github users to Slack users by email in every Slack workspace.
+func FetchSlackUsers(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, slackWorkspaces slack.Workspaces) {
+	for _, workspace := range slackWorkspaces.Names() {
+		slackAPI, _ := slackWorkspaces.Get(workspace)
+
+		log := log
+		if workspace != "" {
+			log = log.With("workspace", workspace)
+		}
+
+		fetchSlackUsersInWorkspace(ctx, log, db, teamConfig, workspace, slackAPI)
 	}
+}
 
+func fetchSlackUsersInWorkspace(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, workspace string, slackAPI slack.Client) {
 	log.Info("Fetching users from Slack")
 	users, err := slackAPI.ListUsers()
 	if err != nil {
@@ -29,24 +33,70 @@ func FetchSlackUsers(ctx context.Context, log *slog.Logger, db *gensql.Queries)
 		return
 	}
 
-	log.Info("Saving Slack users ID to database")
+	// ListUsers fails unless every page is fetched, but an empty list would
+	// still remove every stored ID in the workspace
+	if len(users) == 0 {
+		log.Warn("No users listed from Slack, keeping stored Slack IDs")
+		return
+	}
+
+	slackIDs := make(map[string]string, len(users))
 	for _, user := range users {
-		login, err := db.GetUserByEmail(ctx, user.Email)
+		if user.Email != "" {
+			slackIDs[user.Email] = user.ID
+		}
+	}
+
+	log.Info("Saving Slack users ID to database")
+	added, removed, err := sql.SyncSlackIDs(ctx, db, workspace, slackIDs)
+	if err != nil {
+		log.Error("Syncing Slack IDs", "error", err)
+		return
+	}
+
+	for _, login := range added {
+		log.Info("Slack ID added", "login", login)
+	}
+	for _, login := range removed {
+		log.Info("Slack ID removed", "login", login)
+	}
+
+	logSlackChangesPerTeam(ctx, log, db, teamConfig, workspace, added, removed)
+	log.Info("Slack users synced", "users", len(slackIDs), "added", len(added), "removed", len(removed))
+}
+
+// logSlackChangesPerTeam logs which members of each team in the workspace got their Slack ID added or removed.
+func logSlackChangesPerTeam(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, workspace string, added, removed []string) {
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	for name, team := range teamConfig {
+		if team.SlackWorkspace != workspace {
+			continue
+		}
+
+		members, err := db.ListTeamMembers(ctx, name)
 		if err != nil {
-			if !errors.Is(err, pgx.ErrNoRows) {
-				log.Error("Getting user by email", "error", err, "email", user.Email)
-			}
+			log.Error("Listing team members", "team", name, "error", err)
 			continue
 		}
 
-		if login != "" {
-			if err := db.CreateSlackID(ctx, gensql.CreateSlackIDParams{
-				Login: login,
-				ID:    user.ID,
-			}); err != nil {
-				log.Error("Saving Slack user ID to database", "user", user.ID, "error", err)
-				return
+		var teamAdded, teamRemoved []string
+		for _, member := range members {
+			if slices.Contains(added, member) {
+				teamAdded = append(teamAdded, member)
 			}
+			if slices.Contains(removed, member) {
+				teamRemoved = append(teamRemoved, member)
+			}
+		}
+
+		if len(teamAdded) > 0 {
+			log.Info("Added Slack IDs for team members", "team", name, "members", teamAdded)
+		}
+		if len(teamRemoved) > 0 {
+			log.Info("Removed Slack IDs for team members", "team", name, "members", teamRemoved)
 		}
 	}
 }
@@ -11,7 +11,7 @@ import (
 	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
-func FetchGithubData(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, githubClient github.Client, subscribeToOrg bool) {
+func FetchGithubData(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, githubClients github.Clients, subscribeToOrg bool) {
 	log.Info("Fetching data from Github")
 
 	storedTeams, err := db.ListTeams(ctx)
@@ -20,13 +20,33 @@ func FetchGithubData(ctx context.Context, log *slog.Logger, db *gensql.Queries,
 		return
 	}
 
-	for name := range teamConfig {
-		if slices.Contains(storedTeams, name) {
+	// Teams and repositories stored before ghep supported more than one organization belong to the primary organization
+	primaryOrg := github.OrgKey(githubClients.Primary().Org())
+	if err := db.AssignTeamsToOrg(ctx, primaryOrg); err != nil {
+		log.Error("Assigning teams to the primary organization", "error", err)
+		return
+	}
+	if err := db.AssignRepositoriesToOrg(ctx, primaryOrg); err != nil {
+		log.Error("Assigning repositories to the primary organization", "error", err)
+		return
+	}
+
+	for name, team := range teamConfig {
+		client, ok := githubClients.ForTeam(team)
+		if !ok {
+			log.Error("No Github client for the team's organization", "team", name, "org", team.Org)
 			continue
 		}
 
-		log.Info("Adding team to database", "team", name)
-		if err := db.CreateTeam(ctx, name); err != nil {
+		if !slices.Contains(storedTeams, name) {
+			log.Info("Adding team to database", "team", name)
+		}
+
+		if err := db.CreateTeam(ctx, gensql.CreateTeamParams{
+			Slug:       name,
+			Org:        github.OrgKey(client.Org()),
+			GithubSlug: team.Slug(),
+		}); err != nil {
 			log.Error("Creating team in database", "team", name, "error", err)
 			return
 		}
@@ -47,20 +67,24 @@ func FetchGithubData(ctx context.Context, log *slog.Logger, db *gensql.Queries,
 	log.Info("Getting info about teams from Github")
 	reposBlocklist := strings.Split(os.Getenv("GITHUB_BLOCKLIST_REPOS"), ",")
 
-	if subscribeToOrg {
-		if err := githubClient.FetchOrgAsTeam(ctx, log, reposBlocklist); err != nil {
-			log.Error("Fetching org members from Github", "error", err)
-			return
-		}
-	} else {
-		if err := githubClient.FetchTeams(ctx, log, reposBlocklist); err != nil {
-			log.Error("Fetching teams from Github", "error", err)
-			return
+	for _, githubClient := range githubClients.All() {
+		log := log.With("org", githubClient.Org())
+
+		if subscribeToOrg {
+			if err := githubClient.FetchOrgAsTeam(ctx, log, reposBlocklist); err != nil {
+				log.Error("Fetching org members from Github", "error", err)
+				continue
+			}
+		} else {
+			if err := githubClient.FetchTeams(ctx, log, reposBlocklist); err != nil {
+				log.Error("Fetching teams from Github", "error", err)
+				continue
+			}
 		}
-	}
 
-	if err := githubClient.FetchOrgUsersWithEmail(ctx); err != nil {
-		log.Error("Fetching org members from Github", "error", err)
-		return
+		if err := githubClient.FetchOrgUsersWithEmail(ctx); err != nil {
+			log.Error("Fetching org members from Github", "error", err)
+			continue
+		}
 	}
 }
@@ -6,15 +6,22 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/navikt/ghep/internal/api"
 	"github.com/navikt/ghep/internal/events"
 	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/slack"
 	"github.com/navikt/ghep/internal/sql/gensql"
+	"github.com/navikt/ghep/internal/webhook"
 )
 
-func Run(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, githubClient github.Client, slackAPI slack.Client, subscribeToOrg bool) error {
+// webhookShutdownTimeout is how long pending deliveries to outgoing webhooks
+// get to finish after the API server has shut down.
+const webhookShutdownTimeout = 15 * time.Second
+
+// Run serves the API until ctx is done, which happens when Ghep is asked to stop.
+func Run(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, githubClients github.Clients, slackWorkspaces slack.Workspaces, notifiers slack.Notifiers, webhooks *webhook.Client, subscribeToOrg bool, resyncer *Resyncer, digests *DigestSender, personal *events.PersonalNotifier) error {
 	log.Info("Starting Ghep", "org", os.Getenv("GITHUB_ORG"))
 
 	webhookSecret := os.Getenv("GITHUB_WEBHOOK_SECRET")
@@ -30,22 +37,28 @@ func Run(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig m
 	log.Info("Teams using Ghep", "teams", strings.Join(teams, ", "))
 
 	log.Info("Ensuring Slack channels")
-	if err := slackAPI.EnsureChannels(teamConfig); err != nil {
+	if err := slackWorkspaces.EnsureChannels(teamConfig); err != nil {
 		return fmt.Errorf("ensuring Slack channels: %w", err)
 	}
 
 	log.Info("Creating event handler")
-	eventHandler := events.NewHandler(db, slackAPI, teamConfig)
-
-	apiClient := api.New(
-		log.With("client", "api"),
-		db,
-		eventHandler,
-		teamConfig,
-		webhookSecret,
-		os.Getenv("EXTERNAL_CONTRIBUTORS_CHANNEL"),
-		subscribeToOrg,
-	)
+	eventHandler := events.NewHandler(db, githubClients, notifiers, webhooks, teamConfig)
+
+	apiClient := api.New(log.With("client", "api"), api.Options{
+		DB:              db,
+		Events:          eventHandler,
+		TeamConfig:      teamConfig,
+		GithubClients:   githubClients,
+		SlackWorkspaces: slackWorkspaces,
+		Resyncer:        resyncer,
+		Digests:         digests,
+		Personal:        personal,
+
+		WebhookSecret:               webhookSecret,
+		AdminToken:                  os.Getenv("GHEP_ADMIN_TOKEN"),
+		ExternalContributorsChannel: os.Getenv("EXTERNAL_CONTRIBUTORS_CHANNEL"),
+		SubscribeToOrg:              subscribeToOrg,
+	})
 
 	addr := os.Getenv("SERVER_ADDR")
 	if addr == "" {
@@ -53,8 +66,15 @@ func Run(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig m
 	}
 
 	log.Info("Starting API server")
-	if err := apiClient.Run(os.Getenv("API_BASE_PATH"), addr); err != nil {
-		return fmt.Errorf("starting API server: %w", err)
+	if err := apiClient.Run(ctx, os.Getenv("API_BASE_PATH"), addr); err != nil {
+		return fmt.Errorf("running API server: %w", err)
+	}
+
+	// Events handled before the server stopped are still delivered to outgoing webhooks
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+	defer cancel()
+	if err := webhooks.Shutdown(shutdownCtx); err != nil {
+		log.Warn("Giving up on pending webhook deliveries", "error", err)
 	}
 
 	return nil
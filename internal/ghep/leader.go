@@ -9,6 +9,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/navikt/ghep/internal/email"
 	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/slack"
 	"github.com/navikt/ghep/internal/sql/gensql"
@@ -47,9 +48,12 @@ func RunLeaderSchedulers(
 	log *slog.Logger,
 	db *gensql.Queries,
 	teamConfig map[string]github.Team,
-	githubClient github.Client,
-	slackClient slack.Client,
+	githubClients github.Clients,
+	slackWorkspaces slack.Workspaces,
+	notifiers slack.Notifiers,
+	emailClient *email.Client,
 	personalDigestUsers []github.PersonalDigestUserEntry,
+	resyncer *Resyncer,
 ) {
 	var cancelSchedulers context.CancelFunc
 
@@ -69,11 +73,17 @@ func RunLeaderSchedulers(
 			schedulerCtx, cancel := context.WithCancel(ctx)
 			cancelSchedulers = cancel
 
-			go RunPersonalDigestScheduler(schedulerCtx, log.With("subsystem", "digest-personal"), db, slackClient, personalDigestUsers)
-			go RunPullRequestDigestScheduler(schedulerCtx, log.With("subsystem", "digest-pull-request"), db, teamConfig, githubClient, slackClient)
-			go RunSecurityDigestScheduler(schedulerCtx, log.With("subsystem", "digest-security"), db, teamConfig, githubClient, slackClient)
+			go RunResyncScheduler(schedulerCtx, log.With("subsystem", "resync"), resyncer)
+			go RunTeamEventRetentionScheduler(schedulerCtx, log.With("subsystem", "team-event-retention"), db)
+			go RunPersonalDigestScheduler(schedulerCtx, log.With("subsystem", "digest-personal"), db, slackWorkspaces, emailClient, personalDigestUsers)
+			go RunPullRequestDigestScheduler(schedulerCtx, log.With("subsystem", "digest-pull-request"), db, teamConfig, githubClients, notifiers, emailClient)
+			go RunSecurityDigestScheduler(schedulerCtx, log.With("subsystem", "digest-security"), db, teamConfig, githubClients, notifiers, emailClient)
+			go RunMuteExpiryScheduler(schedulerCtx, log.With("subsystem", "mute-expiry"), db, teamConfig, notifiers)
+			go RunSecurityEscalationScheduler(schedulerCtx, log.With("subsystem", "security-escalation"), db, teamConfig, notifiers)
+			go RunDeliveryWindowScheduler(schedulerCtx, log.With("subsystem", "delivery-window"), db, teamConfig, notifiers)
+			go RunDependencyRollupScheduler(schedulerCtx, log.With("subsystem", "dependency-rollup"), db, teamConfig, notifiers)
 		} else if !leader && cancelSchedulers != nil {
-			log.Info("Lost leadership, stopping digest schedulers")
+			log.Info("Lost leadership, stopping schedulers")
 			cancelSchedulers()
 			cancelSchedulers = nil
 		}
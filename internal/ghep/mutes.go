@@ -0,0 +1,79 @@
+package ghep
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// RunMuteExpiryScheduler reports how many events were suppressed when a mute
+// ends, either by expiring or by being ended early.
+func RunMuteExpiryScheduler(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, notifiers slack.Notifiers) {
+	log.Info("Starting mute expiry scheduler")
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := reportEndedMutes(ctx, log, db, teamConfig, notifiers); err != nil {
+				log.Error("Reporting ended mutes", "error", err)
+			}
+		}
+	}
+}
+
+func reportEndedMutes(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, notifiers slack.Notifiers) error {
+	mutes, err := db.ClaimEndedMutes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, mute := range mutes {
+		log := log.With("team", mute.TeamSlug, "repository", mute.Repository, "source_type", mute.SourceType, "suppressed", mute.Suppressed)
+		log.Info("Mute ended")
+
+		team, ok := teamConfig[mute.TeamSlug]
+		if !ok || mute.Suppressed == 0 {
+			continue
+		}
+
+		for _, source := range mutedSources(team, mute) {
+			notifier := team.NotifierForChannel(source.Channel)
+			message := slack.CreateMuteEndedMessage(source.Channel, mute)
+			if team.UsesBlockKit(notifier) {
+				message = slack.ToBlockKit(message)
+			}
+
+			if _, err := notifiers.Post(team, notifier, source.SourceType, message); err != nil {
+				log.Error("Posting mute ended message", "error", err, "channel", source.Channel)
+			}
+		}
+	}
+
+	return nil
+}
+
+// mutedSources returns the sources the mute covered, once per channel.
+func mutedSources(team github.Team, mute gensql.Mute) []github.Source {
+	var sources []github.Source
+	for _, source := range team.Sources {
+		if source.Channel == "" || slices.ContainsFunc(sources, func(s github.Source) bool { return s.Channel == source.Channel }) {
+			continue
+		}
+
+		if mute.SourceType == "" || mute.SourceType == source.SourceType {
+			sources = append(sources, source)
+		}
+	}
+
+	return sources
+}
@@ -4,18 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/email"
 	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/slack"
 	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
-func RunPersonalDigestScheduler(ctx context.Context, log *slog.Logger, db *gensql.Queries, slackClient slack.Client, users []github.PersonalDigestUserEntry) {
+func RunPersonalDigestScheduler(ctx context.Context, log *slog.Logger, db *gensql.Queries, slackWorkspaces slack.Workspaces, emailClient *email.Client, users []github.PersonalDigestUserEntry) {
 	if len(users) == 0 {
 		log.Info("No users configured for personal digest, scheduler not running")
 		return
@@ -36,7 +38,7 @@ func RunPersonalDigestScheduler(ctx context.Context, log *slog.Logger, db *gensq
 			now := t.Truncate(time.Microsecond)
 			for _, entry := range users {
 				go func(e github.PersonalDigestUserEntry) {
-					if err := maybeFirePersonalDigestForUser(ctx, log, db, slackClient, e, now); err != nil {
+					if err := maybeFirePersonalDigestForUser(ctx, log, db, slackWorkspaces, emailClient, e, now); err != nil {
 						log.Error("Sending personal digest", "login", e.Login, "error", err)
 					}
 				}(entry)
@@ -45,7 +47,7 @@ func RunPersonalDigestScheduler(ctx context.Context, log *slog.Logger, db *gensq
 	}
 }
 
-func maybeFirePersonalDigestForUser(ctx context.Context, log *slog.Logger, db *gensql.Queries, slackClient slack.Client, entry github.PersonalDigestUserEntry, now time.Time) error {
+func maybeFirePersonalDigestForUser(ctx context.Context, log *slog.Logger, db *gensql.Queries, slackWorkspaces slack.Workspaces, emailClient *email.Client, entry github.PersonalDigestUserEntry, now time.Time) error {
 	loc, err := time.LoadLocation(entry.Timezone)
 	if err != nil {
 		return err
@@ -53,7 +55,7 @@ func maybeFirePersonalDigestForUser(ctx context.Context, log *slog.Logger, db *g
 
 	local := now.In(loc)
 
-	targetWeekday, ok := weekdays[strings.ToLower(entry.Day)]
+	targetWeekday, ok := github.Weekdays[strings.ToLower(entry.Day)]
 	if !ok {
 		return nil
 	}
@@ -63,12 +65,10 @@ func maybeFirePersonalDigestForUser(ctx context.Context, log *slog.Logger, db *g
 	}
 
 	// Compute the exact scheduled time for today in the user's timezone
-	scheduledAt := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
-	parsed, err := time.ParseInLocation("15:04", entry.Time, loc)
+	scheduledAt, err := github.TimeOfDay(now, entry.Time, loc)
 	if err != nil {
 		return err
 	}
-	scheduledAt = scheduledAt.Add(time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute)
 
 	if now.Before(scheduledAt) {
 		return nil
@@ -97,10 +97,15 @@ func maybeFirePersonalDigestForUser(ctx context.Context, log *slog.Logger, db *g
 		return nil
 	}
 
-	return sendPersonalDigest(ctx, log, db, slackClient, entry.Login, now, prevSentAt)
+	slackClient, ok := slackWorkspaces.Get(entry.SlackWorkspace)
+	if !ok {
+		return fmt.Errorf("unknown Slack workspace %q", entry.SlackWorkspace)
+	}
+
+	return sendPersonalDigest(ctx, log, db, slackClient, emailClient, entry, now, prevSentAt)
 }
 
-func sendPersonalDigest(ctx context.Context, log *slog.Logger, db *gensql.Queries, slackClient slack.Client, login string, now time.Time, prevSentAt pgtype.Timestamptz) error {
+func sendPersonalDigest(ctx context.Context, log *slog.Logger, db *gensql.Queries, slackClient slack.Client, emailClient *email.Client, entry github.PersonalDigestUserEntry, now time.Time, prevSentAt pgtype.Timestamptz) error {
 	// Determine the time window: since last digest, or 7 days if never sent.
 	var since pgtype.Timestamptz
 	if prevSentAt.Valid {
@@ -110,7 +115,7 @@ func sendPersonalDigest(ctx context.Context, log *slog.Logger, db *gensql.Querie
 	}
 
 	repos, err := db.GetUserCommitsSince(ctx, gensql.GetUserCommitsSinceParams{
-		Login:        login,
+		Login:        entry.Login,
 		LastPushedAt: since,
 	})
 	if err != nil {
@@ -121,10 +126,23 @@ func sendPersonalDigest(ctx context.Context, log *slog.Logger, db *gensql.Querie
 		return nil
 	}
 
-	slackID, err := db.GetUserSlackID(ctx, login)
+	if len(entry.Email) > 0 {
+		message, err := email.CreatePersonalDigest(entry.Email, repos, now)
+		if err != nil {
+			return err
+		}
+		if err := emailClient.Send(message); err != nil {
+			return err
+		}
+	}
+
+	slackID, err := db.GetUserSlackID(ctx, gensql.GetUserSlackIDParams{
+		Workspace: entry.SlackWorkspace,
+		Login:     entry.Login,
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			log.Debug("No Slack ID for user, skipping personal digest", "login", login)
+			log.Debug("No Slack ID for user, skipping personal digest", "login", entry.Login)
 			return nil
 		}
 		return err
@@ -145,7 +163,7 @@ func sendPersonalDigest(ctx context.Context, log *slog.Logger, db *gensql.Querie
 		return err
 	}
 
-	log.Info("Personal digest sent", "login", login, "repos", len(repos))
+	log.Info("Personal digest sent", "login", entry.Login, "repos", len(repos))
 
 	return nil
 }
@@ -2,8 +2,8 @@ package ghep
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"slices"
 	"strings"
@@ -11,22 +11,13 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/email"
 	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/slack"
 	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
-var weekdays = map[string]time.Weekday{
-	"monday":    time.Monday,
-	"tuesday":   time.Tuesday,
-	"wednesday": time.Wednesday,
-	"thursday":  time.Thursday,
-	"friday":    time.Friday,
-	"saturday":  time.Saturday,
-	"sunday":    time.Sunday,
-}
-
-func RunPullRequestDigestScheduler(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, githubClient github.Client, slackClient slack.Client) {
+func RunPullRequestDigestScheduler(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, githubClients github.Clients, notifiers slack.Notifiers, emailClient *email.Client) {
 	type digestEntry struct {
 		teamSlug string
 		digest   *github.DigestConfig
@@ -59,7 +50,7 @@ func RunPullRequestDigestScheduler(ctx context.Context, log *slog.Logger, db *ge
 			now := t.Truncate(time.Microsecond)
 			for _, entry := range entries {
 				go func(e digestEntry) {
-					if err := maybeFireDigest(ctx, log, db, now, e.teamSlug, e.digest, teamConfig, githubClient, slackClient); err != nil {
+					if err := maybeFireDigest(ctx, log, db, now, e.teamSlug, e.digest, teamConfig, githubClients, notifiers, emailClient); err != nil {
 						log.Error("Sending digest", "team", e.teamSlug, "error", err)
 					}
 				}(entry)
@@ -68,7 +59,7 @@ func RunPullRequestDigestScheduler(ctx context.Context, log *slog.Logger, db *ge
 	}
 }
 
-func maybeFireDigest(ctx context.Context, log *slog.Logger, db *gensql.Queries, now time.Time, teamSlug string, digest *github.DigestConfig, teamConfig map[string]github.Team, githubClient github.Client, slackClient slack.Client) error {
+func maybeFireDigest(ctx context.Context, log *slog.Logger, db *gensql.Queries, now time.Time, teamSlug string, digest *github.DigestConfig, teamConfig map[string]github.Team, githubClients github.Clients, notifiers slack.Notifiers, emailClient *email.Client) error {
 	tz := digest.Timezone
 	if tz == "" {
 		tz = "Europe/Oslo"
@@ -81,7 +72,7 @@ func maybeFireDigest(ctx context.Context, log *slog.Logger, db *gensql.Queries,
 
 	local := now.In(loc)
 
-	targetWeekday, ok := weekdays[strings.ToLower(digest.Day)]
+	targetWeekday, ok := github.Weekdays[strings.ToLower(digest.Day)]
 	if !ok {
 		return nil
 	}
@@ -91,12 +82,10 @@ func maybeFireDigest(ctx context.Context, log *slog.Logger, db *gensql.Queries,
 	}
 
 	// Compute the exact scheduled time for today in the team's timezone
-	scheduledAt := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
-	parsed, err := time.ParseInLocation("15:04", digest.Time, loc)
+	scheduledAt, err := github.TimeOfDay(now, digest.Time, loc)
 	if err != nil {
 		return err
 	}
-	scheduledAt = scheduledAt.Add(time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute)
 
 	// Too early — scheduled time hasn't arrived yet
 	if now.Before(scheduledAt) {
@@ -123,14 +112,25 @@ func maybeFireDigest(ctx context.Context, log *slog.Logger, db *gensql.Queries,
 
 	log.Info("Sending weekly digest", "team", teamSlug, "channel", digest.Channel)
 
+	return sendPullRequestDigest(ctx, log, now, teamSlug, digest, teamConfig, githubClients, notifiers, emailClient)
+}
+
+// sendPullRequestDigest sends the digest right away, without claiming the
+// week's slot, so it can be used both by the scheduler and on demand.
+func sendPullRequestDigest(ctx context.Context, log *slog.Logger, now time.Time, teamSlug string, digest *github.DigestConfig, teamConfig map[string]github.Team, githubClients github.Clients, notifiers slack.Notifiers, emailClient *email.Client) error {
+	team := teamConfig[teamSlug]
+	githubClient, ok := githubClients.ForTeam(team)
+	if !ok {
+		return fmt.Errorf("no Github client for organization %s", team.Org)
+	}
+
 	repoPRs, err := githubClient.FetchOpenPullRequests(ctx, teamSlug)
 	if err != nil {
 		return err
 	}
 
 	// Filter out ignored repositories (global config)
-	team, exists := teamConfig[teamSlug]
-	if exists && len(team.Config.IgnoreRepositories) > 0 {
+	if len(team.Config.IgnoreRepositories) > 0 {
 		var filtered []github.RepoPRs
 		for _, repoPR := range repoPRs {
 			if !slices.Contains(team.Config.IgnoreRepositories, repoPR.RepoName) {
@@ -156,27 +156,21 @@ func maybeFireDigest(ctx context.Context, log *slog.Logger, db *gensql.Queries,
 	} else {
 		teamName := ""
 		if digest.SpecifyTeamName {
-			teamName = github.TitleCaseSlug(teamSlug)
+			teamName = github.TitleCaseSlug(team.Slug())
 		}
-		summary, threadMsgs := slack.CreatePullRequestDigestMessage(digest.Channel, teamName, repoPRs)
-
-		payload, err := json.Marshal(summary)
-		if err != nil {
-			return err
-		}
-
-		resp, err := slackClient.PostMessage(payload)
-		if err != nil {
-			return err
+		if digest.Channel != "" {
+			summary, threadMsgs := slack.CreatePullRequestDigestMessage(digest.Channel, teamName, team.UsesBlockKit(digest.Notifier), repoPRs)
+			if err := postDigestMessages(notifiers, team, digest.Notifier, github.NotifyKeyPullRequestDigest, summary, threadMsgs); err != nil {
+				return err
+			}
 		}
 
-		for _, threadMsg := range threadMsgs {
-			threadMsg.ThreadTimestamp = resp.Timestamp
-			threadPayload, err := json.Marshal(threadMsg)
+		if len(digest.Email) > 0 {
+			message, err := email.CreatePullRequestDigest(digest.Email, teamName, repoPRs, now)
 			if err != nil {
 				return err
 			}
-			if _, err := slackClient.PostMessage(threadPayload); err != nil {
+			if err := emailClient.Send(message.Redact(team.Config.Redaction)); err != nil {
 				return err
 			}
 		}
@@ -186,3 +180,64 @@ func maybeFireDigest(ctx context.Context, log *slog.Logger, db *gensql.Queries,
 
 	return nil
 }
+
+// DigestSender sends digests on demand, outside of their schedule.
+type DigestSender struct {
+	log           *slog.Logger
+	teamConfig    map[string]github.Team
+	githubClients github.Clients
+	notifiers     slack.Notifiers
+	emailClient   *email.Client
+}
+
+func NewDigestSender(log *slog.Logger, teamConfig map[string]github.Team, githubClients github.Clients, notifiers slack.Notifiers, emailClient *email.Client) *DigestSender {
+	return &DigestSender{
+		log:           log,
+		teamConfig:    teamConfig,
+		githubClients: githubClients,
+		notifiers:     notifiers,
+		emailClient:   emailClient,
+	}
+}
+
+// SendPullRequestDigest sends the team's pull request digest now. It does not
+// count as the weekly digest, which is still sent as scheduled.
+func (d *DigestSender) SendPullRequestDigest(ctx context.Context, teamSlug string) error {
+	team, ok := d.teamConfig[teamSlug]
+	if !ok {
+		return fmt.Errorf("unknown team %s", teamSlug)
+	}
+
+	if team.PullRequestDigest == nil {
+		return fmt.Errorf("team %s has no pull request digest configured", teamSlug)
+	}
+
+	log := d.log.With("team", teamSlug)
+	log.Info("Sending digest on demand", "channel", team.PullRequestDigest.Channel)
+
+	return sendPullRequestDigest(ctx, log, time.Now(), teamSlug, team.PullRequestDigest, d.teamConfig, d.githubClients, d.notifiers, d.emailClient)
+}
+
+// postDigestMessages posts the digest summary, with the details in a thread,
+// with secrets redacted like from events.
+func postDigestMessages(notifiers slack.Notifiers, team github.Team, notifier, key string, summary *slack.Message, threadMsgs []*slack.Message) error {
+	summary = slack.Redact(summary, team.Config.Redaction)
+
+	resp, err := notifiers.Post(team, notifier, key, summary)
+	if err != nil {
+		return err
+	}
+
+	for _, threadMsg := range threadMsgs {
+		// Messages outside of Slack can not be threaded, and are posted after the summary
+		if resp != nil {
+			threadMsg.ThreadTimestamp = resp.Timestamp
+		}
+		threadMsg = slack.Redact(threadMsg, team.Config.Redaction)
+		if _, err := notifiers.Post(team, notifier, key, threadMsg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
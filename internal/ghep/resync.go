@@ -0,0 +1,140 @@
+package ghep
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+const (
+	defaultResyncInterval = 6 * time.Hour
+	defaultResyncJitter   = 10 * time.Minute
+)
+
+// Resyncer keeps teams, repositories, members and Slack IDs in the database in
+// sync with Github and Slack. Resyncs only run on the leader, one at a time.
+type Resyncer struct {
+	log             *slog.Logger
+	db              *gensql.Queries
+	teamConfig      map[string]github.Team
+	githubClients   github.Clients
+	slackWorkspaces slack.Workspaces
+	subscribeToOrg  bool
+
+	running atomic.Bool
+	// leading is set while the resync scheduler runs on the leader, which
+	// also runs the resyncs triggered from the admin endpoint.
+	leading  atomic.Bool
+	triggers chan struct{}
+}
+
+func NewResyncer(log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, githubClients github.Clients, slackWorkspaces slack.Workspaces, subscribeToOrg bool) *Resyncer {
+	return &Resyncer{
+		log:             log,
+		db:              db,
+		teamConfig:      teamConfig,
+		githubClients:   githubClients,
+		slackWorkspaces: slackWorkspaces,
+		subscribeToOrg:  subscribeToOrg,
+		triggers:        make(chan struct{}, 1),
+	}
+}
+
+// Resync runs a full resync, and returns false if one was already running.
+func (r *Resyncer) Resync(ctx context.Context) bool {
+	if !r.running.CompareAndSwap(false, true) {
+		return false
+	}
+	defer r.running.Store(false)
+
+	r.resync(ctx)
+	return true
+}
+
+// Leading returns true if the resync scheduler runs on this pod, as only the
+// leader can be triggered.
+func (r *Resyncer) Leading() bool {
+	return r.leading.Load()
+}
+
+// Trigger asks the resync scheduler to resync right away, and returns false if
+// a resync is already running or waiting to run.
+func (r *Resyncer) Trigger() bool {
+	if r.running.Load() {
+		return false
+	}
+
+	select {
+	case r.triggers <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *Resyncer) resync(ctx context.Context) {
+	start := time.Now()
+	r.log.Info("Starting resync")
+
+	FetchGithubData(ctx, r.log.With("component", "fetch-teams"), r.db, r.teamConfig, r.githubClients, r.subscribeToOrg)
+	FetchSlackUsers(ctx, r.log.With("component", "fetch-slack"), r.db, r.teamConfig, r.slackWorkspaces)
+
+	r.log.Info("Resync done", "duration", time.Since(start).String())
+}
+
+// RunResyncScheduler resyncs immediately, and then every GHEP_RESYNC_INTERVAL
+// with up to GHEP_RESYNC_JITTER added to spread the load on Github and Slack,
+// or when triggered from the admin endpoint.
+func RunResyncScheduler(ctx context.Context, log *slog.Logger, resyncer *Resyncer) {
+	interval := durationFromEnv(log, "GHEP_RESYNC_INTERVAL", defaultResyncInterval)
+	if interval == 0 {
+		interval = defaultResyncInterval
+	}
+	jitter := durationFromEnv(log, "GHEP_RESYNC_JITTER", defaultResyncJitter)
+
+	log.Info("Starting resync scheduler", "interval", interval.String(), "jitter", jitter.String())
+
+	resyncer.leading.Store(true)
+	defer resyncer.leading.Store(false)
+
+	for {
+		if !resyncer.Resync(ctx) {
+			log.Info("Resync already running, skipping")
+		}
+
+		wait := interval
+		if jitter > 0 {
+			wait += rand.N(jitter) // #nosec G404 -- jitter does not need a secure random source
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		case <-resyncer.triggers:
+			log.Info("Resync triggered from admin endpoint")
+		}
+	}
+}
+
+func durationFromEnv(log *slog.Logger, key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		log.Warn("Invalid duration, using default", "env", key, "value", value, "default", fallback.String())
+		return fallback
+	}
+
+	return duration
+}
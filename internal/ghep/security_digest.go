@@ -2,20 +2,21 @@ package ghep
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/email"
 	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/slack"
 	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
-func RunSecurityDigestScheduler(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, githubClient github.Client, slackClient slack.Client) {
+func RunSecurityDigestScheduler(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, githubClients github.Clients, notifiers slack.Notifiers, emailClient *email.Client) {
 	type securityDigestEntry struct {
 		teamSlug string
 		digest   *github.SecurityDigestConfig
@@ -48,7 +49,7 @@ func RunSecurityDigestScheduler(ctx context.Context, log *slog.Logger, db *gensq
 			now := t.Truncate(time.Microsecond)
 			for _, entry := range entries {
 				go func(e securityDigestEntry) {
-					if err := maybeFireSecurityDigest(ctx, log, db, now, e.teamSlug, e.digest, teamConfig, githubClient, slackClient); err != nil {
+					if err := maybeFireSecurityDigest(ctx, log, db, now, e.teamSlug, e.digest, teamConfig, githubClients, notifiers, emailClient); err != nil {
 						log.Error("Sending security digest", "team", e.teamSlug, "error", err)
 					}
 				}(entry)
@@ -57,7 +58,7 @@ func RunSecurityDigestScheduler(ctx context.Context, log *slog.Logger, db *gensq
 	}
 }
 
-func maybeFireSecurityDigest(ctx context.Context, log *slog.Logger, db *gensql.Queries, now time.Time, teamSlug string, digest *github.SecurityDigestConfig, teamConfig map[string]github.Team, githubClient github.Client, slackClient slack.Client) error {
+func maybeFireSecurityDigest(ctx context.Context, log *slog.Logger, db *gensql.Queries, now time.Time, teamSlug string, digest *github.SecurityDigestConfig, teamConfig map[string]github.Team, githubClients github.Clients, notifiers slack.Notifiers, emailClient *email.Client) error {
 	tz := digest.Timezone
 	if tz == "" {
 		tz = "Europe/Oslo"
@@ -70,7 +71,7 @@ func maybeFireSecurityDigest(ctx context.Context, log *slog.Logger, db *gensql.Q
 
 	local := now.In(loc)
 
-	targetWeekday, ok := weekdays[strings.ToLower(digest.Day)]
+	targetWeekday, ok := github.Weekdays[strings.ToLower(digest.Day)]
 	if !ok {
 		return nil
 	}
@@ -80,12 +81,10 @@ func maybeFireSecurityDigest(ctx context.Context, log *slog.Logger, db *gensql.Q
 	}
 
 	// Compute the exact scheduled time for today in the team's timezone.
-	scheduledAt := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
-	parsed, err := time.ParseInLocation("15:04", digest.Time, loc)
+	scheduledAt, err := github.TimeOfDay(now, digest.Time, loc)
 	if err != nil {
 		return err
 	}
-	scheduledAt = scheduledAt.Add(time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute)
 
 	// Too early — scheduled time hasn't arrived yet.
 	if now.Before(scheduledAt) {
@@ -112,13 +111,13 @@ func maybeFireSecurityDigest(ctx context.Context, log *slog.Logger, db *gensql.Q
 
 	log.Info("Sending security digest", "team", teamSlug, "channel", digest.Channel)
 
-	team, exists := teamConfig[teamSlug]
-	var globalIgnore []string
-	if exists {
-		globalIgnore = team.Config.IgnoreRepositories
+	team := teamConfig[teamSlug]
+	githubClient, ok := githubClients.ForTeam(team)
+	if !ok {
+		return fmt.Errorf("no Github client for organization %s", team.Org)
 	}
 
-	repoAlerts, err := githubClient.FetchOpenSecurityAlerts(ctx, teamSlug, digest, globalIgnore)
+	repoAlerts, err := githubClient.FetchOpenSecurityAlerts(ctx, teamSlug, digest, team.Config.IgnoreRepositories)
 	if err != nil {
 		return err
 	}
@@ -128,32 +127,41 @@ func maybeFireSecurityDigest(ctx context.Context, log *slog.Logger, db *gensql.Q
 		totalAlerts += r.Total()
 	}
 
+	var trend *github.SecurityTrend
+	previous, err := previousSecurityDigest(ctx, db, teamSlug)
+	if err != nil {
+		log.Error("Getting previous security digest", "team", teamSlug, "error", err)
+	} else if previous != nil {
+		t := github.NewSecurityTrend(*previous, repoAlerts)
+		trend = &t
+	}
+
+	// Saved before the digest is sent, as the slot is already claimed, so the
+	// next digest compares with these alerts even if sending fails
+	if err := saveSecurityDigest(ctx, db, teamSlug, now, repoAlerts); err != nil {
+		log.Error("Saving security digest", "team", teamSlug, "error", err)
+	}
+
 	if totalAlerts == 0 && !digest.SendEmpty {
 		log.Info("No security alerts to digest", "team", teamSlug, "channel", digest.Channel)
 	} else {
 		teamName := ""
 		if digest.SpecifyTeamName {
-			teamName = github.TitleCaseSlug(teamSlug)
+			teamName = github.TitleCaseSlug(team.Slug())
 		}
-		summary, threadMsgs := slack.CreateSecurityDigestMessage(digest.Channel, teamName, repoAlerts)
-
-		payload, err := json.Marshal(summary)
-		if err != nil {
-			return err
-		}
-
-		resp, err := slackClient.PostMessage(payload)
-		if err != nil {
-			return err
+		if digest.Channel != "" {
+			summary, threadMsgs := slack.CreateSecurityDigestMessage(digest.Channel, teamName, team.UsesBlockKit(digest.Notifier), repoAlerts, trend)
+			if err := postDigestMessages(notifiers, team, digest.Notifier, github.NotifyKeySecurityDigest, summary, threadMsgs); err != nil {
+				return err
+			}
 		}
 
-		for _, threadMsg := range threadMsgs {
-			threadMsg.ThreadTimestamp = resp.Timestamp
-			threadPayload, err := json.Marshal(threadMsg)
+		if len(digest.Email) > 0 {
+			message, err := email.CreateSecurityDigest(digest.Email, teamName, repoAlerts, trend, now)
 			if err != nil {
 				return err
 			}
-			if _, err := slackClient.PostMessage(threadPayload); err != nil {
+			if err := emailClient.Send(message.Redact(team.Config.Redaction)); err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,106 @@
+package ghep
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// RunSecurityEscalationScheduler escalates security alerts left open past the
+// deadline in the team's SLA.
+func RunSecurityEscalationScheduler(ctx context.Context, log *slog.Logger, db *gensql.Queries, teamConfig map[string]github.Team, notifiers slack.Notifiers) {
+	log.Info("Starting security escalation scheduler")
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticker.C:
+			if err := escalateOverdueAlerts(ctx, log, db, t, teamConfig, notifiers); err != nil {
+				log.Error("Escalating security alerts", "error", err)
+			}
+		}
+	}
+}
+
+func escalateOverdueAlerts(ctx context.Context, log *slog.Logger, db *gensql.Queries, now time.Time, teamConfig map[string]github.Team, notifiers slack.Notifiers) error {
+	alerts, err := db.ListUnescalatedSecurityAlerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, alert := range alerts {
+		team, ok := teamConfig[alert.TeamSlug]
+		if !ok || team.Config.SecuritySLA == nil {
+			continue
+		}
+
+		sla := team.Config.SecuritySLA
+		severity := github.AsSeverityType(alert.Severity)
+		deadline, ok := sla.Deadline(severity, alert.FirstSeenAt.Time)
+		if !ok || now.Before(deadline) {
+			continue
+		}
+
+		// Claim the alert first, so it is escalated once even if posting fails
+		claimed, err := db.MarkSecurityAlertEscalated(ctx, gensql.MarkSecurityAlertEscalatedParams{
+			TeamSlug: alert.TeamSlug,
+			AlertUrl: alert.AlertUrl,
+		})
+		if err != nil {
+			return err
+		}
+		if claimed == 0 {
+			continue
+		}
+
+		log := log.With("team", alert.TeamSlug, "alert", alert.AlertUrl, "severity", alert.Severity)
+		log.Info("Escalating security alert past deadline")
+		escalateAlert(ctx, log, db, now, team, alert, sla.Days(severity), notifiers)
+	}
+
+	return nil
+}
+
+// escalateAlert posts in the threads the alert was posted to, and in the
+// team's escalation channel.
+func escalateAlert(ctx context.Context, log *slog.Logger, db *gensql.Queries, now time.Time, team github.Team, alert gensql.SecurityAlert, days int, notifiers slack.Notifiers) {
+	threads, err := db.ListSlackMessagesByEvent(ctx, gensql.ListSlackMessagesByEventParams{
+		TeamSlug: team.Name,
+		EventID:  alert.AlertUrl,
+	})
+	if err != nil {
+		log.Error("Listing alert threads", "error", err)
+	}
+
+	// Threads are only stored for messages posted to Slack
+	for _, thread := range threads {
+		message := slack.CreateSecurityAlertEscalationMessage(thread.Channel, thread.ThreadTs, alert, days, now)
+		if team.UsesBlockKit(github.NotifierSlack) {
+			message = slack.ToBlockKit(message)
+		}
+
+		if _, err := notifiers.Post(team, github.NotifierSlack, github.NotifyKeySecurityEscalation, message); err != nil {
+			log.Error("Posting escalation", "error", err, "channel", message.Channel)
+		}
+	}
+
+	if channel := team.Config.SecuritySLA.EscalationChannel; channel != "" {
+		notifier := team.NotifierForChannel(channel)
+		message := slack.CreateSecurityAlertEscalationMessage(channel, "", alert, days, now)
+		if team.UsesBlockKit(notifier) {
+			message = slack.ToBlockKit(message)
+		}
+
+		if _, err := notifiers.Post(team, notifier, github.NotifyKeySecurityEscalation, message); err != nil {
+			log.Error("Posting escalation", "error", err, "channel", message.Channel)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package ghep
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// previousSecurityDigest returns what the team's previous security digest saw,
+// or nil if this is the team's first digest.
+func previousSecurityDigest(ctx context.Context, db *gensql.Queries, teamSlug string) (*github.SecurityDigestSnapshot, error) {
+	digest, err := db.GetLatestSecurityDigest(ctx, teamSlug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	counts, err := db.ListSecurityDigestCounts(ctx, digest.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts, err := db.ListSecurityDigestAlerts(ctx, digest.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &github.SecurityDigestSnapshot{
+		Open: map[string]int{},
+	}
+	for _, count := range counts {
+		snapshot.Open[count.Repository] += int(count.Count)
+	}
+	for _, alert := range alerts {
+		snapshot.Alerts = append(snapshot.Alerts, alert.AlertUrl)
+		if alert.Critical {
+			snapshot.Critical = append(snapshot.Critical, alert.AlertUrl)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// saveSecurityDigest stores the open alerts per repository, type and
+// severity, for the trends in the next digest. It is stored in a transaction,
+// so the next digest never compares with half a digest.
+func saveSecurityDigest(ctx context.Context, db *gensql.Queries, teamSlug string, now time.Time, repoAlerts []github.RepoSecurityAlerts) error {
+	return db.InTx(ctx, func(db *gensql.Queries) error {
+		digestID, err := db.CreateSecurityDigest(ctx, gensql.CreateSecurityDigestParams{
+			TeamSlug: teamSlug,
+			SentAt:   pgtype.Timestamptz{Time: now, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		type key struct {
+			alertType string
+			severity  string
+		}
+
+		for _, repo := range repoAlerts {
+			counts := map[key]int32{}
+			for _, alert := range repo.All() {
+				severity := ""
+				if alert.AlertType != github.AlertTypeSecretScanning {
+					severity = github.AsSeverityType(alert.Severity).Name()
+				}
+				counts[key{alertType: alert.AlertType.Name(), severity: severity}]++
+
+				if err := db.CreateSecurityDigestAlert(ctx, gensql.CreateSecurityDigestAlertParams{
+					DigestID: digestID,
+					AlertUrl: alert.URL,
+					Critical: alert.IsCritical(),
+				}); err != nil {
+					return err
+				}
+			}
+
+			for k, count := range counts {
+				if err := db.CreateSecurityDigestCount(ctx, gensql.CreateSecurityDigestCountParams{
+					DigestID:   digestID,
+					Repository: repo.Repository.Name,
+					AlertType:  k.alertType,
+					Severity:   k.severity,
+					Count:      count,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,34 @@
+package ghep
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// teamEventRetention is how long events are kept for the feeds.
+const teamEventRetention = 90 * 24 * time.Hour
+
+// RunTeamEventRetentionScheduler deletes events older than 90 days from the
+// feeds, on startup and then every hour.
+func RunTeamEventRetentionScheduler(ctx context.Context, log *slog.Logger, db *gensql.Queries) {
+	log.Info("Starting team event retention scheduler")
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if err := db.DeleteTeamEventsBefore(ctx, pgtype.Timestamptz{Time: time.Now().Add(-teamEventRetention), Valid: true}); err != nil {
+			log.Error("Deleting old team events", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+)
+
+// DependabotDismissReasons are the reasons Github accepts when dismissing a Dependabot alert.
+var DependabotDismissReasons = []string{"fix_started", "inaccurate", "no_bandwidth", "not_used", "tolerable_risk"}
+
+// DismissDependabotAlert dismisses a Dependabot alert in one of the organization's repositories.
+func (c Client) DismissDependabotAlert(ctx context.Context, repository string, number int, reason, comment string) error {
+	if !slices.Contains(DependabotDismissReasons, reason) {
+		return fmt.Errorf("invalid dismiss reason %q", reason)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"state":             "dismissed",
+		"dismissed_reason":  reason,
+		"dismissed_comment": comment,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/dependabot/alerts/%d", c.urls.API, c.org, repository, number)
+	return c.doAction(ctx, http.MethodPatch, url, body)
+}
+
+// RerunFailedJobs re-runs the failed jobs, and the jobs depending on them, in a workflow run.
+func (c Client) RerunFailedJobs(ctx context.Context, repository string, runID int) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/actions/runs/%d/rerun-failed-jobs", c.urls.API, c.org, repository, runID)
+	return c.doAction(ctx, http.MethodPost, url, nil)
+}
+
+func (c Client) doAction(ctx context.Context, method, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Add("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		var ghErr githubError
+		if err := json.Unmarshal(respBody, &ghErr); err != nil || ghErr.Message == "" {
+			return fmt.Errorf("request failed (%v): %s", resp.Status, respBody)
+		}
+		return fmt.Errorf("request failed (%v): %s", resp.Status, ghErr.Message)
+	}
+
+	return nil
+}
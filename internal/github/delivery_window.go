@@ -0,0 +1,165 @@
+package github
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	deliveryWindowDefaultTimezone = "Europe/Oslo"
+	deliveryWindowDefaultFrom     = "08:00"
+	deliveryWindowDefaultTo       = "16:00"
+)
+
+// Weekdays maps the weekday names used in teams.yaml to time.Weekday.
+var Weekdays = map[string]time.Weekday{
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"sunday":    time.Sunday,
+}
+
+// TimeOfDay returns the time of day, in HH:MM format, on the day of t in the
+// location. It is used by the digest schedulers and delivery windows alike.
+func TimeOfDay(t time.Time, clock string, loc *time.Location) (time.Time, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), parsed.Hour(), parsed.Minute(), 0, 0, loc), nil
+}
+
+// DeliveryWindow is when a team's or source's events are posted. Events outside
+// the window are queued, and posted as a summary when the window opens.
+type DeliveryWindow struct {
+	Timezone string `yaml:"timezone"`
+	// Days are the weekdays events are posted, where empty is Monday to Friday.
+	Days []string `yaml:"days"`
+	From string   `yaml:"from"`
+	To   string   `yaml:"to"`
+	// Bypass are event types posted right away, like secret_scanning_alert.
+	Bypass []string `yaml:"bypass"`
+	// BypassSeverity posts security alerts of at least this severity right away.
+	BypassSeverity string `yaml:"bypassSeverity"`
+}
+
+// IsOpen returns true if events can be posted at the given time. Windows are
+// validated when teams.yaml is parsed, so an error means the window was never
+// validated.
+func (w DeliveryWindow) IsOpen(now time.Time) (bool, error) {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return false, err
+	}
+
+	if !slices.ContainsFunc(w.Days, func(day string) bool { return Weekdays[strings.ToLower(day)] == now.In(loc).Weekday() }) {
+		return false, nil
+	}
+
+	from, err := TimeOfDay(now, w.From, loc)
+	if err != nil {
+		return false, err
+	}
+
+	to, err := TimeOfDay(now, w.To, loc)
+	if err != nil {
+		return false, err
+	}
+
+	return !now.Before(from) && now.Before(to), nil
+}
+
+// Bypasses returns true if the event, handled as the event type, should be
+// posted even when the window is closed.
+func (w DeliveryWindow) Bypasses(eventType EventType, event Event) bool {
+	if slices.Contains(w.Bypass, eventType.Name()) {
+		return true
+	}
+
+	if w.BypassSeverity == "" {
+		return false
+	}
+
+	severity, ok := event.AlertSeverity()
+	if !ok {
+		severity, ok = event.RegressionSeverity(eventType)
+	}
+
+	return ok && severity >= AsSeverityType(w.BypassSeverity)
+}
+
+// AlertSeverity returns the severity of a code scanning or Dependabot alert.
+func (e Event) AlertSeverity() (SeverityType, bool) {
+	switch e.GetEventType() {
+	case TypeCodeScanningAlert:
+		return e.Alert.Rule.SeverityType(), true
+	case TypeDependabotAlert:
+		return e.Alert.SecurityAdvisory.SeverityType(), true
+	}
+
+	return SeverityLow, false
+}
+
+func applyDeliveryWindowDefaults(w *DeliveryWindow) error {
+	if w.Timezone == "" {
+		w.Timezone = deliveryWindowDefaultTimezone
+	}
+	if len(w.Days) == 0 {
+		w.Days = []string{"monday", "tuesday", "wednesday", "thursday", "friday"}
+	}
+	if w.From == "" {
+		w.From = deliveryWindowDefaultFrom
+	}
+	if w.To == "" {
+		w.To = deliveryWindowDefaultTo
+	}
+
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return fmt.Errorf("timezone %q is not a valid IANA timezone", w.Timezone)
+	}
+	for _, day := range w.Days {
+		if _, ok := Weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("day %q is not a valid weekday", day)
+		}
+	}
+
+	from, err := time.Parse("15:04", w.From)
+	if err != nil {
+		return fmt.Errorf("from %q must be in HH:MM format", w.From)
+	}
+	to, err := time.Parse("15:04", w.To)
+	if err != nil {
+		return fmt.Errorf("to %q must be in HH:MM format", w.To)
+	}
+	if !from.Before(to) {
+		return fmt.Errorf("from %s must be before to %s", w.From, w.To)
+	}
+
+	for _, eventType := range w.Bypass {
+		if !slices.Contains(eventTypeNames(), eventType) {
+			return fmt.Errorf("bypass %q must be one of %s", eventType, strings.Join(eventTypeNames(), ", "))
+		}
+	}
+	if w.BypassSeverity != "" && !slices.Contains([]string{"low", "medium", "high", "critical"}, strings.ToLower(w.BypassSeverity)) {
+		return fmt.Errorf("bypassSeverity %q must be one of low, medium, high, critical", w.BypassSeverity)
+	}
+
+	return nil
+}
+
+// eventTypeNames returns the names of the known event types.
+func eventTypeNames() []string {
+	var names []string
+	for eventType := TypeCommit; eventType < TypeUnknown; eventType++ {
+		names = append(names, eventType.Name())
+	}
+
+	return names
+}
@@ -0,0 +1,161 @@
+package github
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeliveryWindowIsOpen(t *testing.T) {
+	window := DeliveryWindow{}
+	if err := applyDeliveryWindowDefaults(&window); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "working hours",
+			now:      time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name: "before working hours in Oslo",
+			now:  time.Date(2026, 10, 19, 5, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "at end of working hours",
+			now:  time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekend",
+			now:  time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := window.IsOpen(tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("IsOpen() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDeliveryWindowIsOpenUnvalidated(t *testing.T) {
+	window := DeliveryWindow{Timezone: "Europe/Bergen", Days: []string{"monday"}, From: "08:00", To: "16:00"}
+	if _, err := window.IsOpen(time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("expected an error for an unknown timezone")
+	}
+}
+
+func TestDeliveryWindowBypasses(t *testing.T) {
+	secretType := "GitHub Personal Access Token"
+	window := DeliveryWindow{Bypass: []string{"secret_scanning_alert"}, BypassSeverity: "critical"}
+
+	tests := []struct {
+		name      string
+		eventType EventType
+		event     Event
+		expected  bool
+	}{
+		{
+			name:      "bypassed event type",
+			eventType: TypeSecretScanningAlert,
+			event:     Event{Alert: &Alert{SecretType: &secretType}},
+			expected:  true,
+		},
+		{
+			name:      "critical Dependabot alert",
+			eventType: TypeDependabotAlert,
+			event:     Event{Alert: &Alert{SecurityAdvisory: &SecurityAdvisory{Severity: "critical"}}},
+			expected:  true,
+		},
+		{
+			name:      "high Dependabot alert",
+			eventType: TypeDependabotAlert,
+			event:     Event{Alert: &Alert{SecurityAdvisory: &SecurityAdvisory{Severity: "high"}}},
+		},
+		{
+			name:      "workflow",
+			eventType: TypeWorkflow,
+			event:     Event{Workflow: &Workflow{}},
+		},
+		{
+			name:      "force push to the default branch",
+			eventType: TypeForcePush,
+			event:     Event{Ref: "refs/heads/main", Forced: true, Repository: &Repository{DefaultBranch: "main"}},
+		},
+		{
+			name:      "secret scanning disabled",
+			eventType: TypeSecurityAndAnalysis,
+			event: Event{
+				Repository: &Repository{SecurityAndAnalysis: SecurityAndAnalysis{"secret_scanning": {Status: "disabled"}}},
+				Changes:    securityAndAnalysisChanges(SecurityAndAnalysis{"secret_scanning": {Status: "enabled"}}),
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := window.Bypasses(tt.eventType, tt.event); got != tt.expected {
+				t.Errorf("Bypasses() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyDeliveryWindowDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  DeliveryWindow
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			window: DeliveryWindow{},
+		},
+		{
+			name:    "unknown timezone",
+			window:  DeliveryWindow{Timezone: "Europe/Bergen"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown day",
+			window:  DeliveryWindow{Days: []string{"funday"}},
+			wantErr: true,
+		},
+		{
+			name:    "from after to",
+			window:  DeliveryWindow{From: "16:00", To: "08:00"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown bypass",
+			window:  DeliveryWindow{Bypass: []string{"security"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := applyDeliveryWindowDefaults(&tt.window); (err != nil) != tt.wantErr {
+				t.Errorf("applyDeliveryWindowDefaults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func securityAndAnalysisChanges(from SecurityAndAnalysis) *Changes {
+	changes := &Changes{}
+	changes.From.SecurityAndAnalysis = from
+	return changes
+}
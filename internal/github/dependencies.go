@@ -0,0 +1,28 @@
+package github
+
+import "strings"
+
+func (u User) IsRenovate() bool {
+	return u.Login == "renovate[bot]"
+}
+
+// IsDependencyBot returns true for the bots opening dependency pull requests.
+func (u User) IsDependencyBot() bool {
+	return u.IsDependabot() || u.IsRenovate()
+}
+
+func (a Author) IsDependencyBot() bool {
+	return a.IsDependabot() || a.Username == "renovate[bot]"
+}
+
+// Ecosystem returns the package ecosystem of a dependency pull request, from
+// the dependabot/<ecosystem>/<dependency> branches Dependabot opens them from.
+// Renovate's branches have no ecosystem, so its pull requests are "other".
+func (i Issue) Ecosystem() string {
+	parts := strings.Split(i.Head.Ref, "/")
+	if len(parts) >= 3 && parts[0] == "dependabot" {
+		return parts[1]
+	}
+
+	return "other"
+}
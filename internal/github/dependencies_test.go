@@ -0,0 +1,53 @@
+package github
+
+import "testing"
+
+func TestEcosystem(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "dependabot/npm_and_yarn/vite-6.0.1", want: "npm_and_yarn"},
+		{ref: "dependabot/gradle/org.jetbrains.kotlin-kotlin-stdlib-2.1.0", want: "gradle"},
+		{ref: "renovate/vite-6.x", want: "other"},
+		{ref: "main", want: "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			if got := (Issue{Head: IssueBase{Ref: tt.ref}}).Ecosystem(); got != tt.want {
+				t.Errorf("Ecosystem() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDependencyRollup(t *testing.T) {
+	rollup := &DependencyRollupConfig{Channel: "#dependencies", Time: "09:00"}
+
+	tests := []struct {
+		name    string
+		silence DependabotConfig
+		rollup  *DependencyRollupConfig
+		wantErr bool
+	}{
+		{name: "not configured"},
+		{name: "silenced", silence: DependabotConfigAlways},
+		{name: "rolled up", silence: DependabotConfigRollup, rollup: rollup},
+		{name: "rollup without config", silence: DependabotConfigRollup, wantErr: true},
+		{name: "config without rollup", silence: DependabotConfigAlways, rollup: rollup, wantErr: true},
+		{name: "unknown mode is ignored", silence: "sometimes"},
+		{name: "config with unknown mode", silence: "sometimes", rollup: rollup, wantErr: true},
+		{name: "missing channel", silence: DependabotConfigRollup, rollup: &DependencyRollupConfig{Time: "09:00"}, wantErr: true},
+		{name: "invalid time", silence: DependabotConfigRollup, rollup: &DependencyRollupConfig{Channel: "#dependencies", Time: "9"}, wantErr: true},
+		{name: "invalid timezone", silence: DependabotConfigRollup, rollup: &DependencyRollupConfig{Channel: "#dependencies", Time: "09:00", Timezone: "Nowhere"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateDependencyRollup("nada", tt.silence, tt.rollup); (err != nil) != tt.wantErr {
+				t.Errorf("validateDependencyRollup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
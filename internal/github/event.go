@@ -5,9 +5,10 @@ package github
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 type (
@@ -31,6 +32,12 @@ const (
 	TypeSecretScanningAlert
 	TypeTeam
 	TypeWorkflow
+	TypeRepositoryAdvisory
+	TypeSecurityAndAnalysis
+	TypeBranchProtectionRule
+	TypeRepositoryRuleset
+	// TypeForcePush is a force push to the default branch, which is also a TypeCommit.
+	TypeForcePush
 	TypeUnknown
 
 	SeverityLow SeverityType = iota
@@ -86,13 +93,13 @@ func isBot(username string) bool {
 	return strings.HasSuffix(username, "[bot]") || strings.HasSuffix(username, "bot")
 }
 
-func (a Author) AsUser() User {
+func (a Author) AsUser(webURL string) User {
 	var user User
 
 	if a.IsBot() {
 		user.Login = a.Username
 		user.Type = "Bot"
-		user.URL = "https://github.com/apps/" + strings.TrimSuffix(a.Username, "[bot]")
+		user.URL = webURL + "/apps/" + strings.TrimSuffix(a.Username, "[bot]")
 	} else {
 		user.Login = a.Username
 		user.Type = "User"
@@ -100,7 +107,7 @@ func (a Author) AsUser() User {
 		if a.Username == "" {
 			user.Login = a.Name
 		} else {
-			user.URL = "https://github.com/" + a.Username
+			user.URL = webURL + "/" + a.Username
 		}
 	}
 
@@ -113,6 +120,9 @@ type Changes struct {
 			From string `json:"from"`
 		} `json:"name"`
 	} `json:"repository"`
+	From struct {
+		SecurityAndAnalysis SecurityAndAnalysis `json:"security_and_analysis"`
+	} `json:"from"`
 }
 
 // ToSlack returns a formatted string for Slack
@@ -127,11 +137,24 @@ func (r Repository) ToSlack() string {
 }
 
 type Repository struct {
-	Name          string `json:"name"`
-	FullName      string `json:"full_name"`
-	URL           string `json:"html_url"`
-	DefaultBranch string `json:"default_branch"`
-	RoleName      string `json:"role_name"`
+	Name                string              `json:"name"`
+	FullName            string              `json:"full_name"`
+	URL                 string              `json:"html_url"`
+	DefaultBranch       string              `json:"default_branch"`
+	RoleName            string              `json:"role_name"`
+	Private             bool                `json:"private"`
+	Visibility          string              `json:"visibility"`
+	SecurityAndAnalysis SecurityAndAnalysis `json:"security_and_analysis"`
+}
+
+// IsPublic returns true for public repositories. Only the visibility tells
+// internal repositories apart from public ones, as neither are private.
+func (r Repository) IsPublic() bool {
+	if r.Visibility != "" {
+		return r.Visibility == "public"
+	}
+
+	return !r.Private
 }
 
 type Commit struct {
@@ -166,21 +189,79 @@ func AsSeverityType(level string) SeverityType {
 	}
 }
 
+// Name returns the severity as used by Github, like critical.
+func (s SeverityType) Name() string {
+	switch s {
+	case SeverityCritical:
+		return "critical"
+	case SeverityHigh:
+		return "high"
+	case SeverityMedium:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
 type Tool struct {
 	Name string `json:"name"`
 }
 
 type Alert struct {
+	Number            int               `json:"number"`
 	PubliclyLeaked    bool              `json:"publicly_leaked"`
 	SecretType        *string           `json:"secret_type_display_name"`
 	State             string            `json:"state"`
 	URL               string            `json:"html_url"`
+	CreatedAt         time.Time         `json:"created_at"`
 	Resolution        string            `json:"resolution"`
 	ResolutionComment string            `json:"resolution_comment"`
 	ResolvedBy        User              `json:"resolved_by"`
 	Rule              Rule              `json:"rule"`
 	Tool              *Tool             `json:"tool"`
 	SecurityAdvisory  *SecurityAdvisory `json:"security_advisory"`
+	// MostRecentInstance is where a code scanning alert was last found.
+	MostRecentInstance *AlertInstance `json:"most_recent_instance"`
+}
+
+type AlertInstance struct {
+	Ref       string        `json:"ref"`
+	CommitSHA string        `json:"commit_sha"`
+	Location  AlertLocation `json:"location"`
+}
+
+// Branch returns the branch the alert was found on, or an empty string if it was not found on a branch.
+func (i AlertInstance) Branch() string {
+	branch, ok := strings.CutPrefix(i.Ref, RefHeadsPrefix)
+	if !ok {
+		return ""
+	}
+
+	return branch
+}
+
+type AlertLocation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// Lines returns the lines of the location, like 13-22, or 13 for a single line.
+func (l AlertLocation) Lines() string {
+	if l.EndLine <= l.StartLine {
+		return strconv.Itoa(l.StartLine)
+	}
+
+	return fmt.Sprintf("%d-%d", l.StartLine, l.EndLine)
+}
+
+// Anchor returns the anchor linking to the lines of the location in a file on Github, like #L13-L22.
+func (l AlertLocation) Anchor() string {
+	if l.EndLine <= l.StartLine {
+		return fmt.Sprintf("#L%d", l.StartLine)
+	}
+
+	return fmt.Sprintf("#L%d-L%d", l.StartLine, l.EndLine)
 }
 
 type RequestTeam struct {
@@ -207,6 +288,7 @@ type Issue struct {
 	Merged             bool          `json:"merged"`
 	User               User          `json:"user"`
 	Base               IssueBase     `json:"base"`
+	Head               IssueBase     `json:"head"`
 	RequestedReviewers []User        `json:"requested_reviewers"`
 	RequestedTeams     []RequestTeam `json:"requested_teams"`
 	Assignees          []User        `json:"assignees"`
@@ -257,6 +339,33 @@ func (s SecurityAdvisory) SeverityType() SeverityType {
 	return AsSeverityType(s.Severity)
 }
 
+// RepositoryAdvisory is a security advisory for one of the organization's
+// repositories, drafted by its maintainers or reported privately.
+type RepositoryAdvisory struct {
+	GHSAID      string `json:"ghsa_id"`
+	CVEID       string `json:"cve_id"`
+	URL         string `json:"html_url"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	// Severity is empty for reports where the reporter did not set one.
+	Severity string `json:"severity"`
+	State    string `json:"state"`
+	Author   User   `json:"author"`
+	// Submission is set for advisories reported through private vulnerability reporting.
+	Submission *struct {
+		Accepted bool `json:"accepted"`
+	} `json:"submission"`
+}
+
+func (a RepositoryAdvisory) SeverityType() SeverityType {
+	return AsSeverityType(a.Severity)
+}
+
+// IsReport returns true if the advisory was reported through private vulnerability reporting.
+func (a RepositoryAdvisory) IsReport() bool {
+	return a.Submission != nil
+}
+
 type FailedJob struct {
 	Name string
 	URL  string
@@ -264,7 +373,8 @@ type FailedJob struct {
 }
 
 type WorkflowPR struct {
-	ID int `json:"id"`
+	ID     int `json:"id"`
+	Number int `json:"number"`
 }
 
 type Workflow struct {
@@ -280,59 +390,26 @@ type Workflow struct {
 	JobsURL      string `json:"jobs_url"`
 	FailedJob    FailedJob
 	PullRequests []WorkflowPR `json:"pull_requests"`
+	HeadCommit   *HeadCommit  `json:"head_commit"`
 }
 
-// UpdateFailedJob finds and update the failed job in a workflow
-func (w *Workflow) UpdateFailedJob() error {
-	type Workflow struct {
-		Jobs []struct {
-			Name       string `json:"name"`
-			URL        string `json:"html_url"`
-			Conclusion string `json:"conclusion"`
-			Steps      []struct {
-				Name        string `json:"name"`
-				Conclusions string `json:"conclusion"`
-			} `json:"steps"`
-		} `json:"jobs"`
-	}
-
-	httpClient := http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := httpClient.Get(w.JobsURL)
-	if err != nil {
-		return fmt.Errorf("getting jobs: %w", err)
-	}
-
-	defer resp.Body.Close()
-
-	var jobs Workflow
-	if err := json.NewDecoder(resp.Body).Decode(&jobs); err != nil {
-		return fmt.Errorf("decoding jobs: %w", err)
-	}
-
-	for _, job := range jobs.Jobs {
-		if job.Conclusion == "failure" {
-			for _, step := range job.Steps {
-				if step.Conclusions == "failure" {
-					w.FailedJob = FailedJob{
-						Name: job.Name,
-						URL:  job.URL,
-						Step: step.Name,
-					}
-
-					return nil
-				}
-			}
-		}
-	}
+type Review struct {
+	State string `json:"state"`
+	URL   string `json:"html_url"`
+	User  User   `json:"user"`
+}
 
-	return nil
+// Comment is a comment on an issue or pull request, or a review comment on a pull request.
+type Comment struct {
+	Body string `json:"body"`
+	URL  string `json:"html_url"`
+	User User   `json:"user"`
 }
 
-type Review struct {
-	State string `json:"state"`
+// HeadCommit is the commit a workflow run ran on.
+type HeadCommit struct {
+	ID     string `json:"id"`
+	Author Author `json:"author"`
 }
 
 func (e Event) IsCommit() bool {
@@ -344,25 +421,62 @@ func (e Event) IsCodeQLWorkflow() bool {
 }
 
 type Event struct {
-	Action              string            `json:"action"`
-	Alert               *Alert            `json:"alert"`
-	Ref                 string            `json:"ref"`
-	After               string            `json:"after"`
-	Repository          *Repository       `json:"repository"`
-	Changes             *Changes          `json:"changes"`
-	Commits             []Commit          `json:"commits"`
-	Compare             string            `json:"compare"`
-	Issue               *Issue            `json:"issue"`
-	PullRequest         *Issue            `json:"pull_request"`
-	Release             *Release          `json:"release"`
-	RepositoriesRemoved []Repository      `json:"repositories_removed"`
-	Review              *Review           `json:"review"`
-	Sender              User              `json:"sender"`
-	Team                *TeamEvent        `json:"team"`
-	Member              User              `json:"member"`
-	Membership          Membership        `json:"membership"`
-	SecurityAdvisory    *SecurityAdvisory `json:"security_advisory"`
-	Workflow            *Workflow         `json:"workflow_run"`
+	Action               string                `json:"action"`
+	Alert                *Alert                `json:"alert"`
+	Ref                  string                `json:"ref"`
+	Forced               bool                  `json:"forced"`
+	After                string                `json:"after"`
+	Repository           *Repository           `json:"repository"`
+	Changes              *Changes              `json:"changes"`
+	Commits              []Commit              `json:"commits"`
+	Compare              string                `json:"compare"`
+	Issue                *Issue                `json:"issue"`
+	PullRequest          *Issue                `json:"pull_request"`
+	Release              *Release              `json:"release"`
+	RepositoriesRemoved  []Repository          `json:"repositories_removed"`
+	Review               *Review               `json:"review"`
+	RequestedReviewer    *User                 `json:"requested_reviewer"`
+	Comment              *Comment              `json:"comment"`
+	Sender               User                  `json:"sender"`
+	Team                 *TeamEvent            `json:"team"`
+	Member               User                  `json:"member"`
+	Membership           Membership            `json:"membership"`
+	SecurityAdvisory     *SecurityAdvisory     `json:"security_advisory"`
+	RepositoryAdvisory   *RepositoryAdvisory   `json:"repository_advisory"`
+	BranchProtectionRule *BranchProtectionRule `json:"rule"`
+	RepositoryRuleset    *RepositoryRuleset    `json:"repository_ruleset"`
+	Workflow             *Workflow             `json:"workflow_run"`
+	Organization         *Organization         `json:"organization"`
+	Installation         *Installation         `json:"installation"`
+
+	// Raw is the payload as received from Github.
+	Raw json.RawMessage `json:"-"`
+	// DeliveryID is the X-GitHub-Delivery header of the webhook request.
+	DeliveryID string `json:"-"`
+}
+
+type Organization struct {
+	Login string `json:"login"`
+}
+
+type Installation struct {
+	ID int64 `json:"id"`
+}
+
+// Name returns the event type in snake case, like pull_request for TypePullRequest.
+func (e EventType) Name() string {
+	var name strings.Builder
+	for i, r := range strings.TrimPrefix(e.String(), "Type") {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				name.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		name.WriteRune(r)
+	}
+
+	return name.String()
 }
 
 func (e Event) GetEventType() EventType {
@@ -378,6 +492,14 @@ func (e Event) GetEventType() EventType {
 		}
 	} else if e.SecurityAdvisory != nil {
 		return TypeSecurityAdvisory
+	} else if e.RepositoryAdvisory != nil {
+		return TypeRepositoryAdvisory
+	} else if e.BranchProtectionRule != nil {
+		return TypeBranchProtectionRule
+	} else if e.RepositoryRuleset != nil {
+		return TypeRepositoryRuleset
+	} else if e.Changes != nil && e.Changes.From.SecurityAndAnalysis != nil {
+		return TypeSecurityAndAnalysis
 	} else if e.Issue != nil {
 		return TypeIssue
 	} else if e.Review != nil {
@@ -413,11 +535,27 @@ func (e Event) GetRepositoryName() string {
 	}
 }
 
+// GetOrg returns the organization the event was sent from, as stored in the database.
+func (e Event) GetOrg() string {
+	if e.Organization != nil && e.Organization.Login != "" {
+		return OrgKey(e.Organization.Login)
+	}
+
+	if e.Repository != nil {
+		if owner, _, ok := strings.Cut(e.Repository.FullName, "/"); ok {
+			return OrgKey(owner)
+		}
+	}
+
+	return ""
+}
+
 func CreateEvent(body []byte) (Event, error) {
 	event := Event{}
 	if err := json.Unmarshal(body, &event); err != nil {
 		return Event{}, fmt.Errorf("decoding event: %w", err)
 	}
+	event.Raw = body
 
 	return event, nil
 }
@@ -0,0 +1,17 @@
+package github
+
+import "testing"
+
+func TestEventTypeName(t *testing.T) {
+	tests := map[EventType]string{
+		TypeCommit:              "commit",
+		TypePullRequest:         "pull_request",
+		TypeSecretScanningAlert: "secret_scanning_alert",
+	}
+
+	for eventType, want := range tests {
+		if got := eventType.Name(); got != want {
+			t.Errorf("%s.Name() = %q, want %q", eventType, got, want)
+		}
+	}
+}
@@ -21,12 +21,17 @@ func _() {
 	_ = x[TypeSecretScanningAlert-11]
 	_ = x[TypeTeam-12]
 	_ = x[TypeWorkflow-13]
-	_ = x[TypeUnknown-14]
+	_ = x[TypeRepositoryAdvisory-14]
+	_ = x[TypeSecurityAndAnalysis-15]
+	_ = x[TypeBranchProtectionRule-16]
+	_ = x[TypeRepositoryRuleset-17]
+	_ = x[TypeForcePush-18]
+	_ = x[TypeUnknown-19]
 }
 
-const _EventType_name = "TypeCommitTypeCodeScanningAlertTypeDependabotAlertTypeIssueTypePullRequestTypePullRequestReviewTypeReleaseTypeRepositoryRenamedTypeRepositoryPublicTypeSecurityAdvisoryTypeSecretScanningAlertTypeTeamTypeWorkflowTypeUnknown"
+const _EventType_name = "TypeCommitTypeCodeScanningAlertTypeDependabotAlertTypeIssueTypePullRequestTypePullRequestReviewTypeReleaseTypeRepositoryRenamedTypeRepositoryPublicTypeSecurityAdvisoryTypeSecretScanningAlertTypeTeamTypeWorkflowTypeRepositoryAdvisoryTypeSecurityAndAnalysisTypeBranchProtectionRuleTypeRepositoryRulesetTypeForcePushTypeUnknown"
 
-var _EventType_index = [...]uint8{0, 10, 31, 50, 59, 74, 95, 106, 127, 147, 167, 190, 198, 210, 221}
+var _EventType_index = [...]uint16{0, 10, 31, 50, 59, 74, 95, 106, 127, 147, 167, 190, 198, 210, 232, 255, 279, 300, 313, 324}
 
 func (i EventType) String() string {
 	idx := int(i) - 1
@@ -1,23 +1,61 @@
 package github
 
 import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
 	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
 type Client struct {
 	db                *gensql.Queries
+	httpClient        *http.Client
 	appInstallationID string
 	appID             string
 	appPrivateKey     string
 	org               string
+	urls              URLs
+	// namespaced is set for every organization except GITHUB_ORG, and
+	// prefixes its teams and repositories with the organization in the database.
+	namespaced bool
+	// alertStore serializes refreshing the organization's stored security
+	// alerts, so digests running at the same time only fetch them once.
+	alertStore *sync.Mutex
 }
 
-func New(db *gensql.Queries, appInstallationID, appID, appPrivateKey, githubOrg string) Client {
-	return Client{
+func New(log *slog.Logger, db *gensql.Queries, urls URLs, appInstallationID, appID, appPrivateKey, githubOrg string) Client {
+	client := Client{
 		db:                db,
 		appInstallationID: appInstallationID,
 		appID:             appID,
 		appPrivateKey:     appPrivateKey,
 		org:               githubOrg,
+		urls:              urls,
+		alertStore:        &sync.Mutex{},
 	}
+
+	client.httpClient = newHTTPClient(log, &installationToken{fetch: client.createInstallationToken})
+
+	return client
+}
+
+func (c Client) Org() string {
+	return c.org
+}
+
+// TeamURL returns the web URL of a team in the organization.
+func (c Client) TeamURL(slug string) string {
+	return fmt.Sprintf("%s/orgs/%s/teams/%s", c.urls.Web, c.org, slug)
+}
+
+// TeamName returns the name a team in the organization is configured with in
+// teams.yaml, where teams outside GITHUB_ORG are written as org/slug.
+func (c Client) TeamName(slug string) string {
+	if !c.namespaced {
+		return slug
+	}
+
+	return c.org + "/" + slug
 }
@@ -6,13 +6,12 @@ import (
 	"io"
 	"net/http"
 	"strconv"
-	"time"
 )
 
 // fetchMembers fetches members for a Github org or team using the following API:
 // Team: https://docs.github.com/en/rest/teams/members#list-team-members
 // Org: https://docs.github.com/en/rest/orgs/members#list-organization-members
-func fetchMembers(teamURL, bearerToken string) ([]*User, error) {
+func fetchMembers(httpClient *http.Client, teamURL string) ([]*User, error) {
 	req, err := http.NewRequest("GET", teamURL+"/members", nil)
 	if err != nil {
 		return nil, err
@@ -21,13 +20,8 @@ func fetchMembers(teamURL, bearerToken string) ([]*User, error) {
 	query := req.URL.Query()
 	query.Set("per_page", "100")
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %v", bearerToken))
 	req.Header.Add("Content-Type", "application/json")
 
-	httpClient := http.Client{
-		Timeout: 10 * time.Second,
-	}
-
 	var teamMembers []*User
 	page := 1
 	for {
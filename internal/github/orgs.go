@@ -0,0 +1,172 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// Clients holds a Client for each organization ghep is installed in, where
+// the primary organization is GITHUB_ORG.
+type Clients struct {
+	primary        Client
+	byOrg          map[string]Client
+	byInstallation map[int64]string
+}
+
+// NewClients creates a client for the primary organization, and one for each
+// additional organization, given as comma separated org:installationID pairs.
+func NewClients(log *slog.Logger, db *gensql.Queries, urls URLs, appID, appPrivateKey, org, installationID, additionalOrgs string) (Clients, error) {
+	primary := New(log.With("org", org), db, urls, installationID, appID, appPrivateKey, org)
+	clients := Clients{
+		primary:        primary,
+		byOrg:          map[string]Client{strings.ToLower(org): primary},
+		byInstallation: map[int64]string{},
+	}
+
+	if id, err := strconv.ParseInt(installationID, 10, 64); err == nil {
+		clients.byInstallation[id] = strings.ToLower(org)
+	}
+
+	for pair := range strings.SplitSeq(additionalOrgs, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		additionalOrg, installation, ok := strings.Cut(pair, ":")
+		id, err := strconv.ParseInt(installation, 10, 64)
+		if !ok || additionalOrg == "" || err != nil {
+			return Clients{}, fmt.Errorf("invalid organization %q, expected org:installationID", pair)
+		}
+
+		key := strings.ToLower(additionalOrg)
+		if _, exists := clients.byOrg[key]; exists {
+			return Clients{}, fmt.Errorf("organization %s is configured more than once", additionalOrg)
+		}
+
+		client := New(log.With("org", additionalOrg), db, urls, installation, appID, appPrivateKey, additionalOrg)
+		client.namespaced = true
+
+		clients.byOrg[key] = client
+		clients.byInstallation[id] = key
+	}
+
+	return clients, nil
+}
+
+// OrgKey returns the organization as stored with teams and repositories in
+// the database, as organization names on Github are case insensitive.
+func OrgKey(org string) string {
+	return strings.ToLower(org)
+}
+
+func (c Clients) Primary() Client {
+	return c.primary
+}
+
+// All returns the client for every organization, starting with the primary.
+func (c Clients) All() []Client {
+	all := []Client{c.primary}
+	for _, client := range c.byOrg {
+		if client.namespaced {
+			all = append(all, client)
+		}
+	}
+
+	return all
+}
+
+// ForOrg returns the client for an organization, where an empty org is the primary organization.
+func (c Clients) ForOrg(org string) (Client, bool) {
+	if org == "" {
+		return c.primary, true
+	}
+
+	client, ok := c.byOrg[strings.ToLower(org)]
+	return client, ok
+}
+
+// ForTeam returns the client for the organization the team belongs to.
+func (c Clients) ForTeam(team Team) (Client, bool) {
+	return c.ForOrg(team.Org)
+}
+
+// ForEvent returns the client for the organization a webhook event was sent
+// from, using the installation before the organization. Events without
+// either belong to the primary organization.
+func (c Clients) ForEvent(event Event) (Client, bool) {
+	if event.Installation != nil {
+		if org, ok := c.byInstallation[event.Installation.ID]; ok {
+			return c.byOrg[org], true
+		}
+	}
+
+	if event.Organization != nil && event.Organization.Login != "" {
+		return c.ForOrg(event.Organization.Login)
+	}
+
+	if event.Installation != nil {
+		return Client{}, false
+	}
+
+	return c.primary, true
+}
+
+// TeamURL returns the web URL of a configured team.
+func (c Clients) TeamURL(team Team) string {
+	client, ok := c.ForTeam(team)
+	if !ok {
+		client = c.primary
+	}
+
+	return client.TeamURL(team.Slug())
+}
+
+// ValidateTeams checks that every team configured as org/slug belongs to a configured organization.
+func (c Clients) ValidateTeams(teamConfig map[string]Team) error {
+	for name, team := range teamConfig {
+		if team.Org == "" {
+			continue
+		}
+
+		client, ok := c.ForOrg(team.Org)
+		if !ok {
+			return fmt.Errorf("team %s: organization %s is not configured in GITHUB_ADDITIONAL_ORGS", name, team.Org)
+		}
+
+		if !client.namespaced {
+			return fmt.Errorf("team %s: teams in %s should be configured without the organization", name, team.Org)
+		}
+
+		if team.Org != client.org {
+			return fmt.Errorf("team %s: organization must be written as %s", name, client.org)
+		}
+	}
+
+	return nil
+}
+
+// FindSecretLocation finds where a secret was committed, using the client for the organization.
+func (c Clients) FindSecretLocation(ctx context.Context, org, repository string, alertNumber int) (SecretLocation, bool, error) {
+	client, ok := c.ForOrg(org)
+	if !ok {
+		return SecretLocation{}, false, fmt.Errorf("organization %s is not configured", org)
+	}
+
+	return client.FindSecretLocation(ctx, repository, alertNumber)
+}
+
+// UpdateFailedJob finds the failed job in a workflow, using the client for the organization.
+func (c Clients) UpdateFailedJob(ctx context.Context, org string, w *Workflow) error {
+	client, ok := c.ForOrg(org)
+	if !ok {
+		return fmt.Errorf("organization %s is not configured", org)
+	}
+
+	return client.UpdateFailedJob(ctx, w)
+}
@@ -0,0 +1,122 @@
+package github
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestClientsForEvent(t *testing.T) {
+	clients, err := NewClients(slog.Default(), nil, NewURLs("", "", ""), "1", "", "navikt", "100", "other-org:200, Third-Org:300")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+		ok    bool
+	}{
+		{
+			name: "no installation or organization is the primary organization",
+			want: "navikt",
+			ok:   true,
+		},
+		{
+			name:  "installation",
+			event: Event{Installation: &Installation{ID: 200}},
+			want:  "other-org",
+			ok:    true,
+		},
+		{
+			name:  "organization is case insensitive",
+			event: Event{Organization: &Organization{Login: "third-org"}},
+			want:  "Third-Org",
+			ok:    true,
+		},
+		{
+			name:  "installation takes precedence",
+			event: Event{Installation: &Installation{ID: 100}, Organization: &Organization{Login: "other-org"}},
+			want:  "navikt",
+			ok:    true,
+		},
+		{
+			name:  "unknown organization",
+			event: Event{Organization: &Organization{Login: "unknown"}},
+		},
+		{
+			name:  "unknown installation",
+			event: Event{Installation: &Installation{ID: 400}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, ok := clients.ForEvent(tt.event)
+			if ok != tt.ok {
+				t.Fatalf("ForEvent() ok = %v, want %v", ok, tt.ok)
+			}
+
+			if client.Org() != tt.want {
+				t.Errorf("ForEvent() org = %q, want %q", client.Org(), tt.want)
+			}
+		})
+	}
+}
+
+func TestClientsKeys(t *testing.T) {
+	clients, err := NewClients(slog.Default(), nil, NewURLs("", "", ""), "1", "", "navikt", "100", "other-org:200")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	primary := clients.Primary()
+	other, _ := clients.ForOrg("other-org")
+
+	if got := primary.TeamName("nada"); got != "nada" {
+		t.Errorf("primary TeamName() = %q, want %q", got, "nada")
+	}
+	if got := other.TeamName("nada"); got != "other-org/nada" {
+		t.Errorf("other TeamName() = %q, want %q", got, "other-org/nada")
+	}
+
+	if got, want := clients.TeamURL(Team{Name: "other-org/nada", Org: "other-org"}), "https://github.com/orgs/other-org/teams/nada"; got != want {
+		t.Errorf("TeamURL() = %q, want %q", got, want)
+	}
+}
+
+func TestNewClientsInvalid(t *testing.T) {
+	for _, additionalOrgs := range []string{"other-org", "other-org:abc", ":200", "navikt:200"} {
+		if _, err := NewClients(slog.Default(), nil, NewURLs("", "", ""), "1", "", "navikt", "100", additionalOrgs); err == nil {
+			t.Errorf("expected error for %q", additionalOrgs)
+		}
+	}
+}
+
+func TestClientsValidateTeams(t *testing.T) {
+	clients, err := NewClients(slog.Default(), nil, NewURLs("", "", ""), "1", "", "navikt", "100", "other-org:200")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		team    Team
+		wantErr bool
+	}{
+		{name: "primary organization", team: Team{Name: "nada"}},
+		{name: "additional organization", team: Team{Name: "other-org/nada", Org: "other-org"}},
+		{name: "unknown organization", team: Team{Name: "unknown/nada", Org: "unknown"}, wantErr: true},
+		{name: "primary organization with prefix", team: Team{Name: "navikt/nada", Org: "navikt"}, wantErr: true},
+		{name: "different case", team: Team{Name: "Other-Org/nada", Org: "Other-Org"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := clients.ValidateTeams(map[string]Team{tt.team.Name: tt.team})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTeams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
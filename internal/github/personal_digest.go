@@ -19,6 +19,10 @@ type PersonalDigestUserEntry struct {
 	Day      string `yaml:"day"`
 	Time     string `yaml:"time"`
 	Timezone string `yaml:"timezone"`
+	// SlackWorkspace is the named Slack workspace to send the digest in, where empty is the default workspace.
+	SlackWorkspace string `yaml:"slackWorkspace"`
+	// Email is a list of addresses that also receive the digest by email.
+	Email []string `yaml:"email"`
 }
 
 // applyPersonalDigestDefaults fills in missing fields with defaults and validates the entry.
@@ -45,5 +49,8 @@ func applyPersonalDigestDefaults(e *PersonalDigestUserEntry) error {
 	if _, err := time.LoadLocation(e.Timezone); err != nil {
 		return fmt.Errorf("timezone %q is not a valid IANA timezone", e.Timezone)
 	}
+	if err := validateEmails(e.Email); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,110 @@
+package github
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	NotificationReviewRequested = "review_requested"
+	NotificationReviewed        = "reviewed"
+	NotificationWorkflowFailed  = "workflow_failed"
+	NotificationMentioned       = "mentioned"
+
+	personalNotificationsDefaultTimezone = "Europe/Oslo"
+)
+
+// PersonalNotificationTypes are the events a user can get a DM about.
+var PersonalNotificationTypes = []string{NotificationReviewRequested, NotificationReviewed, NotificationWorkflowFailed, NotificationMentioned}
+
+// PersonalNotifications holds a user's preferences for real-time DMs about
+// their own pull requests, commits and mentions.
+type PersonalNotifications struct {
+	Login string `yaml:"login"`
+	// SlackWorkspace is the named Slack workspace to send DMs in, where empty is the default workspace.
+	SlackWorkspace string `yaml:"slackWorkspace"`
+	// Types are the notifications to send, where empty is all of them.
+	Types      []string    `yaml:"types"`
+	QuietHours *QuietHours `yaml:"quietHours"`
+}
+
+// QuietHours is a daily period without DMs, which may span midnight.
+type QuietHours struct {
+	From     string `yaml:"from"`
+	To       string `yaml:"to"`
+	Timezone string `yaml:"timezone"`
+}
+
+// Wants returns true if the user wants DMs of the notification type.
+func (p PersonalNotifications) Wants(notificationType string) bool {
+	return len(p.Types) == 0 || slices.Contains(p.Types, notificationType)
+}
+
+// IsQuiet returns true if the time is within the user's quiet hours.
+func (p PersonalNotifications) IsQuiet(now time.Time) bool {
+	if p.QuietHours == nil {
+		return false
+	}
+
+	return p.QuietHours.Contains(now)
+}
+
+// Contains returns true if the time is within the quiet hours. Invalid quiet
+// hours never contain any time, and are caught when the config is parsed.
+func (q QuietHours) Contains(now time.Time) bool {
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return false
+	}
+
+	from, errFrom := time.Parse("15:04", q.From)
+	to, errTo := time.Parse("15:04", q.To)
+	if errFrom != nil || errTo != nil {
+		return false
+	}
+
+	local := now.In(loc)
+	minutes := local.Hour()*60 + local.Minute()
+	start := from.Hour()*60 + from.Minute()
+	end := to.Hour()*60 + to.Minute()
+
+	if start <= end {
+		return minutes >= start && minutes < end
+	}
+
+	return minutes >= start || minutes < end
+}
+
+// ApplyPersonalNotificationsDefaults fills in missing fields with defaults and validates the preferences.
+func ApplyPersonalNotificationsDefaults(p *PersonalNotifications) error {
+	if p.Login == "" {
+		return fmt.Errorf("login is required")
+	}
+
+	for _, notificationType := range p.Types {
+		if !slices.Contains(PersonalNotificationTypes, notificationType) {
+			return fmt.Errorf("type %q must be one of %s", notificationType, strings.Join(PersonalNotificationTypes, ", "))
+		}
+	}
+
+	if p.QuietHours == nil {
+		return nil
+	}
+
+	if p.QuietHours.Timezone == "" {
+		p.QuietHours.Timezone = personalNotificationsDefaultTimezone
+	}
+	if _, err := time.Parse("15:04", p.QuietHours.From); err != nil {
+		return fmt.Errorf("quietHours: from %q must be in HH:MM format", p.QuietHours.From)
+	}
+	if _, err := time.Parse("15:04", p.QuietHours.To); err != nil {
+		return fmt.Errorf("quietHours: to %q must be in HH:MM format", p.QuietHours.To)
+	}
+	if _, err := time.LoadLocation(p.QuietHours.Timezone); err != nil {
+		return fmt.Errorf("quietHours: timezone %q is not a valid IANA timezone", p.QuietHours.Timezone)
+	}
+
+	return nil
+}
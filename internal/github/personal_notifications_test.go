@@ -0,0 +1,98 @@
+package github
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		quiet    QuietHours
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "within same day",
+			quiet:    QuietHours{From: "12:00", To: "13:00", Timezone: "UTC"},
+			now:      time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:  "at end of same day",
+			quiet: QuietHours{From: "12:00", To: "13:00", Timezone: "UTC"},
+			now:   time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "after midnight",
+			quiet:    QuietHours{From: "18:00", To: "08:00", Timezone: "UTC"},
+			now:      time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:  "during the day when spanning midnight",
+			quiet: QuietHours{From: "18:00", To: "08:00", Timezone: "UTC"},
+			now:   time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "in timezone",
+			quiet:    QuietHours{From: "18:00", To: "08:00", Timezone: "Europe/Oslo"},
+			now:      time.Date(2026, 7, 1, 16, 30, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:  "invalid",
+			quiet: QuietHours{From: "6pm", To: "08:00", Timezone: "UTC"},
+			now:   time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.Contains(tt.now); got != tt.expected {
+				t.Errorf("Contains() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyPersonalNotificationsDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  PersonalNotifications
+		wantErr bool
+	}{
+		{
+			name:   "defaults timezone",
+			config: PersonalNotifications{Login: "Kyrremann", QuietHours: &QuietHours{From: "18:00", To: "08:00"}},
+		},
+		{
+			name:    "missing login",
+			config:  PersonalNotifications{},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			config:  PersonalNotifications{Login: "Kyrremann", Types: []string{"commits"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timezone",
+			config:  PersonalNotifications{Login: "Kyrremann", QuietHours: &QuietHours{From: "18:00", To: "08:00", Timezone: "Norway"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ApplyPersonalNotificationsDefaults(&tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyPersonalNotificationsDefaults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.config.QuietHours != nil && !tt.wantErr && tt.config.QuietHours.Timezone != personalNotificationsDefaultTimezone {
+				t.Errorf("expected default timezone, got %q", tt.config.QuietHours.Timezone)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrivateContentPolicy is what is posted from private and internal repositories
+// to public channels, which anyone in the workspace can read.
+type PrivateContentPolicy string
+
+const (
+	// PrivateContentFull posts events as usual, and is used when no policy is set.
+	PrivateContentFull PrivateContentPolicy = "full"
+	// PrivateContentTitle posts titles, but not bodies of pull requests, issues,
+	// releases and comments, nor the bodies of commit messages.
+	PrivateContentTitle PrivateContentPolicy = "title"
+	// PrivateContentBlock posts nothing.
+	PrivateContentBlock PrivateContentPolicy = "block"
+)
+
+func validatePrivateContentPolicy(teamName string, policy PrivateContentPolicy) error {
+	switch policy {
+	case "", PrivateContentFull, PrivateContentTitle, PrivateContentBlock:
+		return nil
+	}
+
+	return fmt.Errorf("team %s: invalid privateContent %q, must be one of full, title or block", teamName, policy)
+}
+
+// HasPrivateContent returns true if the event is from a private or internal repository.
+func (e Event) HasPrivateContent() bool {
+	return e.Repository != nil && !e.Repository.IsPublic()
+}
+
+// WithTitlesOnly returns a copy of the event without the bodies of pull requests,
+// issues, releases and comments, and with only the first line of commit messages.
+func (e Event) WithTitlesOnly() Event {
+	if e.PullRequest != nil {
+		pullRequest := *e.PullRequest
+		pullRequest.Body = ""
+		e.PullRequest = &pullRequest
+	}
+
+	if e.Issue != nil {
+		issue := *e.Issue
+		issue.Body = ""
+		e.Issue = &issue
+	}
+
+	if e.Release != nil {
+		release := *e.Release
+		release.Body = ""
+		e.Release = &release
+	}
+
+	if e.Comment != nil {
+		comment := *e.Comment
+		comment.Body = ""
+		e.Comment = &comment
+	}
+
+	if len(e.Commits) > 0 {
+		commits := make([]Commit, len(e.Commits))
+		for i, commit := range e.Commits {
+			commit.Message, _, _ = strings.Cut(commit.Message, "\n")
+			commits[i] = commit
+		}
+		e.Commits = commits
+	}
+
+	return e
+}
+
+// Describe returns what happens to events from private and internal
+// repositories posted to a public channel.
+func (p PrivateContentPolicy) Describe() string {
+	switch p {
+	case PrivateContentTitle:
+		return "are posted with titles only"
+	case PrivateContentBlock:
+		return "are not posted"
+	default:
+		return "are posted in full"
+	}
+}
@@ -0,0 +1,45 @@
+package github
+
+import "testing"
+
+func TestRepositoryIsPublic(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository Repository
+		want       bool
+	}{
+		{name: "public", repository: Repository{Visibility: "public"}, want: true},
+		{name: "private", repository: Repository{Private: true, Visibility: "private"}},
+		{name: "internal", repository: Repository{Visibility: "internal"}},
+		{name: "public without visibility", repository: Repository{}, want: true},
+		{name: "private without visibility", repository: Repository{Private: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.repository.IsPublic(); got != tt.want {
+				t.Errorf("IsPublic() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTitlesOnly(t *testing.T) {
+	event := Event{
+		PullRequest: &Issue{Title: "Add feature", Body: "Connects to the internal API"},
+		Commits:     []Commit{{Message: "Add feature\n\nUses the internal API"}},
+	}
+
+	got := event.WithTitlesOnly()
+	if got.PullRequest.Title != "Add feature" || got.PullRequest.Body != "" {
+		t.Errorf("expected only the title of the pull request, got %+v", got.PullRequest)
+	}
+
+	if got.Commits[0].Message != "Add feature" {
+		t.Errorf("expected only the first line of the commit message, got %q", got.Commits[0].Message)
+	}
+
+	if event.PullRequest.Body == "" || event.Commits[0].Message != "Add feature\n\nUses the internal API" {
+		t.Error("expected the original event to be unchanged")
+	}
+}
@@ -11,8 +11,6 @@ import (
 	"time"
 )
 
-const graphqlURL = "https://api.github.com/graphql"
-
 type PullRequest struct {
 	Number    int       `json:"number"`
 	Title     string    `json:"title"`
@@ -49,29 +47,24 @@ type graphqlResponse struct {
 }
 
 func (c Client) FetchOpenPullRequests(ctx context.Context, teamSlug string) ([]RepoPRs, error) {
-	bearerToken, err := c.createBearerToken()
-	if err != nil {
-		return nil, fmt.Errorf("creating bearer token: %v", err)
-	}
-
 	repos, err := c.db.ListTeamRepositories(ctx, teamSlug)
 	if err != nil {
 		return nil, fmt.Errorf("listing repositories for team %s: %v", teamSlug, err)
 	}
 
-	if len(repos) == 0 {
-		return nil, nil
+	repoNames := make([]string, 0, len(repos))
+	for _, r := range repos {
+		if r.Org == OrgKey(c.org) {
+			repoNames = append(repoNames, r.Name)
+		}
 	}
 
-	httpClient := http.Client{Timeout: 30 * time.Second}
-
-	repoNames := make([]string, len(repos))
-	for i, r := range repos {
-		repoNames[i] = r.Name
+	if len(repoNames) == 0 {
+		return nil, nil
 	}
 
 	// Fetch first page for all repos in one query
-	connections, err := fetchPRsBatch(ctx, httpClient, bearerToken, c.org, repoNames, nil)
+	connections, err := fetchPRsBatch(ctx, c.httpClient, c.urls.GraphQL, c.org, repoNames, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +81,7 @@ func (c Client) FetchOpenPullRequests(ctx context.Context, teamSlug string) ([]R
 		// Paginate repos that have more than 100 open PRs
 		for conn.PageInfo.HasNextPage {
 			cursor := conn.PageInfo.EndCursor
-			more, err := fetchPRsBatch(ctx, httpClient, bearerToken, c.org, []string{repoName}, map[string]string{repoName: cursor})
+			more, err := fetchPRsBatch(ctx, c.httpClient, c.urls.GraphQL, c.org, []string{repoName}, map[string]string{repoName: cursor})
 			if err != nil {
 				return nil, err
 			}
@@ -110,7 +103,7 @@ func (c Client) FetchOpenPullRequests(ctx context.Context, teamSlug string) ([]R
 
 // fetchPRsBatch sends a single GraphQL query fetching open PRs for all given repos.
 // cursors maps repoName -> after-cursor for pagination (nil or missing = first page).
-func fetchPRsBatch(ctx context.Context, httpClient http.Client, bearerToken, org string, repoNames []string, cursors map[string]string) (map[string]graphqlPRConnection, error) {
+func fetchPRsBatch(ctx context.Context, httpClient *http.Client, graphqlURL, org string, repoNames []string, cursors map[string]string) (map[string]graphqlPRConnection, error) {
 	query := buildBatchQuery(org, repoNames, cursors)
 
 	payload, err := json.Marshal(map[string]string{"query": query})
@@ -122,7 +115,6 @@ func fetchPRsBatch(ctx context.Context, httpClient http.Client, bearerToken, org
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add("Authorization", "Bearer "+bearerToken)
 	req.Header.Add("Content-Type", "application/json")
 
 	resp, err := httpClient.Do(req)
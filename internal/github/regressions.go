@@ -0,0 +1,122 @@
+package github
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// securityFeatures are the features in security_and_analysis Ghep warns about
+// when disabled, with how severe it is.
+var securityFeatures = map[string]SeverityType{
+	"secret_scanning":                 SeverityCritical,
+	"secret_scanning_push_protection": SeverityCritical,
+	"advanced_security":               SeverityHigh,
+	"dependabot_security_updates":     SeverityMedium,
+}
+
+// SecurityAndAnalysis is the status of a repository's security features, keyed
+// by feature like secret_scanning.
+type SecurityAndAnalysis map[string]struct {
+	Status string `json:"status"`
+}
+
+// BranchProtectionRule is a classic branch protection rule, where Name is the branch pattern it protects.
+type BranchProtectionRule struct {
+	Name string `json:"name"`
+}
+
+// Protects returns true if the rule's pattern matches the branch.
+func (r BranchProtectionRule) Protects(branch string) bool {
+	matched, err := path.Match(r.Name, branch)
+	return err == nil && matched
+}
+
+type RepositoryRuleset struct {
+	Name        string `json:"name"`
+	Target      string `json:"target"`
+	Enforcement string `json:"enforcement"`
+	Conditions  struct {
+		RefName struct {
+			Include []string `json:"include"`
+			Exclude []string `json:"exclude"`
+		} `json:"ref_name"`
+	} `json:"conditions"`
+}
+
+// Protects returns true if the ruleset applies to the branch, which is the
+// repository's default branch.
+func (r RepositoryRuleset) Protects(defaultBranch string) bool {
+	if r.Target != "branch" {
+		return false
+	}
+
+	matches := func(patterns []string) bool {
+		return slices.ContainsFunc(patterns, func(pattern string) bool {
+			if pattern == "~DEFAULT_BRANCH" || pattern == "~ALL" {
+				return true
+			}
+
+			matched, err := path.Match(strings.TrimPrefix(pattern, RefHeadsPrefix), defaultBranch)
+			return err == nil && matched
+		})
+	}
+
+	return matches(r.Conditions.RefName.Include) && !matches(r.Conditions.RefName.Exclude)
+}
+
+// DisabledSecurityFeatures returns the security features a security_and_analysis
+// event turned off, sorted from the most severe, and the severity of the most severe.
+func (e Event) DisabledSecurityFeatures() ([]string, SeverityType) {
+	if e.Changes == nil || e.Changes.From.SecurityAndAnalysis == nil || e.Repository == nil {
+		return nil, SeverityLow
+	}
+
+	var disabled []string
+	for feature, before := range e.Changes.From.SecurityAndAnalysis {
+		if _, ok := securityFeatures[feature]; !ok || before.Status != "enabled" {
+			continue
+		}
+
+		if e.Repository.SecurityAndAnalysis[feature].Status == "disabled" {
+			disabled = append(disabled, feature)
+		}
+	}
+
+	slices.SortFunc(disabled, func(a, b string) int {
+		if securityFeatures[a] != securityFeatures[b] {
+			return int(securityFeatures[b]) - int(securityFeatures[a])
+		}
+		return strings.Compare(a, b)
+	})
+
+	if len(disabled) == 0 {
+		return nil, SeverityLow
+	}
+
+	return disabled, securityFeatures[disabled[0]]
+}
+
+// IsForcePushToDefaultBranch returns true for force pushes to the repository's default branch.
+func (e Event) IsForcePushToDefaultBranch() bool {
+	return e.IsCommit() && e.Forced && e.Repository != nil && e.Repository.DefaultBranch != "" &&
+		strings.TrimPrefix(e.Ref, RefHeadsPrefix) == e.Repository.DefaultBranch
+}
+
+// RegressionSeverity returns how severe a security or branch protection regression is,
+// and false if the event does not turn off any protection of the repository.
+func (e Event) RegressionSeverity(eventType EventType) (SeverityType, bool) {
+	switch eventType {
+	case TypeSecurityAndAnalysis:
+		disabled, severity := e.DisabledSecurityFeatures()
+		return severity, len(disabled) > 0
+	case TypeBranchProtectionRule:
+		return SeverityHigh, e.Action == "deleted" && e.Repository != nil && e.BranchProtectionRule.Protects(e.Repository.DefaultBranch)
+	case TypeRepositoryRuleset:
+		return SeverityHigh, e.Action == "deleted" && e.Repository != nil && e.RepositoryRuleset.Protects(e.Repository.DefaultBranch)
+	case TypeForcePush:
+		return SeverityHigh, e.IsForcePushToDefaultBranch()
+	}
+
+	return SeverityLow, false
+}
@@ -0,0 +1,70 @@
+package github
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRepositoryRulesetProtects(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		include []string
+		exclude []string
+		want    bool
+	}{
+		{name: "default branch", target: "branch", include: []string{"~DEFAULT_BRANCH"}, want: true},
+		{name: "all branches", target: "branch", include: []string{"~ALL"}, want: true},
+		{name: "branch by ref", target: "branch", include: []string{"refs/heads/main"}, want: true},
+		{name: "matching pattern", target: "branch", include: []string{"refs/heads/ma*"}, want: true},
+		{name: "other branch", target: "branch", include: []string{"refs/heads/release/*"}},
+		{name: "default branch excluded", target: "branch", include: []string{"~ALL"}, exclude: []string{"~DEFAULT_BRANCH"}},
+		{name: "tag ruleset", target: "tag", include: []string{"~ALL"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ruleset := RepositoryRuleset{Target: tt.target}
+			ruleset.Conditions.RefName.Include = tt.include
+			ruleset.Conditions.RefName.Exclude = tt.exclude
+
+			if got := ruleset.Protects("main"); got != tt.want {
+				t.Errorf("Protects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisabledSecurityFeatures(t *testing.T) {
+	event := Event{
+		Repository: &Repository{SecurityAndAnalysis: SecurityAndAnalysis{
+			"advanced_security":               {Status: "disabled"},
+			"dependabot_security_updates":     {Status: "disabled"},
+			"secret_scanning":                 {Status: "enabled"},
+			"secret_scanning_push_protection": {Status: "disabled"},
+		}},
+		Changes: &Changes{},
+	}
+	event.Changes.From.SecurityAndAnalysis = SecurityAndAnalysis{
+		"advanced_security":               {Status: "enabled"},
+		"dependabot_security_updates":     {Status: "enabled"},
+		"secret_scanning_push_protection": {Status: "enabled"},
+	}
+
+	disabled, severity := event.DisabledSecurityFeatures()
+	want := []string{"secret_scanning_push_protection", "advanced_security", "dependabot_security_updates"}
+	if !slices.Equal(disabled, want) {
+		t.Errorf("DisabledSecurityFeatures() = %v, want %v", disabled, want)
+	}
+	if severity != SeverityCritical {
+		t.Errorf("expected critical severity, got %s", severity.Name())
+	}
+
+	event.Repository.SecurityAndAnalysis["secret_scanning_push_protection"] = struct {
+		Status string `json:"status"`
+	}{Status: "enabled"}
+	event.Changes.From.SecurityAndAnalysis = SecurityAndAnalysis{"secret_scanning_push_protection": {Status: "disabled"}}
+	if disabled, _ := event.DisabledSecurityFeatures(); len(disabled) != 0 {
+		t.Errorf("expected enabling a feature not to be a regression, got %v", disabled)
+	}
+}
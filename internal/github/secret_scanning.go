@@ -0,0 +1,105 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SecretLocation is where in the repository a secret scanning alert found the
+// secret, and who committed it.
+type SecretLocation struct {
+	Path      string
+	StartLine int
+	CommitSHA string
+	// URL links to the line with the secret in the commit.
+	URL         string
+	AuthorLogin string
+	AuthorEmail string
+}
+
+// FindSecretLocation returns the first commit the secret scanning alert found
+// the secret in. It returns false if the secret was not found in a commit,
+// like in an issue or a pull request comment.
+func (c Client) FindSecretLocation(ctx context.Context, repository string, alertNumber int) (SecretLocation, bool, error) {
+	type location struct {
+		Type    string `json:"type"`
+		Details struct {
+			Path      string `json:"path"`
+			StartLine int    `json:"start_line"`
+			CommitSHA string `json:"commit_sha"`
+		} `json:"details"`
+	}
+
+	locations, err := fetchAllPages[location](ctx, c.httpClient,
+		fmt.Sprintf("%s/repos/%s/%s/secret-scanning/alerts/%d/locations?per_page=100", c.urls.API, c.org, repository, alertNumber))
+	if err != nil {
+		return SecretLocation{}, false, fmt.Errorf("getting secret locations: %w", err)
+	}
+
+	for _, l := range locations {
+		if l.Type != "commit" || l.Details.CommitSHA == "" {
+			continue
+		}
+
+		login, email, err := c.commitAuthor(ctx, repository, l.Details.CommitSHA)
+		if err != nil {
+			return SecretLocation{}, false, err
+		}
+
+		return SecretLocation{
+			Path:        l.Details.Path,
+			StartLine:   l.Details.StartLine,
+			CommitSHA:   l.Details.CommitSHA,
+			URL:         fmt.Sprintf("%s/%s/%s/blob/%s/%s#L%d", c.urls.Web, c.org, repository, l.Details.CommitSHA, l.Details.Path, l.Details.StartLine),
+			AuthorLogin: login,
+			AuthorEmail: email,
+		}, true, nil
+	}
+
+	return SecretLocation{}, false, nil
+}
+
+// commitAuthor returns the Github login and the email of a commit's author.
+// The login is empty when the email is not connected to a Github user.
+func (c Client) commitAuthor(ctx context.Context, repository, sha string) (string, string, error) {
+	type commit struct {
+		Author *struct {
+			Login string `json:"login"`
+		} `json:"author"`
+		Commit struct {
+			Author struct {
+				Email string `json:"email"`
+			} `json:"author"`
+		} `json:"commit"`
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/repos/%s/%s/commits/%s", c.urls.API, c.org, repository, sha), nil)
+	if err != nil {
+		return "", "", fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("getting commit: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("getting commit: %s", resp.Status)
+	}
+
+	var body commit
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", "", fmt.Errorf("decoding commit: %w", err)
+	}
+
+	var login string
+	if body.Author != nil {
+		login = body.Author.Login
+	}
+
+	return login, body.Commit.Author.Email, nil
+}
@@ -0,0 +1,142 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// securityAlertStoreMaxAge is how long the organization's stored security alerts
+// are used before they are fetched from Github again. Webhook events keep them
+// up to date in between.
+const securityAlertStoreMaxAge = 24 * time.Hour
+
+// SecurityAlertType returns the type of security alert the event is for.
+func (e Event) SecurityAlertType() (SecurityAlertType, bool) {
+	switch e.GetEventType() {
+	case TypeSecretScanningAlert:
+		return AlertTypeSecretScanning, true
+	case TypeCodeScanningAlert:
+		return AlertTypeCodeScanning, true
+	case TypeDependabotAlert:
+		return AlertTypeDependabot, true
+	}
+
+	return 0, false
+}
+
+// StoredSecurityAlert returns the alert the event is for, as stored for the
+// organization's security digests.
+func (e Event) StoredSecurityAlert() (gensql.UpsertOrgSecurityAlertParams, bool) {
+	alertType, ok := e.SecurityAlertType()
+	if !ok || e.Organization == nil || e.Repository == nil {
+		return gensql.UpsertOrgSecurityAlertParams{}, false
+	}
+
+	var severity, description string
+	switch alertType {
+	case AlertTypeSecretScanning:
+		description = *e.Alert.SecretType
+	case AlertTypeCodeScanning:
+		severity = e.Alert.Rule.SecuritySeverityLevel
+		description = e.Alert.Rule.Description
+	case AlertTypeDependabot:
+		severity = e.Alert.SecurityAdvisory.Severity
+		description = e.Alert.SecurityAdvisory.Summary
+	}
+
+	return storedAlert(e.Organization.Login, alertType, *e.Repository, e.Alert.URL, severity, description, e.Alert.CreatedAt), true
+}
+
+// openOrgSecurityAlerts returns the organization's open security alerts from the
+// database, fetching them from Github first if they are stale. All teams share
+// the stored alerts, so the organization is fetched once, not once per team.
+func (c Client) openOrgSecurityAlerts(ctx context.Context, now time.Time) ([]gensql.OrgSecurityAlert, error) {
+	c.alertStore.Lock()
+	defer c.alertStore.Unlock()
+
+	syncedAt, err := c.db.GetOrgSecurityAlertSync(ctx, alertStoreOrg(c.org))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("getting last security alert sync: %v", err)
+	}
+
+	if err != nil || now.Sub(syncedAt.Time) > securityAlertStoreMaxAge {
+		if err := c.syncOrgSecurityAlerts(ctx, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.db.ListOrgSecurityAlerts(ctx, alertStoreOrg(c.org))
+}
+
+// syncOrgSecurityAlerts replaces the stored security alerts with the open alerts from Github.
+func (c Client) syncOrgSecurityAlerts(ctx context.Context, now time.Time) error {
+	secretAlerts, err := fetchOrgSecretScanningAlerts(ctx, c.httpClient, c.urls.API, c.org)
+	if err != nil {
+		return fmt.Errorf("fetching secret scanning alerts: %v", err)
+	}
+
+	codeScanningAlerts, err := fetchOrgCodeScanningAlerts(ctx, c.httpClient, c.urls.API, c.org)
+	if err != nil {
+		return fmt.Errorf("fetching code scanning alerts: %v", err)
+	}
+
+	dependabotAlerts, err := fetchOrgDependabotAlerts(ctx, c.httpClient, c.urls.API, c.org)
+	if err != nil {
+		return fmt.Errorf("fetching dependabot alerts: %v", err)
+	}
+
+	var alerts []gensql.UpsertOrgSecurityAlertParams
+	for _, a := range secretAlerts {
+		alerts = append(alerts, storedAlert(c.org, AlertTypeSecretScanning, a.repo, a.url, "", a.secretType, a.createdAt))
+	}
+	for _, a := range codeScanningAlerts {
+		alerts = append(alerts, storedAlert(c.org, AlertTypeCodeScanning, a.repo, a.url, a.severity, a.ruleDescription, a.createdAt))
+	}
+	for _, a := range dependabotAlerts {
+		alerts = append(alerts, storedAlert(c.org, AlertTypeDependabot, a.repo, a.url, a.severity, a.advisorySummary, a.createdAt))
+	}
+
+	// Replaced in a transaction, so digests never see the alerts half stored
+	return c.db.InTx(ctx, func(db *gensql.Queries) error {
+		if err := db.DeleteOrgSecurityAlerts(ctx, alertStoreOrg(c.org)); err != nil {
+			return fmt.Errorf("clearing stored security alerts: %v", err)
+		}
+
+		for _, alert := range alerts {
+			if err := db.UpsertOrgSecurityAlert(ctx, alert); err != nil {
+				return fmt.Errorf("storing security alert %s: %v", alert.AlertUrl, err)
+			}
+		}
+
+		return db.UpsertOrgSecurityAlertSync(ctx, gensql.UpsertOrgSecurityAlertSyncParams{
+			Org:      alertStoreOrg(c.org),
+			SyncedAt: pgtype.Timestamptz{Time: now, Valid: true},
+		})
+	})
+}
+
+// alertStoreOrg returns the organization alerts are stored with, the same for
+// webhook events and the configured organization regardless of case.
+func alertStoreOrg(org string) string {
+	return strings.ToLower(org)
+}
+
+func storedAlert(org string, alertType SecurityAlertType, repo Repository, url, severity, description string, createdAt time.Time) gensql.UpsertOrgSecurityAlertParams {
+	return gensql.UpsertOrgSecurityAlertParams{
+		Org:           alertStoreOrg(org),
+		AlertUrl:      url,
+		AlertType:     alertType.Name(),
+		Repository:    repo.Name,
+		RepositoryUrl: repo.URL,
+		Severity:      severity,
+		Description:   description,
+		CreatedAt:     pgtype.Timestamptz{Time: createdAt, Valid: true},
+	}
+}
@@ -13,6 +13,9 @@ import (
 // SecurityAlert represents a single open security alert from any of the three alert types.
 type SecurityAlert struct {
 	AlertType  SecurityAlertType
+	Repository string
+	URL        string
+	CreatedAt  time.Time
 	SecretType string
 	// RuleDescription is the rule description for code scanning alerts.
 	RuleDescription string
@@ -22,6 +25,23 @@ type SecurityAlert struct {
 	AdvisorySummary string
 }
 
+// IsCritical returns true for critical code scanning and Dependabot alerts.
+func (a SecurityAlert) IsCritical() bool {
+	return a.AlertType != AlertTypeSecretScanning && AsSeverityType(a.Severity) == SeverityCritical
+}
+
+// Description returns the secret type, rule description or advisory summary, depending on the alert type.
+func (a SecurityAlert) Description() string {
+	switch a.AlertType {
+	case AlertTypeSecretScanning:
+		return a.SecretType
+	case AlertTypeCodeScanning:
+		return a.RuleDescription
+	default:
+		return a.AdvisorySummary
+	}
+}
+
 type SecurityAlertType int
 
 const (
@@ -30,6 +50,18 @@ const (
 	AlertTypeDependabot
 )
 
+// Name returns the alert type in snake case, like code_scanning.
+func (t SecurityAlertType) Name() string {
+	switch t {
+	case AlertTypeSecretScanning:
+		return "secret_scanning"
+	case AlertTypeCodeScanning:
+		return "code_scanning"
+	default:
+		return "dependabot"
+	}
+}
+
 type RepoSecurityAlerts struct {
 	Repository     Repository
 	SecretScanning []SecurityAlert
@@ -49,12 +81,26 @@ func (r RepoSecurityAlerts) Total() int {
 	return len(r.SecretScanning) + len(r.CodeScanning) + len(r.Dependabot)
 }
 
-func (c Client) FetchOpenSecurityAlerts(ctx context.Context, teamSlug string, cfg *SecurityDigestConfig, globalIgnore []string) ([]RepoSecurityAlerts, error) {
-	bearerToken, err := c.createBearerToken()
-	if err != nil {
-		return nil, fmt.Errorf("creating bearer token: %v", err)
+// All returns the alerts of all types.
+func (r RepoSecurityAlerts) All() []SecurityAlert {
+	return slices.Concat(r.SecretScanning, r.CodeScanning, r.Dependabot)
+}
+
+// Oldest returns when the oldest open alert was created, or the zero time if unknown.
+func (r RepoSecurityAlerts) Oldest() time.Time {
+	var oldest time.Time
+	for _, a := range r.All() {
+		if !a.CreatedAt.IsZero() && (oldest.IsZero() || a.CreatedAt.Before(oldest)) {
+			oldest = a.CreatedAt
+		}
 	}
 
+	return oldest
+}
+
+// FetchOpenSecurityAlerts returns the open security alerts in the team's
+// repositories, from the alerts stored for the whole organization.
+func (c Client) FetchOpenSecurityAlerts(ctx context.Context, teamSlug string, cfg *SecurityDigestConfig, globalIgnore []string) ([]RepoSecurityAlerts, error) {
 	repos, err := c.db.ListTeamRepositories(ctx, teamSlug)
 	if err != nil {
 		return nil, fmt.Errorf("listing repositories for team %s: %v", teamSlug, err)
@@ -66,76 +112,61 @@ func (c Client) FetchOpenSecurityAlerts(ctx context.Context, teamSlug string, cf
 	// Build set of repos for this team, excluding ignored repos from both config levels.
 	repoSet := make(map[string]bool, len(repos))
 	for _, r := range repos {
-		if slices.Contains(globalIgnore, r.Name) {
+		if r.Org != OrgKey(c.org) {
 			continue
 		}
-		if slices.Contains(cfg.IgnoreRepositories, r.Name) {
+		name := r.Name
+		if slices.Contains(globalIgnore, name) {
 			continue
 		}
-		repoSet[r.Name] = true
-	}
-
-	httpClient := http.Client{Timeout: 30 * time.Second}
-
-	secretAlerts, err := fetchOrgSecretScanningAlerts(ctx, httpClient, bearerToken, c.org)
-	if err != nil {
-		return nil, fmt.Errorf("fetching secret scanning alerts: %v", err)
+		if slices.Contains(cfg.IgnoreRepositories, name) {
+			continue
+		}
+		repoSet[name] = true
 	}
 
-	codeScanningAlerts, err := fetchOrgCodeScanningAlerts(ctx, httpClient, bearerToken, c.org)
+	stored, err := c.openOrgSecurityAlerts(ctx, time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("fetching code scanning alerts: %v", err)
+		return nil, err
 	}
 
-	dependabotAlerts, err := fetchOrgDependabotAlerts(ctx, httpClient, bearerToken, c.org)
-	if err != nil {
-		return nil, fmt.Errorf("fetching dependabot alerts: %v", err)
-	}
+	threshold := cfg.SeverityType()
 
 	// Group by repo, filtering to team's repos.
 	byRepo := make(map[string]*RepoSecurityAlerts)
 
-	for _, a := range secretAlerts {
-		if !repoSet[a.repo.Name] {
+	for _, a := range stored {
+		if !repoSet[a.Repository] {
 			continue
 		}
-		entry := getOrCreate(byRepo, a.repo)
-		entry.SecretScanning = append(entry.SecretScanning, SecurityAlert{
-			AlertType:  AlertTypeSecretScanning,
-			SecretType: a.secretType,
-		})
-	}
-
-	threshold := cfg.SeverityType()
-
-	for _, a := range codeScanningAlerts {
-		if !repoSet[a.repo.Name] {
-			continue
-		}
-		if AsSeverityType(a.severity) < threshold {
+		// Secret scanning alerts have no severity, and are always included
+		if a.AlertType != AlertTypeSecretScanning.Name() && AsSeverityType(a.Severity) < threshold {
 			continue
 		}
-		entry := getOrCreate(byRepo, a.repo)
-		entry.CodeScanning = append(entry.CodeScanning, SecurityAlert{
-			AlertType:       AlertTypeCodeScanning,
-			RuleDescription: a.ruleDescription,
-			Severity:        a.severity,
-		})
-	}
 
-	for _, a := range dependabotAlerts {
-		if !repoSet[a.repo.Name] {
-			continue
+		alert := SecurityAlert{
+			Repository: a.Repository,
+			URL:        a.AlertUrl,
+			CreatedAt:  a.CreatedAt.Time,
 		}
-		if AsSeverityType(a.severity) < threshold {
-			continue
+
+		entry := getOrCreate(byRepo, Repository{Name: a.Repository, URL: a.RepositoryUrl})
+		switch a.AlertType {
+		case AlertTypeSecretScanning.Name():
+			alert.AlertType = AlertTypeSecretScanning
+			alert.SecretType = a.Description
+			entry.SecretScanning = append(entry.SecretScanning, alert)
+		case AlertTypeCodeScanning.Name():
+			alert.AlertType = AlertTypeCodeScanning
+			alert.RuleDescription = a.Description
+			alert.Severity = a.Severity
+			entry.CodeScanning = append(entry.CodeScanning, alert)
+		case AlertTypeDependabot.Name():
+			alert.AlertType = AlertTypeDependabot
+			alert.AdvisorySummary = a.Description
+			alert.Severity = a.Severity
+			entry.Dependabot = append(entry.Dependabot, alert)
 		}
-		entry := getOrCreate(byRepo, a.repo)
-		entry.Dependabot = append(entry.Dependabot, SecurityAlert{
-			AlertType:       AlertTypeDependabot,
-			AdvisorySummary: a.advisorySummary,
-			Severity:        a.severity,
-		})
 	}
 
 	result := make([]RepoSecurityAlerts, 0, len(byRepo))
@@ -159,29 +190,36 @@ func getOrCreate(m map[string]*RepoSecurityAlerts, repository Repository) *RepoS
 type orgSecretAlert struct {
 	repo       Repository
 	url        string
+	createdAt  time.Time
 	secretType string
 }
 
 type orgCodeScanningAlert struct {
 	repo            Repository
+	url             string
+	createdAt       time.Time
 	ruleDescription string
 	severity        string
 }
 
 type orgDependabotAlert struct {
 	repo            Repository
+	url             string
+	createdAt       time.Time
 	advisorySummary string
 	severity        string
 }
 
-func fetchOrgSecretScanningAlerts(ctx context.Context, httpClient http.Client, bearerToken, org string) ([]orgSecretAlert, error) {
+func fetchOrgSecretScanningAlerts(ctx context.Context, httpClient *http.Client, apiURL, org string) ([]orgSecretAlert, error) {
 	type apiAlert struct {
+		URL               string     `json:"html_url"`
+		CreatedAt         time.Time  `json:"created_at"`
 		SecretTypeDisplay string     `json:"secret_type_display_name"`
 		Repository        Repository `json:"repository"`
 	}
 
-	raw, err := fetchAllPages[apiAlert](ctx, httpClient, bearerToken,
-		fmt.Sprintf("https://api.github.com/orgs/%s/secret-scanning/alerts?state=open&per_page=100", org))
+	raw, err := fetchAllPages[apiAlert](ctx, httpClient,
+		fmt.Sprintf("%s/orgs/%s/secret-scanning/alerts?state=open&per_page=100", apiURL, org))
 	if err != nil {
 		return nil, err
 	}
@@ -190,24 +228,27 @@ func fetchOrgSecretScanningAlerts(ctx context.Context, httpClient http.Client, b
 	for _, a := range raw {
 		result = append(result, orgSecretAlert{
 			repo:       a.Repository,
-			url:        a.Repository.URL,
+			url:        a.URL,
+			createdAt:  a.CreatedAt,
 			secretType: a.SecretTypeDisplay,
 		})
 	}
 	return result, nil
 }
 
-func fetchOrgCodeScanningAlerts(ctx context.Context, httpClient http.Client, bearerToken, org string) ([]orgCodeScanningAlert, error) {
+func fetchOrgCodeScanningAlerts(ctx context.Context, httpClient *http.Client, apiURL, org string) ([]orgCodeScanningAlert, error) {
 	type apiAlert struct {
-		Rule struct {
+		URL       string    `json:"html_url"`
+		CreatedAt time.Time `json:"created_at"`
+		Rule      struct {
 			Description           string `json:"description"`
 			SecuritySeverityLevel string `json:"security_severity_level"`
 		} `json:"rule"`
 		Repository Repository `json:"repository"`
 	}
 
-	raw, err := fetchAllPages[apiAlert](ctx, httpClient, bearerToken,
-		fmt.Sprintf("https://api.github.com/orgs/%s/code-scanning/alerts?state=open&per_page=100", org))
+	raw, err := fetchAllPages[apiAlert](ctx, httpClient,
+		fmt.Sprintf("%s/orgs/%s/code-scanning/alerts?state=open&per_page=100", apiURL, org))
 	if err != nil {
 		return nil, err
 	}
@@ -216,6 +257,8 @@ func fetchOrgCodeScanningAlerts(ctx context.Context, httpClient http.Client, bea
 	for _, a := range raw {
 		result = append(result, orgCodeScanningAlert{
 			repo:            a.Repository,
+			url:             a.URL,
+			createdAt:       a.CreatedAt,
 			ruleDescription: a.Rule.Description,
 			severity:        a.Rule.SecuritySeverityLevel,
 		})
@@ -223,8 +266,10 @@ func fetchOrgCodeScanningAlerts(ctx context.Context, httpClient http.Client, bea
 	return result, nil
 }
 
-func fetchOrgDependabotAlerts(ctx context.Context, httpClient http.Client, bearerToken, org string) ([]orgDependabotAlert, error) {
+func fetchOrgDependabotAlerts(ctx context.Context, httpClient *http.Client, apiURL, org string) ([]orgDependabotAlert, error) {
 	type apiAlert struct {
+		URL              string    `json:"html_url"`
+		CreatedAt        time.Time `json:"created_at"`
 		SecurityAdvisory struct {
 			Summary  string `json:"summary"`
 			Severity string `json:"severity"`
@@ -232,8 +277,8 @@ func fetchOrgDependabotAlerts(ctx context.Context, httpClient http.Client, beare
 		Repository Repository `json:"repository"`
 	}
 
-	raw, err := fetchAllPages[apiAlert](ctx, httpClient, bearerToken,
-		fmt.Sprintf("https://api.github.com/orgs/%s/dependabot/alerts?state=open&per_page=100", org))
+	raw, err := fetchAllPages[apiAlert](ctx, httpClient,
+		fmt.Sprintf("%s/orgs/%s/dependabot/alerts?state=open&per_page=100", apiURL, org))
 	if err != nil {
 		return nil, err
 	}
@@ -242,6 +287,8 @@ func fetchOrgDependabotAlerts(ctx context.Context, httpClient http.Client, beare
 	for _, a := range raw {
 		result = append(result, orgDependabotAlert{
 			repo:            a.Repository,
+			url:             a.URL,
+			createdAt:       a.CreatedAt,
 			advisorySummary: a.SecurityAdvisory.Summary,
 			severity:        a.SecurityAdvisory.Severity,
 		})
@@ -249,7 +296,7 @@ func fetchOrgDependabotAlerts(ctx context.Context, httpClient http.Client, beare
 	return result, nil
 }
 
-func fetchAllPages[T any](ctx context.Context, httpClient http.Client, bearerToken, url string) ([]T, error) {
+func fetchAllPages[T any](ctx context.Context, httpClient *http.Client, url string) ([]T, error) {
 	var result []T
 	next := url
 
@@ -258,7 +305,6 @@ func fetchAllPages[T any](ctx context.Context, httpClient http.Client, bearerTok
 		if err != nil {
 			return nil, err
 		}
-		req.Header.Add("Authorization", "Bearer "+bearerToken)
 		req.Header.Add("Content-Type", "application/json")
 
 		resp, err := httpClient.Do(req)
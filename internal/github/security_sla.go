@@ -0,0 +1,53 @@
+package github
+
+import (
+	"fmt"
+	"time"
+)
+
+// SecuritySLA is how many days a team has to close security alerts of each
+// severity, where zero means no deadline. Secret scanning alerts have no
+// severity, and count as critical.
+type SecuritySLA struct {
+	Critical int `yaml:"critical"`
+	High     int `yaml:"high"`
+	Medium   int `yaml:"medium"`
+	Low      int `yaml:"low"`
+	// EscalationChannel gets the escalations, in addition to the thread of the alert.
+	EscalationChannel string `yaml:"escalationChannel"`
+}
+
+// Days returns the number of days to close an alert of the severity.
+func (s SecuritySLA) Days(severity SeverityType) int {
+	switch severity {
+	case SeverityCritical:
+		return s.Critical
+	case SeverityHigh:
+		return s.High
+	case SeverityMedium:
+		return s.Medium
+	default:
+		return s.Low
+	}
+}
+
+// Deadline returns when an alert first seen at the given time must be closed,
+// or false if the severity has no deadline.
+func (s SecuritySLA) Deadline(severity SeverityType, firstSeen time.Time) (time.Time, bool) {
+	days := s.Days(severity)
+	if days <= 0 {
+		return time.Time{}, false
+	}
+
+	return firstSeen.AddDate(0, 0, days), true
+}
+
+func validateSecuritySLA(teamName string, s *SecuritySLA) error {
+	for severity, days := range map[string]int{"critical": s.Critical, "high": s.High, "medium": s.Medium, "low": s.Low} {
+		if days < 0 {
+			return fmt.Errorf("team %s: securitySLA.%s must be zero or more days", teamName, severity)
+		}
+	}
+
+	return nil
+}
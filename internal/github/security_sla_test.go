@@ -0,0 +1,31 @@
+package github
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSecuritySLADeadline(t *testing.T) {
+	sla := SecuritySLA{Critical: 7, High: 30}
+	firstSeen := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		severity SeverityType
+		expected time.Time
+		ok       bool
+	}{
+		{name: "critical", severity: SeverityCritical, expected: time.Date(2026, 10, 8, 12, 0, 0, 0, time.UTC), ok: true},
+		{name: "high", severity: SeverityHigh, expected: time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC), ok: true},
+		{name: "no deadline", severity: SeverityMedium},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sla.Deadline(tt.severity, firstSeen)
+			if ok != tt.ok || !got.Equal(tt.expected) {
+				t.Errorf("Deadline() = %v, %v, expected %v, %v", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
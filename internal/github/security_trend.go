@@ -0,0 +1,83 @@
+package github
+
+import (
+	"fmt"
+	"slices"
+)
+
+// SecurityDigestSnapshot is what a security digest saw, to compare the next digest with.
+type SecurityDigestSnapshot struct {
+	// Open is the number of open alerts per repository.
+	Open map[string]int
+	// Alerts are the URLs of the open alerts.
+	Alerts []string
+	// Critical are the URLs of the open critical alerts.
+	Critical []string
+}
+
+// SecurityTrend compares the open alerts with the previous security digest.
+type SecurityTrend struct {
+	// New are the open alerts that were not open in the previous digest, as
+	// they were created or reopened since.
+	New int
+	// Fixed are the alerts open in the previous digest that are no longer
+	// open, as they were closed in any way. Alerts both created and closed
+	// between two digests are neither new nor fixed.
+	Fixed int
+	// Changes are the change in open alerts per repository.
+	Changes map[string]int
+	// NewlyCritical are the critical alerts that were not critical, or not open, in the previous digest.
+	NewlyCritical []SecurityAlert
+}
+
+// NewSecurityTrend compares the open alerts with the previous digest.
+func NewSecurityTrend(previous SecurityDigestSnapshot, repoAlerts []RepoSecurityAlerts) SecurityTrend {
+	trend := SecurityTrend{Changes: map[string]int{}}
+
+	for repository, open := range previous.Open {
+		trend.Changes[repository] = -open
+	}
+
+	previouslyOpen := map[string]bool{}
+	for _, url := range previous.Alerts {
+		previouslyOpen[url] = true
+	}
+
+	open := map[string]bool{}
+	for _, repo := range repoAlerts {
+		trend.Changes[repo.Repository.Name] += repo.Total()
+
+		for _, alert := range repo.All() {
+			open[alert.URL] = true
+			if !previouslyOpen[alert.URL] {
+				trend.New++
+			}
+
+			if alert.IsCritical() && !slices.Contains(previous.Critical, alert.URL) {
+				trend.NewlyCritical = append(trend.NewlyCritical, alert)
+			}
+		}
+	}
+
+	for url := range previouslyOpen {
+		if !open[url] {
+			trend.Fixed++
+		}
+	}
+
+	return trend
+}
+
+// Delta returns the change in open alerts for the repository, like ▲2 or ▼1,
+// or an empty string if unchanged.
+func (t *SecurityTrend) Delta(repository string) string {
+	change := t.Changes[repository]
+	switch {
+	case change > 0:
+		return fmt.Sprintf("▲%d", change)
+	case change < 0:
+		return fmt.Sprintf("▼%d", -change)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,52 @@
+package github
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSecurityTrend(t *testing.T) {
+	sentAt := time.Date(2026, 10, 12, 8, 0, 0, 0, time.UTC)
+	previous := SecurityDigestSnapshot{
+		Open: map[string]int{"ghep": 3, "fixed": 2},
+		Alerts: []string{
+			"https://github.com/navikt/ghep/security/dependabot/1",
+			"https://github.com/navikt/ghep/security/dependabot/3",
+			"https://github.com/navikt/ghep/security/code-scanning/1",
+			"https://github.com/navikt/fixed/security/dependabot/1",
+			"https://github.com/navikt/fixed/security/dependabot/2",
+		},
+		Critical: []string{"https://github.com/navikt/ghep/security/dependabot/1"},
+	}
+
+	repoAlerts := []RepoSecurityAlerts{
+		{
+			Repository: Repository{Name: "ghep"},
+			Dependabot: []SecurityAlert{
+				{AlertType: AlertTypeDependabot, URL: "https://github.com/navikt/ghep/security/dependabot/1", Severity: "critical", CreatedAt: sentAt.Add(-24 * time.Hour)},
+				{AlertType: AlertTypeDependabot, URL: "https://github.com/navikt/ghep/security/dependabot/2", Severity: "critical", CreatedAt: sentAt.Add(24 * time.Hour)},
+			},
+		},
+		{
+			Repository:     Repository{Name: "new"},
+			SecretScanning: []SecurityAlert{{AlertType: AlertTypeSecretScanning, URL: "https://github.com/navikt/new/security/secret-scanning/1", CreatedAt: sentAt.Add(-time.Hour)}},
+		},
+	}
+
+	trend := NewSecurityTrend(previous, repoAlerts)
+
+	// 5 open before, of which 1 is still open, and 1 created and 1 reopened since
+	if trend.New != 2 || trend.Fixed != 4 {
+		t.Errorf("expected 2 new and 4 fixed, got %d new and %d fixed", trend.New, trend.Fixed)
+	}
+
+	for repository, expected := range map[string]string{"ghep": "▼1", "fixed": "▼2", "new": "▲1", "unknown": ""} {
+		if got := trend.Delta(repository); got != expected {
+			t.Errorf("Delta(%q) = %q, expected %q", repository, got, expected)
+		}
+	}
+
+	if len(trend.NewlyCritical) != 1 || trend.NewlyCritical[0].URL != "https://github.com/navikt/ghep/security/dependabot/2" {
+		t.Errorf("expected only dependabot/2 to be newly critical, got %+v", trend.NewlyCritical)
+	}
+}
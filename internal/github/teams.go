@@ -7,6 +7,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/mail"
 	"os"
 	"path/filepath"
 	"slices"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/navikt/ghep/internal/sql"
+	"github.com/navikt/ghep/internal/sql/gensql"
 	"gopkg.in/yaml.v3"
 )
 
@@ -28,10 +30,26 @@ type DependabotConfig string
 
 const (
 	DependabotConfigAlways DependabotConfig = "always"
+	// DependabotConfigRollup posts Dependabot and Renovate pull requests in a daily roll-up instead.
+	DependabotConfigRollup DependabotConfig = "rollup"
+
+	// NotifierSlack is the default notifier, and is used when no notifier is set.
+	NotifierSlack   = "slack"
+	NotifierMSTeams = "msteams"
+
+	// Messages posted to Microsoft Teams are keyed by the source type, or one
+	// of these for messages not posted for a source.
+	NotifyKeyPullRequestDigest  = "pull-request-digest"
+	NotifyKeySecurityDigest     = "security-digest"
+	NotifyKeyDependencyRollup   = "dependency-rollup"
+	NotifyKeySecurityEscalation = "security-escalation"
 
 	TeamNameExternalContributors = "external-contributors"
 )
 
+// SourceTypes are the event types a source can post.
+var SourceTypes = []string{"commits", "pulls", "issues", "workflows", "releases", "security"}
+
 type Config struct {
 	ExternalContributorsChannel string           `yaml:"externalContributorsChannel"`
 	Workflows                   Workflows        `yaml:"workflows"`
@@ -40,6 +58,16 @@ type Config struct {
 	Security                    Security         `yaml:"security"`
 	PingSlackUsers              bool             `yaml:"pingSlackUsers"`
 	Pulls                       PullsConfig      `yaml:"pulls"`
+	// BlockKit renders Slack messages with Block Kit instead of legacy attachments.
+	BlockKit bool `yaml:"blockKit"`
+	// DeliveryWindow is when events are posted, for sources without their own window.
+	DeliveryWindow *DeliveryWindow `yaml:"deliveryWindow"`
+	// SecuritySLA escalates security alerts left open past their deadline.
+	SecuritySLA *SecuritySLA `yaml:"securitySLA"`
+	// Redaction adds the team's own patterns for secrets hidden from posted messages.
+	Redaction Redaction `yaml:"redaction"`
+	// PrivateContent is what is posted from private and internal repositories to public channels.
+	PrivateContent PrivateContentPolicy `yaml:"privateContent"`
 }
 
 type PullsConfig struct {
@@ -50,10 +78,21 @@ type PullsConfig struct {
 	Events       []string `yaml:"events"`
 }
 
+// IsKnown returns false for values other than always and rollup. They were
+// ignored before rollup was added, and still are, so teams.yaml files with
+// them keep working.
+func (d DependabotConfig) IsKnown() bool {
+	return d == "" || d == DependabotConfigAlways || d == DependabotConfigRollup
+}
+
 func (c Config) ShouldSilenceDependabot() bool {
 	return c.SilenceDependabot == DependabotConfigAlways
 }
 
+func (c Config) ShouldRollUpDependencies() bool {
+	return c.SilenceDependabot == DependabotConfigRollup
+}
+
 type Security struct {
 	SeverityFilter string `yaml:"severityFilter"`
 }
@@ -83,6 +122,8 @@ type SourceConfig struct {
 	Pulls     PullsConfig `yaml:"pulls"`
 	Workflows Workflows   `yaml:"workflows"`
 	Security  Security    `yaml:"security"`
+	// DeliveryWindow is when the source's events are posted, overriding the team's window.
+	DeliveryWindow *DeliveryWindow `yaml:"deliveryWindow"`
 }
 
 // Source defines a single event-type-to-channel mapping with optional config.
@@ -90,6 +131,19 @@ type Source struct {
 	SourceType string       `yaml:"source"`
 	Channel    string       `yaml:"channel"`
 	Config     SourceConfig `yaml:"config"`
+	// Notifier is where messages are sent, either slack or msteams. Defaults to the team's notifier.
+	Notifier string `yaml:"notifier"`
+	// Webhook is the name of an outgoing webhook receiving the source's events as CloudEvents.
+	Webhook string `yaml:"webhook"`
+}
+
+// Slug returns the team's slug on Github, without the organization.
+func (t Team) Slug() string {
+	if t.Org == "" {
+		return t.Name
+	}
+
+	return strings.TrimPrefix(t.Name, t.Org+"/")
 }
 
 func (t Team) IsExternalContributor() bool {
@@ -104,6 +158,9 @@ type DigestConfig struct {
 	SendEmpty          bool     `yaml:"send_empty"`
 	SpecifyTeamName    bool     `yaml:"specifyTeamName"`
 	IgnoreRepositories []string `yaml:"ignoreRepositories"`
+	Notifier           string   `yaml:"notifier"`
+	// Email is a list of addresses that also receive the digest by email.
+	Email []string `yaml:"email"`
 }
 
 type SecurityDigestConfig struct {
@@ -115,6 +172,34 @@ type SecurityDigestConfig struct {
 	SpecifyTeamName    bool     `yaml:"specifyTeamName"`
 	SeverityFilter     string   `yaml:"severity_filter"`
 	IgnoreRepositories []string `yaml:"ignoreRepositories"`
+	Notifier           string   `yaml:"notifier"`
+	// Email is a list of addresses that also receive the digest by email.
+	Email []string `yaml:"email"`
+}
+
+// DependencyRollupConfig is when and where the daily roll-up of dependency
+// pull requests is posted, for teams with silenceDependabot set to rollup.
+type DependencyRollupConfig struct {
+	Channel  string `yaml:"channel"`
+	Time     string `yaml:"time"`
+	Timezone string `yaml:"timezone"`
+	Notifier string `yaml:"notifier"`
+}
+
+// ScheduledAt returns when the roll-up is posted on the day of now, in the
+// roll-up's timezone.
+func (c DependencyRollupConfig) ScheduledAt(now time.Time) (time.Time, error) {
+	tz := c.Timezone
+	if tz == "" {
+		tz = "Europe/Oslo"
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return TimeOfDay(now, c.Time, loc)
 }
 
 func TitleCaseSlug(slug string) string {
@@ -133,12 +218,62 @@ func (s SecurityDigestConfig) SeverityType() SeverityType {
 }
 
 type Team struct {
-	Name              string
-	SlackChannels     SlackChannels         `yaml:",inline"`
-	Config            Config                `yaml:"config"`
-	Sources           []Source              `yaml:"sources"`
-	PullRequestDigest *DigestConfig         `yaml:"pr-digest"`
-	SecurityDigest    *SecurityDigestConfig `yaml:"security-digest"`
+	Name string
+	// Org is set when the team belongs to another organization than GITHUB_ORG,
+	// from a team configured as "org/slug".
+	Org string
+	// SlackWorkspace is the named Slack workspace the team's channels are in,
+	// where empty is the default workspace.
+	SlackWorkspace string `yaml:"slackWorkspace"`
+	// Notifier is the default notifier for the team's sources and digests, either slack or msteams.
+	Notifier          string                  `yaml:"notifier"`
+	SlackChannels     SlackChannels           `yaml:",inline"`
+	Config            Config                  `yaml:"config"`
+	Sources           []Source                `yaml:"sources"`
+	PullRequestDigest *DigestConfig           `yaml:"pr-digest"`
+	SecurityDigest    *SecurityDigestConfig   `yaml:"security-digest"`
+	DependencyRollup  *DependencyRollupConfig `yaml:"dependency-rollup"`
+}
+
+// NotifierForChannel returns the notifier of the source posting to the channel,
+// falling back to the team's notifier.
+func (t Team) NotifierForChannel(channel string) string {
+	for _, s := range t.Sources {
+		if s.Channel == channel {
+			return s.Notifier
+		}
+	}
+
+	return t.Notifier
+}
+
+// MSTeamsKeys returns the keys of the team's messages posted to Microsoft Teams.
+func (t Team) MSTeamsKeys() []string {
+	var keys []string
+	for _, source := range t.Sources {
+		if source.Notifier == NotifierMSTeams && source.Channel != "" && !slices.Contains(keys, source.SourceType) {
+			keys = append(keys, source.SourceType)
+		}
+	}
+	if t.PullRequestDigest != nil && t.PullRequestDigest.Notifier == NotifierMSTeams {
+		keys = append(keys, NotifyKeyPullRequestDigest)
+	}
+	if t.SecurityDigest != nil && t.SecurityDigest.Notifier == NotifierMSTeams {
+		keys = append(keys, NotifyKeySecurityDigest)
+	}
+	if t.DependencyRollup != nil && t.DependencyRollup.Notifier == NotifierMSTeams {
+		keys = append(keys, NotifyKeyDependencyRollup)
+	}
+	if sla := t.Config.SecuritySLA; sla != nil && sla.EscalationChannel != "" && t.NotifierForChannel(sla.EscalationChannel) == NotifierMSTeams {
+		keys = append(keys, NotifyKeySecurityEscalation)
+	}
+
+	return keys
+}
+
+// UsesBlockKit returns true if messages posted with the notifier should be rendered with Block Kit.
+func (t Team) UsesBlockKit(notifier string) bool {
+	return t.Config.BlockKit && notifier != NotifierMSTeams
 }
 
 // SourcesForType returns all sources matching the given event type.
@@ -155,7 +290,8 @@ func (t Team) SourcesForType(eventType EventType) []Source {
 		sourceType = "workflows"
 	case TypeRelease:
 		sourceType = "releases"
-	case TypeCodeScanningAlert, TypeDependabotAlert, TypeSecretScanningAlert, TypeSecurityAdvisory:
+	case TypeCodeScanningAlert, TypeDependabotAlert, TypeSecretScanningAlert, TypeSecurityAdvisory, TypeRepositoryAdvisory,
+		TypeSecurityAndAnalysis, TypeBranchProtectionRule, TypeRepositoryRuleset, TypeForcePush:
 		sourceType = "security"
 	default:
 		return nil
@@ -175,7 +311,7 @@ type githubError struct {
 	Status  string `json:"status"`
 }
 
-func fetchRepositories(teamURL, bearerToken string, blocklist []string) ([]string, error) {
+func fetchRepositories(httpClient *http.Client, teamURL string, blocklist []string) ([]string, error) {
 	req, err := http.NewRequest("GET", teamURL+"/repos", nil)
 	if err != nil {
 		return nil, err
@@ -184,13 +320,8 @@ func fetchRepositories(teamURL, bearerToken string, blocklist []string) ([]strin
 	query := req.URL.Query()
 	query.Set("per_page", "100")
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %v", bearerToken))
 	req.Header.Add("Content-Type", "application/json")
 
-	httpClient := http.Client{
-		Timeout: 10 * time.Second,
-	}
-
 	type GithubRepo struct {
 		Name     string `json:"name"`
 		Archived bool   `json:"archived"`
@@ -250,73 +381,149 @@ func fetchRepositories(teamURL, bearerToken string, blocklist []string) ([]strin
 }
 
 type teamsFile struct {
-	PersonalDigest []PersonalDigestUserEntry `yaml:"personal-digest"`
-	Teams          map[string]Team           `yaml:"teams"`
+	PersonalDigest        []PersonalDigestUserEntry `yaml:"personal-digest"`
+	PersonalNotifications []PersonalNotifications   `yaml:"personal-notifications"`
+	Teams                 map[string]Team           `yaml:"teams"`
 }
 
-func ParseTeamConfig(path string) (map[string]Team, []PersonalDigestUserEntry, error) {
+func ParseTeamConfig(path string) (map[string]Team, []PersonalDigestUserEntry, []PersonalNotifications, error) {
 	file, err := os.Open(filepath.Clean(path))
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	defer file.Close()
 
 	var tf teamsFile
 	if err := yaml.NewDecoder(file).Decode(&tf); err != nil {
-		return nil, nil, fmt.Errorf("decoding team config: %v", err)
+		return nil, nil, nil, fmt.Errorf("decoding team config: %v", err)
 	}
 
 	for i := range tf.PersonalDigest {
 		if err := applyPersonalDigestDefaults(&tf.PersonalDigest[i]); err != nil {
-			return nil, nil, fmt.Errorf("personal-digest: user %q: %w", tf.PersonalDigest[i].Login, err)
+			return nil, nil, nil, fmt.Errorf("personal-digest: user %q: %w", tf.PersonalDigest[i].Login, err)
 		}
 	}
 
-	validSourceTypes := map[string]bool{
-		"commits":   true,
-		"pulls":     true,
-		"issues":    true,
-		"workflows": true,
-		"releases":  true,
-		"security":  true,
+	for i := range tf.PersonalNotifications {
+		if err := ApplyPersonalNotificationsDefaults(&tf.PersonalNotifications[i]); err != nil {
+			return nil, nil, nil, fmt.Errorf("personal-notifications: user %q: %w", tf.PersonalNotifications[i].Login, err)
+		}
 	}
 
 	teams := tf.Teams
 	for name, team := range teams {
 		team.Name = name
+		if org, slug, ok := strings.Cut(name, "/"); ok {
+			if org == "" || slug == "" || strings.Contains(slug, "/") {
+				return nil, nil, nil, fmt.Errorf("team %s: must be either a team slug or org/slug", name)
+			}
+			team.Org = org
+		}
 
 		for _, s := range team.Sources {
-			if !validSourceTypes[s.SourceType] {
-				return nil, nil, fmt.Errorf("team %s: invalid source type %q", name, s.SourceType)
+			if !slices.Contains(SourceTypes, s.SourceType) {
+				return nil, nil, nil, fmt.Errorf("team %s: invalid source type %q", name, s.SourceType)
+			}
+			if !validNotifier(s.Notifier) {
+				return nil, nil, nil, fmt.Errorf("team %s: invalid notifier %q for source %s", name, s.Notifier, s.SourceType)
+			}
+			if s.Config.DeliveryWindow != nil {
+				if err := applyDeliveryWindowDefaults(s.Config.DeliveryWindow); err != nil {
+					return nil, nil, nil, fmt.Errorf("team %s: deliveryWindow for source %s: %w", name, s.SourceType, err)
+				}
+			}
+		}
+
+		if team.Config.DeliveryWindow != nil {
+			if err := applyDeliveryWindowDefaults(team.Config.DeliveryWindow); err != nil {
+				return nil, nil, nil, fmt.Errorf("team %s: deliveryWindow: %w", name, err)
 			}
 		}
 
+		if !validNotifier(team.Notifier) {
+			return nil, nil, nil, fmt.Errorf("team %s: invalid notifier %q", name, team.Notifier)
+		}
+
+		if team.Config.SecuritySLA != nil {
+			if err := validateSecuritySLA(name, team.Config.SecuritySLA); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+
+		if err := team.Config.Redaction.compile(name); err != nil {
+			return nil, nil, nil, err
+		}
+
+		if err := validatePrivateContentPolicy(name, team.Config.PrivateContent); err != nil {
+			return nil, nil, nil, err
+		}
+
 		if team.PullRequestDigest != nil {
 			if err := validateDigestConfig(name, team.PullRequestDigest); err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
+			}
+			if !validNotifier(team.PullRequestDigest.Notifier) {
+				return nil, nil, nil, fmt.Errorf("team %s: invalid notifier %q for digest", name, team.PullRequestDigest.Notifier)
+			}
+			if team.PullRequestDigest.Notifier == "" {
+				team.PullRequestDigest.Notifier = team.Notifier
 			}
 		}
 
 		if team.SecurityDigest != nil {
 			if err := validateSecurityDigestConfig(name, team.SecurityDigest); err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
+			}
+			if !validNotifier(team.SecurityDigest.Notifier) {
+				return nil, nil, nil, fmt.Errorf("team %s: invalid notifier %q for digest", name, team.SecurityDigest.Notifier)
+			}
+			if team.SecurityDigest.Notifier == "" {
+				team.SecurityDigest.Notifier = team.Notifier
+			}
+		}
+
+		if err := validateDependencyRollup(name, team.Config.SilenceDependabot, team.DependencyRollup); err != nil {
+			return nil, nil, nil, err
+		}
+		if team.DependencyRollup != nil {
+			if !validNotifier(team.DependencyRollup.Notifier) {
+				return nil, nil, nil, fmt.Errorf("team %s: invalid notifier %q for dependency-rollup", name, team.DependencyRollup.Notifier)
+			}
+			if team.DependencyRollup.Notifier == "" {
+				team.DependencyRollup.Notifier = team.Notifier
 			}
 		}
 
 		flatSources := flatChannelsToSources(team.SlackChannels, team.Config)
 		team.Sources = append(flatSources, team.Sources...)
 
+		for i := range team.Sources {
+			if team.Sources[i].Notifier == "" {
+				team.Sources[i].Notifier = team.Notifier
+			}
+			if team.Sources[i].Config.DeliveryWindow == nil {
+				team.Sources[i].Config.DeliveryWindow = team.Config.DeliveryWindow
+			}
+		}
+
 		teams[name] = team
 	}
 
-	return teams, tf.PersonalDigest, nil
+	return teams, tf.PersonalDigest, tf.PersonalNotifications, nil
+}
+
+func validNotifier(notifier string) bool {
+	return notifier == "" || notifier == NotifierSlack || notifier == NotifierMSTeams
 }
 
 var validWeekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
 
 func validateDigestConfig(teamName string, d *DigestConfig) error {
-	if d.Channel == "" {
-		return fmt.Errorf("team %s: pr-digest.channel is required", teamName)
+	if d.Channel == "" && len(d.Email) == 0 {
+		return fmt.Errorf("team %s: pr-digest.channel or pr-digest.email is required", teamName)
+	}
+	if err := validateEmails(d.Email); err != nil {
+		return fmt.Errorf("team %s: pr-digest.email: %w", teamName, err)
 	}
 	if !slices.Contains(validWeekdays, strings.ToLower(d.Day)) {
 		return fmt.Errorf("team %s: pr-digest.day %q is not a valid weekday", teamName, d.Day)
@@ -335,8 +542,11 @@ func validateDigestConfig(teamName string, d *DigestConfig) error {
 }
 
 func validateSecurityDigestConfig(teamName string, d *SecurityDigestConfig) error {
-	if d.Channel == "" {
-		return fmt.Errorf("team %s: security-digest.channel is required", teamName)
+	if d.Channel == "" && len(d.Email) == 0 {
+		return fmt.Errorf("team %s: security-digest.channel or security-digest.email is required", teamName)
+	}
+	if err := validateEmails(d.Email); err != nil {
+		return fmt.Errorf("team %s: security-digest.email: %w", teamName, err)
 	}
 	if !slices.Contains(validWeekdays, strings.ToLower(d.Day)) {
 		return fmt.Errorf("team %s: security-digest.day %q is not a valid weekday", teamName, d.Day)
@@ -354,6 +564,43 @@ func validateSecurityDigestConfig(teamName string, d *SecurityDigestConfig) erro
 	return nil
 }
 
+func validateDependencyRollup(teamName string, silence DependabotConfig, d *DependencyRollupConfig) error {
+	if silence != DependabotConfigRollup {
+		if d != nil {
+			return fmt.Errorf("team %s: dependency-rollup requires config.silenceDependabot to be %q", teamName, DependabotConfigRollup)
+		}
+		return nil
+	}
+
+	if d == nil {
+		return fmt.Errorf("team %s: config.silenceDependabot %q requires dependency-rollup", teamName, DependabotConfigRollup)
+	}
+
+	if d.Channel == "" {
+		return fmt.Errorf("team %s: dependency-rollup.channel is required", teamName)
+	}
+	if _, err := time.Parse("15:04", d.Time); err != nil {
+		return fmt.Errorf("team %s: dependency-rollup.time %q must be in HH:MM format", teamName, d.Time)
+	}
+	tz := d.Timezone
+	if tz == "" {
+		tz = "Europe/Oslo"
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("team %s: dependency-rollup.timezone %q is not a valid IANA timezone", teamName, d.Timezone)
+	}
+	return nil
+}
+
+func validateEmails(addresses []string) error {
+	for _, address := range addresses {
+		if _, err := mail.ParseAddress(address); err != nil {
+			return fmt.Errorf("%q is not a valid address", address)
+		}
+	}
+	return nil
+}
+
 // flatChannelsToSources converts the old flat channel format into sources for backward compatibility.
 func flatChannelsToSources(channels SlackChannels, cfg Config) []Source {
 	var sources []Source
@@ -407,19 +654,14 @@ func flatChannelsToSources(channels SlackChannels, cfg Config) []Source {
 	return sources
 }
 
-func validateOrgExists(url, bearerToken string) error {
+func validateOrgExists(httpClient *http.Client, url string) error {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("creating request: %v", err)
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %v", bearerToken))
 	req.Header.Add("Content-Type", "application/json")
 
-	httpClient := http.Client{
-		Timeout: 10 * time.Second,
-	}
-
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("making request: %v", err)
@@ -433,19 +675,14 @@ func validateOrgExists(url, bearerToken string) error {
 	return nil
 }
 
-func validateTeamExists(teamURL, bearerToken string) (bool, error) {
+func validateTeamExists(httpClient *http.Client, teamURL string) (bool, error) {
 	req, err := http.NewRequest("GET", teamURL, nil)
 	if err != nil {
 		return false, fmt.Errorf("creating request: %v", err)
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %v", bearerToken))
 	req.Header.Add("Content-Type", "application/json")
 
-	httpClient := http.Client{
-		Timeout: 10 * time.Second,
-	}
-
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		return false, fmt.Errorf("making request: %v", err)
@@ -464,70 +701,59 @@ func validateTeamExists(teamURL, bearerToken string) (bool, error) {
 
 // FetchOrgAsTeam fetches the organization as a team, hence there needs to be a team in the organization with the same name as the organization.
 func (c Client) FetchOrgAsTeam(ctx context.Context, log *slog.Logger, reposBlocklist []string) error {
-	bearerToken, err := c.createBearerToken()
-	if err != nil {
-		return fmt.Errorf("creating bearer token: %v", err)
-	}
+	team := c.TeamName(c.org)
 
 	// Ensure team exists in the database
-	if err := c.db.CreateTeam(ctx, c.org); err != nil {
+	if err := c.db.CreateTeam(ctx, gensql.CreateTeamParams{Slug: team, Org: OrgKey(c.org), GithubSlug: c.org}); err != nil {
 		return fmt.Errorf("creating team for organization %s: %v", c.org, err)
 	}
 
-	url := fmt.Sprintf("https://api.github.com/orgs/%s", c.org)
-	if err := validateOrgExists(url, bearerToken); err != nil {
+	url := fmt.Sprintf("%s/orgs/%s", c.urls.API, c.org)
+	if err := validateOrgExists(c.httpClient, url); err != nil {
 		return fmt.Errorf("validating organization %s: %v", c.org, err)
 	}
 
 	teamURL := fmt.Sprintf("%s/teams/%s", url, c.org)
-	members, err := fetchMembers(teamURL, bearerToken)
+	members, err := fetchMembers(c.httpClient, teamURL)
 	if err != nil {
 		return fmt.Errorf("fetching members for %s: %v", c.org, err)
 	}
 
-	for _, member := range members {
-		if err := sql.AddMemberToTeam(ctx, c.db, c.org, member.Login); err != nil {
-			log.Error("Adding member to team", "team", c.org, "member", member.Login, "error", err)
-			continue
-		}
+	addedMembers, removedMembers, err := sql.SyncTeamMembers(ctx, c.db, team, loginsOf(members))
+	if err != nil {
+		return fmt.Errorf("syncing members for org %s: %v", c.org, err)
 	}
 
-	repositories, err := fetchRepositories(teamURL, bearerToken, reposBlocklist)
+	repositories, err := fetchRepositories(c.httpClient, teamURL, reposBlocklist)
 	if err != nil {
 		return fmt.Errorf("fetching repositories for %s: %v", c.org, err)
 	}
 
-	for _, repository := range repositories {
-		if err := sql.AddRepositoryToTeam(ctx, c.db, c.org, repository); err != nil {
-			return fmt.Errorf("adding repository %s to org %s: %v", repository, c.org, err)
-		}
-	}
-
-	if err := sql.RemoveRepositoriesNotBelongingToTeam(ctx, c.db, c.org, repositories); err != nil {
-		return fmt.Errorf("cleaning up old repositories: %v", err)
+	addedRepositories, removedRepositories, err := sql.SyncTeamRepositories(ctx, c.db, team, OrgKey(c.org), repositories)
+	if err != nil {
+		return fmt.Errorf("syncing repositories for org %s: %v", c.org, err)
 	}
 
+	logChanges(log, team, addedRepositories, removedRepositories, addedMembers, removedMembers)
 	log.Info("Subscribed to org", "org", c.org, "members", len(members), "repositories", len(repositories))
 
 	return nil
 }
 
+// FetchTeams syncs repositories and members for the teams in the database belonging to the organization.
 func (c Client) FetchTeams(ctx context.Context, log *slog.Logger, reposBlocklist []string) error {
-	bearerToken, err := c.createBearerToken()
-	if err != nil {
-		return fmt.Errorf("creating bearer token: %v", err)
-	}
+	url := fmt.Sprintf("%s/orgs/%s/teams", c.urls.API, c.org)
 
-	url := fmt.Sprintf("https://api.github.com/orgs/%s/teams", c.org)
-
-	teams, err := c.db.ListTeams(ctx)
+	storedTeams, err := c.db.ListTeamsInOrg(ctx, OrgKey(c.org))
 	if err != nil {
 		return fmt.Errorf("listing teams from database: %v", err)
 	}
 
-	for _, team := range teams {
-		teamURL := fmt.Sprintf("%s/%s", url, team)
-		notFound, err := validateTeamExists(teamURL, bearerToken)
+	for _, storedTeam := range storedTeams {
+		team, slug := storedTeam.Slug, storedTeam.GithubSlug
+
+		teamURL := fmt.Sprintf("%s/%s", url, slug)
+		notFound, err := validateTeamExists(c.httpClient, teamURL)
 		if err != nil {
 			log.Error("Could not validate team", "team", team, "error", err)
 			continue
@@ -540,34 +766,54 @@ func (c Client) FetchTeams(ctx context.Context, log *slog.Logger, reposBlocklist
 			continue
 		}
 
-		repositories, err := fetchRepositories(teamURL, bearerToken, reposBlocklist)
+		repositories, err := fetchRepositories(c.httpClient, teamURL, reposBlocklist)
 		if err != nil {
 			return fmt.Errorf("fetching repositories for %s: %v", team, err)
 		}
 
-		for _, repository := range repositories {
-			if err := sql.AddRepositoryToTeam(ctx, c.db, team, repository); err != nil {
-				return fmt.Errorf("adding repository %s to team %s: %v", repository, team, err)
-			}
-		}
-
-		if err := sql.RemoveRepositoriesNotBelongingToTeam(ctx, c.db, team, repositories); err != nil {
-			return fmt.Errorf("cleaning up old repositories: %v", err)
+		addedRepositories, removedRepositories, err := sql.SyncTeamRepositories(ctx, c.db, team, OrgKey(c.org), repositories)
+		if err != nil {
+			return fmt.Errorf("syncing repositories for team %s: %v", team, err)
 		}
 
-		members, err := fetchMembers(teamURL, bearerToken)
+		members, err := fetchMembers(c.httpClient, teamURL)
 		if err != nil {
 			return fmt.Errorf("fetching members for %s: %v", team, err)
 		}
 
-		for _, member := range members {
-			if err := sql.AddMemberToTeam(ctx, c.db, team, member.Login); err != nil {
-				return fmt.Errorf("adding member %s to team %s: %v", member.Login, team, err)
-			}
+		addedMembers, removedMembers, err := sql.SyncTeamMembers(ctx, c.db, team, loginsOf(members))
+		if err != nil {
+			return fmt.Errorf("syncing members for team %s: %v", team, err)
 		}
 
+		logChanges(log, team, addedRepositories, removedRepositories, addedMembers, removedMembers)
 		log.Info("Processed team", "team", team, "repositories", len(repositories), "members", len(members))
 	}
 
 	return nil
 }
+
+func loginsOf(users []*User) []string {
+	logins := make([]string, len(users))
+	for i, user := range users {
+		logins[i] = user.Login
+	}
+
+	return logins
+}
+
+// logChanges logs the difference between what was stored for a team and what was fetched from GitHub.
+func logChanges(log *slog.Logger, team string, addedRepositories, removedRepositories, addedMembers, removedMembers []string) {
+	if len(addedRepositories) > 0 {
+		log.Info("Added repositories to team", "team", team, "repositories", addedRepositories)
+	}
+	if len(removedRepositories) > 0 {
+		log.Info("Removed repositories from team", "team", team, "repositories", removedRepositories)
+	}
+	if len(addedMembers) > 0 {
+		log.Info("Added members to team", "team", team, "members", addedMembers)
+	}
+	if len(removedMembers) > 0 {
+		log.Info("Removed members from team", "team", team, "members", removedMembers)
+	}
+}
@@ -2,8 +2,10 @@ package github
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestParseTeamConfig(t *testing.T) {
@@ -187,18 +189,144 @@ func TestParseTeamConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "teams in multiple organizations",
+			path: "testdata/multi_org.yaml",
+			want: map[string]Team{
+				"nada": {
+					Name:          "nada",
+					SlackChannels: SlackChannels{Commits: "#nada-test"},
+					Sources:       []Source{{SourceType: "commits", Channel: "#nada-test"}},
+				},
+				"other-org/nada": {
+					Name:          "other-org/nada",
+					Org:           "other-org",
+					SlackChannels: SlackChannels{Commits: "#other-nada"},
+					Sources:       []Source{{SourceType: "commits", Channel: "#other-nada"}},
+				},
+			},
+		},
+		{
+			name: "notifiers are inherited from the team",
+			path: "testdata/notifiers.yaml",
+			want: map[string]Team{
+				"nada": {
+					Name:          "nada",
+					Notifier:      NotifierMSTeams,
+					SlackChannels: SlackChannels{Commits: "nada-commits"},
+					Sources: []Source{
+						{SourceType: "commits", Channel: "nada-commits", Notifier: NotifierMSTeams},
+						{SourceType: "pulls", Channel: "#nada-pulls", Notifier: NotifierSlack},
+					},
+					PullRequestDigest: &DigestConfig{
+						Channel:  "nada-digest",
+						Day:      "monday",
+						Time:     "09:00",
+						Timezone: "Europe/Oslo",
+						Notifier: NotifierMSTeams,
+					},
+				},
+			},
+		},
+		{
+			name: "digest sent by email only",
+			path: "testdata/email_digest.yaml",
+			want: map[string]Team{
+				"nada": {
+					Name:          "nada",
+					SlackChannels: SlackChannels{Commits: "#nada-commits"},
+					Sources:       []Source{{SourceType: "commits", Channel: "#nada-commits"}},
+					SecurityDigest: &SecurityDigestConfig{
+						Day:   "friday",
+						Time:  "09:00",
+						Email: []string{"security-champion@example.com"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got, _, err := ParseTeamConfig(test.path)
+			got, _, _, err := ParseTeamConfig(test.path)
 			if err != nil {
 				t.Error(err)
 			}
 
-			if diff := cmp.Diff(test.want, got); diff != "" {
+			if diff := cmp.Diff(test.want, got, cmpopts.IgnoreUnexported(Redaction{})); diff != "" {
 				t.Errorf("parseTeamConfig mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestMSTeamsKeys(t *testing.T) {
+	teams, _, _, err := ParseTeamConfig("testdata/notifiers.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"commits", NotifyKeyPullRequestDigest}
+	if diff := cmp.Diff(want, teams["nada"].MSTeamsKeys()); diff != "" {
+		t.Errorf("MSTeamsKeys mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDependencyRollupScheduledAt(t *testing.T) {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  DependencyRollupConfig
+		now     time.Time
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:   "defaults to Oslo",
+			config: DependencyRollupConfig{Time: "09:00"},
+			now:    time.Date(2026, time.October, 19, 6, 0, 0, 0, time.UTC),
+			want:   time.Date(2026, time.October, 19, 9, 0, 0, 0, oslo),
+		},
+		{
+			name:   "day in the timezone",
+			config: DependencyRollupConfig{Time: "09:00"},
+			now:    time.Date(2026, time.October, 19, 23, 30, 0, 0, time.UTC),
+			want:   time.Date(2026, time.October, 20, 9, 0, 0, 0, oslo),
+		},
+		{
+			name:   "other timezone",
+			config: DependencyRollupConfig{Time: "08:30", Timezone: "UTC"},
+			now:    time.Date(2026, time.October, 19, 6, 0, 0, 0, time.UTC),
+			want:   time.Date(2026, time.October, 19, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "invalid time",
+			config:  DependencyRollupConfig{Time: "9"},
+			now:     time.Date(2026, time.October, 19, 6, 0, 0, 0, time.UTC),
+			wantErr: true,
+		},
+		{
+			name:    "unknown timezone",
+			config:  DependencyRollupConfig{Time: "09:00", Timezone: "Europe/Nowhere"},
+			now:     time.Date(2026, time.October, 19, 6, 0, 0, 0, time.UTC),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.ScheduledAt(tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ScheduledAt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !got.Equal(tt.want) {
+				t.Errorf("ScheduledAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
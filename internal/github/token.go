@@ -32,16 +32,17 @@ func createJWTToken(appID, appPrivateKey string) (string, error) {
 	return jwtToken, nil
 }
 
-func (c Client) createBearerToken() (string, error) {
-	url := fmt.Sprintf("https://api.github.com/app/installations/%v/access_tokens", c.appInstallationID)
+// createInstallationToken creates a new installation access token, and returns it together with when it expires.
+func (c Client) createInstallationToken() (string, time.Time, error) {
+	url := fmt.Sprintf("%s/app/installations/%v/access_tokens", c.urls.API, c.appInstallationID)
 	req, err := http.NewRequest("POST", url, nil)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 
 	jwtToken, err := createJWTToken(c.appID, c.appPrivateKey)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 
 	req.Header.Add("Authorization", "Bearer "+jwtToken)
@@ -53,28 +54,30 @@ func (c Client) createBearerToken() (string, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return "", fmt.Errorf("error getting bearer token, got %v: %v", resp.StatusCode, string(body))
+		return "", time.Time{}, fmt.Errorf("error getting bearer token, got %v: %v", resp.StatusCode, string(body))
 	}
 
-	var bearer map[string]any
+	var bearer struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
 	if err := json.Unmarshal(body, &bearer); err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 
-	token, ok := bearer["token"].(string)
-	if !ok {
-		return "", fmt.Errorf("token not found or not a string in response")
+	if bearer.Token == "" {
+		return "", time.Time{}, fmt.Errorf("token not found in response")
 	}
 
-	return token, nil
+	return bearer.Token, bearer.ExpiresAt, nil
 }
@@ -0,0 +1,245 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// tokenRefreshMargin is how long before expires_at a cached installation token is refreshed.
+	tokenRefreshMargin = 5 * time.Minute
+	// maxRateLimitWait is the longest we wait for a rate limit to reset before giving up.
+	maxRateLimitWait    = 5 * time.Minute
+	maxRateLimitRetries = 3
+)
+
+// installationToken caches the installation access token, and refreshes it
+// shortly before it expires. It is safe for concurrent use.
+type installationToken struct {
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+	fetch     func() (string, time.Time, error)
+}
+
+func (t *installationToken) get() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && time.Until(t.expiresAt) > tokenRefreshMargin {
+		return t.token, nil
+	}
+
+	token, expiresAt, err := t.fetch()
+	if err != nil {
+		return "", err
+	}
+
+	t.token = token
+	t.expiresAt = expiresAt
+
+	return token, nil
+}
+
+// invalidate drops the cached token, if it is still the given one, so the
+// next request fetches a new one.
+func (t *installationToken) invalidate(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == token {
+		t.token = ""
+	}
+}
+
+// transport authenticates every request with the installation token, and
+// waits out primary and secondary rate limits before retrying.
+type transport struct {
+	log   *slog.Logger
+	base  http.RoundTripper
+	token *installationToken
+
+	mu sync.Mutex
+	// resetAt is when each exhausted rate limit resource, like core or graphql, resets.
+	resetAt map[string]time.Time
+}
+
+// newHTTPClient returns a client for the Github API. There is no overall
+// timeout on the client, as waiting for rate limits can take longer than a
+// single request, so the timeout is set on the response headers instead.
+func newHTTPClient(log *slog.Logger, token *installationToken) *http.Client {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.ResponseHeaderTimeout = 30 * time.Second
+
+	return &http.Client{
+		Transport: &transport{
+			log:     log,
+			base:    base,
+			token:   token,
+			resetAt: map[string]time.Time{},
+		},
+	}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.token.get()
+	if err != nil {
+		return nil, fmt.Errorf("creating bearer token: %w", err)
+	}
+
+	resource := rateLimitResource(req)
+	canRetry := req.Body == nil || req.GetBody != nil
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		if err := t.waitForReset(req.Context(), resource); err != nil {
+			return nil, err
+		}
+
+		outgoing := req.Clone(req.Context())
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			outgoing.Body = body
+		}
+		outgoing.Header.Set("Authorization", "Bearer "+token)
+		if outgoing.Header.Get("Accept") == "" {
+			outgoing.Header.Set("Accept", "application/vnd.github+json")
+		}
+
+		resp, err := t.base.RoundTrip(outgoing) // #nosec G704 -- requests are only made to the configured Github API
+		if err != nil {
+			return nil, err
+		}
+
+		// The installation can be uninstalled and reinstalled, or its token revoked
+		if resp.StatusCode == http.StatusUnauthorized {
+			t.token.invalidate(token)
+			if refreshed || !canRetry {
+				return resp, nil
+			}
+
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close() // #nosec G104 -- closing response body, error intentionally ignored
+
+			refreshed = true
+			token, err = t.token.get()
+			if err != nil {
+				return nil, fmt.Errorf("creating bearer token: %w", err)
+			}
+			continue
+		}
+
+		wait, limited := t.rateLimitWait(resp, resource)
+		if !limited {
+			return resp, nil
+		}
+
+		if attempt >= maxRateLimitRetries || wait > maxRateLimitWait || !canRetry {
+			return resp, nil
+		}
+
+		t.log.Info("Rate limited by Github, waiting before retrying", "url", req.URL.Redacted(), "status", resp.Status, "wait", wait.String(), "attempt", attempt+1)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close() // #nosec G104 -- closing response body, error intentionally ignored
+
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rateLimitResource returns the rate limit resource the request counts
+// against, as Github reports it in X-RateLimit-Resource.
+func rateLimitResource(req *http.Request) string {
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/graphql"):
+		return "graphql"
+	case strings.Contains(req.URL.Path, "/search/code"):
+		return "code_search"
+	case strings.Contains(req.URL.Path, "/search/"):
+		return "search"
+	default:
+		return "core"
+	}
+}
+
+// rateLimitWait inspects the rate limit headers, and returns how long to wait
+// if the response was rate limited. It also remembers when the primary rate
+// limit of the resource resets, so that later requests against the same
+// resource wait instead of being rejected.
+func (t *transport) rateLimitWait(resp *http.Response, resource string) (time.Duration, bool) {
+	var resetAt time.Time
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		resetAt = time.Unix(reset, 0)
+	}
+
+	exhausted := resp.Header.Get("X-RateLimit-Remaining") == "0"
+	if exhausted && !resetAt.IsZero() {
+		if header := resp.Header.Get("X-RateLimit-Resource"); header != "" {
+			resource = header
+		}
+
+		t.mu.Lock()
+		t.resetAt[resource] = resetAt
+		t.mu.Unlock()
+	}
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	// Secondary rate limits tell us how long to wait with Retry-After.
+	if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(retryAfter) * time.Second, true
+	}
+
+	if exhausted && !resetAt.IsZero() {
+		return max(time.Until(resetAt), time.Second), true
+	}
+
+	// A 403 without rate limit headers is a permission error, not a rate limit.
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return time.Minute, true
+	}
+
+	return 0, false
+}
+
+func (t *transport) waitForReset(ctx context.Context, resource string) error {
+	t.mu.Lock()
+	resetAt := t.resetAt[resource]
+	t.mu.Unlock()
+
+	wait := time.Until(resetAt)
+	if wait <= 0 {
+		return nil
+	}
+
+	if wait > maxRateLimitWait {
+		return fmt.Errorf("github %s rate limit exhausted until %s", resource, resetAt.Format(time.RFC3339))
+	}
+
+	t.log.Info("Github rate limit exhausted, waiting for reset", "resource", resource, "wait", wait.String())
+	return sleepContext(ctx, wait)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
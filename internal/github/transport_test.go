@@ -0,0 +1,180 @@
+package github
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInstallationTokenIsCached(t *testing.T) {
+	var fetched int
+	token := &installationToken{
+		fetch: func() (string, time.Time, error) {
+			fetched++
+			return "token-" + strconv.Itoa(fetched), time.Now().Add(time.Hour), nil
+		},
+	}
+
+	for range 3 {
+		got, err := token.get()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != "token-1" {
+			t.Errorf("expected cached token-1, got %s", got)
+		}
+	}
+
+	token.expiresAt = time.Now().Add(time.Minute)
+	got, err := token.get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != "token-2" {
+		t.Errorf("expected token to be refreshed close to expiry, got %s", got)
+	}
+}
+
+func TestTransportRetriesSecondaryRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("missing authorization header, got %q", r.Header.Get("Authorization"))
+		}
+
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	token := &installationToken{
+		fetch: func() (string, time.Time, error) {
+			return "secret", time.Now().Add(time.Hour), nil
+		},
+	}
+
+	resp, err := newHTTPClient(slog.Default(), token).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200 after retry, got %d", resp.StatusCode)
+	}
+
+	if requests.Load() != 2 {
+		t.Errorf("expected 2 requests, got %d", requests.Load())
+	}
+}
+
+func TestTransportDoesNotRetryPermissionErrors(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	token := &installationToken{
+		fetch: func() (string, time.Time, error) {
+			return "secret", time.Now().Add(time.Hour), nil
+		},
+	}
+
+	resp, err := newHTTPClient(slog.Default(), token).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", resp.StatusCode)
+	}
+
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %d", requests.Load())
+	}
+}
+
+func TestTransportWaitsPerRateLimitResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			w.Header().Set("X-RateLimit-Resource", "graphql")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	token := &installationToken{
+		fetch: func() (string, time.Time, error) {
+			return "secret", time.Now().Add(time.Hour), nil
+		},
+	}
+	client := newHTTPClient(slog.Default(), token)
+
+	resp, err := client.Post(server.URL+"/graphql", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// REST calls are not held back by the exhausted GraphQL limit
+	resp, err = client.Get(server.URL + "/repos/navikt/ghep")
+	if err != nil {
+		t.Fatalf("expected REST call to go through, got %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := client.Post(server.URL+"/graphql", "application/json", nil); err == nil {
+		t.Error("expected GraphQL call to fail until the limit resets")
+	}
+}
+
+func TestTransportRefreshesTokenOnUnauthorized(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var fetched int
+	token := &installationToken{
+		fetch: func() (string, time.Time, error) {
+			fetched++
+			return "token-" + strconv.Itoa(fetched), time.Now().Add(time.Hour), nil
+		},
+	}
+
+	resp, err := newHTTPClient(slog.Default(), token).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200 with a new token, got %d", resp.StatusCode)
+	}
+
+	if requests.Load() != 2 {
+		t.Errorf("expected 2 requests, got %d", requests.Load())
+	}
+}
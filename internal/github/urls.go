@@ -0,0 +1,86 @@
+package github
+
+import (
+	"net/url"
+	"strings"
+)
+
+const (
+	defaultAPIURL = "https://api.github.com"
+	defaultWebURL = "https://github.com"
+)
+
+// URLs holds the base URLs for the Github REST API, the GraphQL API and the web
+// interface. They differ between github.com, GHE.com data residency and
+// Github Enterprise Server.
+type URLs struct {
+	API     string
+	GraphQL string
+	Web     string
+}
+
+// NewURLs creates base URLs from the given values, deriving the ones left
+// empty from the REST API URL:
+//
+//	https://api.github.com          -> https://api.github.com/graphql, https://github.com
+//	https://api.octocorp.ghe.com    -> https://api.octocorp.ghe.com/graphql, https://octocorp.ghe.com
+//	https://github.example.com/api/v3 -> https://github.example.com/api/graphql, https://github.example.com
+func NewURLs(apiURL, graphqlURL, webURL string) URLs {
+	apiURL = strings.TrimSuffix(apiURL, "/")
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+
+	enterpriseServer := strings.HasSuffix(apiURL, "/api/v3")
+
+	graphqlURL = strings.TrimSuffix(graphqlURL, "/")
+	if graphqlURL == "" {
+		if enterpriseServer {
+			graphqlURL = strings.TrimSuffix(apiURL, "/v3") + "/graphql"
+		} else {
+			graphqlURL = apiURL + "/graphql"
+		}
+	}
+
+	webURL = strings.TrimSuffix(webURL, "/")
+	if webURL == "" {
+		webURL = deriveWebURL(apiURL, enterpriseServer)
+	}
+
+	return URLs{
+		API:     apiURL,
+		GraphQL: graphqlURL,
+		Web:     webURL,
+	}
+}
+
+func deriveWebURL(apiURL string, enterpriseServer bool) string {
+	if enterpriseServer {
+		return strings.TrimSuffix(apiURL, "/api/v3")
+	}
+
+	parsed, err := url.Parse(apiURL)
+	if err != nil {
+		return apiURL
+	}
+
+	parsed.Host = strings.TrimPrefix(parsed.Host, "api.")
+	parsed.Path = ""
+
+	return parsed.String()
+}
+
+// WebURL returns the base web URL of the Github instance hosting the
+// repository, so links in messages point to where the event came from.
+func (r *Repository) WebURL() string {
+	if r == nil {
+		return defaultWebURL
+	}
+
+	parsed, err := url.Parse(r.URL)
+	if err != nil || parsed.Host == "" {
+		return defaultWebURL
+	}
+
+	return parsed.Scheme + "://" + parsed.Host
+}
@@ -0,0 +1,97 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewURLs(t *testing.T) {
+	tests := []struct {
+		name    string
+		api     string
+		graphql string
+		web     string
+		want    URLs
+	}{
+		{
+			name: "defaults to github.com",
+			want: URLs{API: "https://api.github.com", GraphQL: "https://api.github.com/graphql", Web: "https://github.com"},
+		},
+		{
+			name: "GHE.com data residency",
+			api:  "https://api.octocorp.ghe.com/",
+			want: URLs{API: "https://api.octocorp.ghe.com", GraphQL: "https://api.octocorp.ghe.com/graphql", Web: "https://octocorp.ghe.com"},
+		},
+		{
+			name: "Github Enterprise Server",
+			api:  "https://github.example.com/api/v3",
+			want: URLs{API: "https://github.example.com/api/v3", GraphQL: "https://github.example.com/api/graphql", Web: "https://github.example.com"},
+		},
+		{
+			name:    "everything set explicitly",
+			api:     "http://127.0.0.1:8080",
+			graphql: "http://127.0.0.1:8080/gql",
+			web:     "http://127.0.0.1:8081",
+			want:    URLs{API: "http://127.0.0.1:8080", GraphQL: "http://127.0.0.1:8080/gql", Web: "http://127.0.0.1:8081"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewURLs(tt.api, tt.graphql, tt.web)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("NewURLs mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClientUsesConfiguredURLs(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v3/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "installation-token", "expires_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("GET /api/v3/orgs/acme/dependabot/alerts", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer installation-token" {
+			t.Errorf("unexpected Authorization header: %q", got)
+		}
+		fmt.Fprint(w, `[{"security_advisory": {"summary": "Prototype pollution", "severity": "high"}, "repository": {"name": "app"}}]`)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := New(slog.Default(), nil, NewURLs(server.URL+"/api/v3", "", ""), "42", "1", string(privateKey), "acme")
+
+	alerts, err := fetchOrgDependabotAlerts(context.Background(), client.httpClient, client.urls.API, "acme")
+	if err != nil {
+		t.Fatalf("fetching alerts: %v", err)
+	}
+
+	want := []orgDependabotAlert{{repo: Repository{Name: "app"}, advisorySummary: "Prototype pollution", severity: "high"}}
+	if diff := cmp.Diff(want, alerts, cmp.AllowUnexported(orgDependabotAlert{})); diff != "" {
+		t.Errorf("alerts mismatch (-want +got):\n%s", diff)
+	}
+
+	if got, want := client.TeamURL("platform"), server.URL+"/orgs/acme/teams/platform"; got != want {
+		t.Errorf("TeamURL() = %q, want %q", got, want)
+	}
+}
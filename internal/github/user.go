@@ -7,14 +7,12 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
 const (
-	graphqlEndpoint = "https://api.github.com/graphql"
-	allUserGraphQL  = `query FetchUsersWithEmail($org: String!, $cursor: String) {
+	allUserGraphQL = `query FetchUsersWithEmail($org: String!, $cursor: String) {
 	 organization(login: $org) {
 	   samlIdentityProvider {
 		 externalIdentities(first: 100, after: $cursor) {
@@ -65,11 +63,6 @@ type githubResponse struct {
 }
 
 func (c *Client) FetchOrgUsersWithEmail(ctx context.Context) error {
-	bearerToken, err := c.createBearerToken()
-	if err != nil {
-		return fmt.Errorf("creating bearer token: %v", err)
-	}
-
 	query := map[string]any{
 		"query": allUserGraphQL,
 		"variables": map[string]string{
@@ -78,25 +71,20 @@ func (c *Client) FetchOrgUsersWithEmail(ctx context.Context) error {
 		},
 	}
 
-	httpClient := http.Client{
-		Timeout: 10 * time.Second,
-	}
-
 	for {
 		body, err := json.Marshal(query)
 		if err != nil {
 			return fmt.Errorf("marshalling query: %v", err)
 		}
 
-		req, err := http.NewRequest(http.MethodPost, graphqlEndpoint, bytes.NewBuffer(body))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.urls.GraphQL, bytes.NewBuffer(body))
 		if err != nil {
 			return err
 		}
 
-		req.Header.Add("Authorization", fmt.Sprintf("Bearer %v", bearerToken))
 		req.Header.Add("Content-Type", "application/json")
 
-		httpResp, err := httpClient.Do(req)
+		httpResp, err := c.httpClient.Do(req)
 		if err != nil {
 			return fmt.Errorf("doing request: %v", err)
 		}
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Githubber is the part of the Github clients used when handling events. The
+// org is the team's organization, and empty for the primary organization.
+type Githubber interface {
+	FindSecretLocation(ctx context.Context, org, repository string, alertNumber int) (SecretLocation, bool, error)
+	UpdateFailedJob(ctx context.Context, org string, workflow *Workflow) error
+}
+
+// UpdateFailedJob finds and update the failed job in a workflow
+func (c Client) UpdateFailedJob(ctx context.Context, w *Workflow) error {
+	type Workflow struct {
+		Jobs []struct {
+			Name       string `json:"name"`
+			URL        string `json:"html_url"`
+			Conclusion string `json:"conclusion"`
+			Steps      []struct {
+				Name        string `json:"name"`
+				Conclusions string `json:"conclusion"`
+			} `json:"steps"`
+		} `json:"jobs"`
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.JobsURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("getting jobs: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("getting jobs: %s", resp.Status)
+	}
+
+	var jobs Workflow
+	if err := json.NewDecoder(resp.Body).Decode(&jobs); err != nil {
+		return fmt.Errorf("decoding jobs: %w", err)
+	}
+
+	for _, job := range jobs.Jobs {
+		if job.Conclusion == "failure" {
+			for _, step := range job.Steps {
+				if step.Conclusions == "failure" {
+					w.FailedJob = FailedJob{
+						Name: job.Name,
+						URL:  job.URL,
+						Step: step.Name,
+					}
+
+					return nil
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/navikt/ghep/internal/github"
+)
+
+type Github struct {
+	// SecretLocation is returned for every secret scanning alert, if set.
+	SecretLocation *github.SecretLocation
+}
+
+func (g *Github) FindSecretLocation(_ context.Context, _, _ string, _ int) (github.SecretLocation, bool, error) {
+	if g.SecretLocation == nil {
+		return github.SecretLocation{}, false, nil
+	}
+
+	return *g.SecretLocation, true, nil
+}
+
+func (g *Github) UpdateFailedJob(_ context.Context, _ string, _ *github.Workflow) error {
+	return nil
+}
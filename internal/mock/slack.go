@@ -3,6 +3,7 @@ package mock
 import (
 	"encoding/json"
 	"log/slog"
+	"slices"
 	"testing"
 
 	"github.com/navikt/ghep/internal/github"
@@ -13,6 +14,13 @@ type Slack struct {
 	Messages        int
 	Reactions       int
 	UpdatedMessages int
+	// PrivateChannels are the channels IsPrivateChannel returns true for.
+	PrivateChannels []string
+}
+
+// Notifiers returns the mock as both the default Slack workspace and Microsoft Teams.
+func (s *Slack) Notifiers() slack.Notifiers {
+	return slack.NewNotifiers(map[string]slack.Slacker{"": s}, nil, s)
 }
 
 func (s *Slack) Ensure(t *testing.T, eventType github.EventType, messages, reactions, updatedMessages int) {
@@ -67,6 +75,12 @@ func (s *Slack) PostMessage(payload []byte) (slack.MessageResponse, error) {
 	return slack.MessageResponse{Channel: message.Channel}, nil
 }
 
+// Notify counts messages posted to Microsoft Teams as messages.
+func (s *Slack) Notify(team, key string, message *slack.Message) error {
+	s.Messages += 1
+	return nil
+}
+
 func (s *Slack) PostReaction(channel string, timestamp string, reaction string) error {
 	s.Reactions += 1
 
@@ -86,3 +100,11 @@ func (s *Slack) PostWorkflowReaction(log *slog.Logger, event github.Event, chann
 func (s *Slack) PostPullRequestReaction(log *slog.Logger, reviewState, channel string, timestamp string) error {
 	return s.PostReaction(channel, timestamp, "workflow-reaction")
 }
+
+func (s *Slack) IsPrivateChannel(channel string) (bool, error) {
+	return slices.Contains(s.PrivateChannels, channel), nil
+}
+
+func (s *Slack) OpenDM(userID string) (string, error) {
+	return "D" + userID, nil
+}
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"errors"
 	"slices"
 	"strings"
 
@@ -10,32 +11,152 @@ import (
 )
 
 type Database struct {
+	// DependencyPullRequests are the pull requests collected for dependency roll-ups.
+	DependencyPullRequests []gensql.DependencyPullRequest
+	// FailingEmails are the emails GetUserByEmail fails to look up.
+	FailingEmails []string
 	Members       []string
-	SlackMessages []gensql.CreateSlackMessageParams
+	Mutes         []gensql.Mute
+	// OrgSecurityAlerts are the alerts stored for the organization's security digests.
+	OrgSecurityAlerts []gensql.UpsertOrgSecurityAlertParams
+	// PersonalNotifications are the preferences set from Slack, keyed by login.
+	PersonalNotifications map[string]gensql.PersonalNotification
+	// PersonalNotificationsSent are the claimed DMs, as delivery ID and login.
+	PersonalNotificationsSent []gensql.ClaimPersonalNotificationParams
+	QueuedEvents              []gensql.QueueEventParams
+	// Repositories are the stored repositories, where the ID is the index plus one.
+	Repositories []gensql.CreateRepositoryParams
+	// SecretAuthorNotifications are the claimed secret author DMs, as alert URL and team.
+	SecretAuthorNotifications []gensql.ClaimSecretAuthorNotificationParams
+	SecurityAlerts            []gensql.CreateSecurityAlertParams
+	SlackIDs                  []gensql.CreateSlackIDParams
+	SlackMessages             []gensql.CreateSlackMessageParams
+	TeamEvents                []gensql.CreateTeamEventParams
+	// TeamMembers and TeamRepositories are keyed by team slug.
+	TeamMembers      map[string][]string
+	TeamRepositories map[string][]gensql.CreateRepositoryParams
+	Users            []string
 }
 
-func (m *Database) AddTeamMember(ctx context.Context, params gensql.AddTeamMemberParams) error {
-	panic("unimplemented AddTeamMember")
+func (m *Database) AddTeamMember(_ context.Context, params gensql.AddTeamMemberParams) error {
+	if m.TeamMembers == nil {
+		m.TeamMembers = map[string][]string{}
+	}
+	m.TeamMembers[params.TeamSlug] = append(m.TeamMembers[params.TeamSlug], params.UserLogin)
+	return nil
 }
 
-func (m *Database) AddTeamRepository(ctx context.Context, params gensql.AddTeamRepositoryParams) error {
-	panic("unimplemented AddTeamRepository")
+func (m *Database) AddTeamRepository(_ context.Context, params gensql.AddTeamRepositoryParams) error {
+	if m.TeamRepositories == nil {
+		m.TeamRepositories = map[string][]gensql.CreateRepositoryParams{}
+	}
+	m.TeamRepositories[params.TeamSlug] = append(m.TeamRepositories[params.TeamSlug], m.Repositories[params.RepositoryID-1])
+	return nil
+}
+
+func (m *Database) ClaimPersonalNotification(_ context.Context, arg gensql.ClaimPersonalNotificationParams) (int64, error) {
+	if slices.Contains(m.PersonalNotificationsSent, arg) {
+		return 0, nil
+	}
+
+	m.PersonalNotificationsSent = append(m.PersonalNotificationsSent, arg)
+	return 1, nil
 }
 
-func (m *Database) CreateRepository(ctx context.Context, name string) (int32, error) {
-	panic("unimplemented CreateRepository")
+func (m *Database) ClaimSecretAuthorNotification(_ context.Context, arg gensql.ClaimSecretAuthorNotificationParams) (bool, error) {
+	if slices.Contains(m.SecretAuthorNotifications, arg) {
+		return false, pgx.ErrNoRows
+	}
+
+	first := !slices.ContainsFunc(m.SecretAuthorNotifications, func(n gensql.ClaimSecretAuthorNotificationParams) bool {
+		return n.AlertUrl == arg.AlertUrl
+	})
+	m.SecretAuthorNotifications = append(m.SecretAuthorNotifications, arg)
+	return first, nil
 }
 
-func (m *Database) CreateUser(ctx context.Context, login string) error {
-	panic("unimplemented CreateUser")
+func (m *Database) CreateRepository(_ context.Context, arg gensql.CreateRepositoryParams) (int32, error) {
+	m.Repositories = append(m.Repositories, arg)
+	return int32(len(m.Repositories)), nil // #nosec G115 - few repositories in tests
 }
 
-func (m *Database) ExistsUser(ctx context.Context, login string) (bool, error) {
-	panic("unimplemented ExistsUser")
+func (m *Database) CreateSecurityAlert(_ context.Context, arg gensql.CreateSecurityAlertParams) error {
+	if !slices.ContainsFunc(m.SecurityAlerts, func(a gensql.CreateSecurityAlertParams) bool {
+		return a.TeamSlug == arg.TeamSlug && a.AlertUrl == arg.AlertUrl
+	}) {
+		m.SecurityAlerts = append(m.SecurityAlerts, arg)
+	}
+	return nil
 }
 
-func (m *Database) GetRepository(ctx context.Context, name string) (gensql.Repository, error) {
-	panic("unimplemented GetRepository")
+func (m *Database) DeleteOrgSecurityAlert(_ context.Context, arg gensql.DeleteOrgSecurityAlertParams) error {
+	m.OrgSecurityAlerts = slices.DeleteFunc(m.OrgSecurityAlerts, func(a gensql.UpsertOrgSecurityAlertParams) bool {
+		return a.Org == arg.Org && a.AlertUrl == arg.AlertUrl
+	})
+	return nil
+}
+
+func (m *Database) DeleteSecurityAlert(_ context.Context, arg gensql.DeleteSecurityAlertParams) error {
+	m.SecurityAlerts = slices.DeleteFunc(m.SecurityAlerts, func(a gensql.CreateSecurityAlertParams) bool {
+		return a.TeamSlug == arg.TeamSlug && a.AlertUrl == arg.AlertUrl
+	})
+	return nil
+}
+
+func (m *Database) CreateUser(_ context.Context, login string) error {
+	m.Users = append(m.Users, login)
+	return nil
+}
+
+func (m *Database) ListRolledUpDependencyPullRequests(_ context.Context, arg gensql.ListRolledUpDependencyPullRequestsParams) ([]gensql.DependencyPullRequest, error) {
+	var prs []gensql.DependencyPullRequest
+	for _, pr := range m.DependencyPullRequests {
+		if pr.TeamSlug == arg.TeamSlug && pr.RolledUpAt.Valid && pr.RolledUpAt.Time.Equal(arg.RolledUpAt.Time) {
+			prs = append(prs, pr)
+		}
+	}
+
+	return prs, nil
+}
+
+func (m *Database) MarkDependencyPullRequestCIFailed(_ context.Context, arg gensql.MarkDependencyPullRequestCIFailedParams) (gensql.DependencyPullRequest, error) {
+	for i, pr := range m.DependencyPullRequests {
+		if pr.TeamSlug == arg.TeamSlug && pr.Repository == arg.Repository && pr.Number == arg.Number && !pr.CiFailed {
+			m.DependencyPullRequests[i].CiFailed = true
+			return m.DependencyPullRequests[i], nil
+		}
+	}
+
+	return gensql.DependencyPullRequest{}, pgx.ErrNoRows
+}
+
+func (m *Database) ExistsUser(_ context.Context, login string) (bool, error) {
+	return slices.Contains(m.Users, login), nil
+}
+
+func (m *Database) GetPersonalNotifications(_ context.Context, login string) (gensql.PersonalNotification, error) {
+	if preferences, ok := m.PersonalNotifications[login]; ok {
+		return preferences, nil
+	}
+
+	return gensql.PersonalNotification{}, pgx.ErrNoRows
+}
+
+func (m *Database) GetRepository(_ context.Context, arg gensql.GetRepositoryParams) (gensql.Repository, error) {
+	i := slices.Index(m.Repositories, gensql.CreateRepositoryParams(arg))
+	if i == -1 {
+		return gensql.Repository{}, pgx.ErrNoRows
+	}
+
+	return gensql.Repository{ID: int32(i + 1), Name: arg.Name, Org: arg.Org}, nil // #nosec G115 - few repositories in tests
+}
+
+func (m *Database) CreateSlackID(_ context.Context, arg gensql.CreateSlackIDParams) error {
+	m.SlackIDs = slices.DeleteFunc(m.SlackIDs, func(id gensql.CreateSlackIDParams) bool {
+		return id.Workspace == arg.Workspace && id.Login == arg.Login
+	})
+	m.SlackIDs = append(m.SlackIDs, arg)
+	return nil
 }
 
 func (m *Database) CreateSlackMessage(ctx context.Context, arg gensql.CreateSlackMessageParams) error {
@@ -50,6 +171,18 @@ func (m *Database) CreateSlackMessage(ctx context.Context, arg gensql.CreateSlac
 	return nil
 }
 
+func (m *Database) CreateTeamEvent(ctx context.Context, arg gensql.CreateTeamEventParams) error {
+	m.TeamEvents = append(m.TeamEvents, arg)
+	return nil
+}
+
+func (m *Database) DeleteSlackID(_ context.Context, arg gensql.DeleteSlackIDParams) error {
+	m.SlackIDs = slices.DeleteFunc(m.SlackIDs, func(id gensql.CreateSlackIDParams) bool {
+		return id.Workspace == arg.Workspace && id.Login == arg.Login
+	})
+	return nil
+}
+
 func (m *Database) GetSlackMessage(ctx context.Context, arg gensql.GetSlackMessageParams) (gensql.GetSlackMessageRow, error) {
 	for _, m := range m.SlackMessages {
 		if arg.EventID == m.EventID {
@@ -64,11 +197,59 @@ func (m *Database) GetSlackMessage(ctx context.Context, arg gensql.GetSlackMessa
 	return gensql.GetSlackMessageRow{}, pgx.ErrNoRows
 }
 
+func (m *Database) ListTeamMembers(_ context.Context, teamSlug string) ([]string, error) {
+	return m.TeamMembers[teamSlug], nil
+}
+
+func (m *Database) ListTeamRepositories(_ context.Context, teamSlug string) ([]gensql.Repository, error) {
+	var repositories []gensql.Repository
+	for _, stored := range m.TeamRepositories[teamSlug] {
+		repository, err := m.GetRepository(context.Background(), gensql.GetRepositoryParams(stored))
+		if err != nil {
+			return nil, err
+		}
+		repositories = append(repositories, repository)
+	}
+
+	return repositories, nil
+}
+
+func (m *Database) ListSlackIDs(_ context.Context, workspace string) ([]gensql.ListSlackIDsRow, error) {
+	var rows []gensql.ListSlackIDsRow
+	for _, id := range m.SlackIDs {
+		if id.Workspace == workspace {
+			rows = append(rows, gensql.ListSlackIDsRow{Login: id.Login, ID: id.ID})
+		}
+	}
+	return rows, nil
+}
+
+func (m *Database) IncrementMuteSuppressed(_ context.Context, id int64) error {
+	for i := range m.Mutes {
+		if m.Mutes[i].ID == id {
+			m.Mutes[i].Suppressed++
+		}
+	}
+
+	return nil
+}
+
+func (m *Database) ListActiveMutes(_ context.Context, teamSlug string) ([]gensql.Mute, error) {
+	var mutes []gensql.Mute
+	for _, mute := range m.Mutes {
+		if mute.TeamSlug == teamSlug {
+			mutes = append(mutes, mute)
+		}
+	}
+
+	return mutes, nil
+}
+
 func (m *Database) ListSlackMessagesByEvent(ctx context.Context, arg gensql.ListSlackMessagesByEventParams) ([]gensql.ListSlackMessagesByEventRow, error) {
 	rows := []gensql.ListSlackMessagesByEventRow{}
 
 	for _, m := range m.SlackMessages {
-		if arg.EventID == m.EventID {
+		if arg.TeamSlug == m.TeamSlug && arg.EventID == m.EventID {
 			rows = append(rows, gensql.ListSlackMessagesByEventRow{
 				ThreadTs: m.ThreadTs,
 				Channel:  m.Channel,
@@ -84,23 +265,85 @@ func (m *Database) ListSlackMessagesByEvent(ctx context.Context, arg gensql.List
 	return rows, nil
 }
 
-func (m *Database) RemoveTeamMember(ctx context.Context, arg gensql.RemoveTeamMemberParams) error {
-	panic("unimplemented RemoveTeamMember")
+func (m *Database) QueueEvent(_ context.Context, arg gensql.QueueEventParams) error {
+	m.QueuedEvents = append(m.QueuedEvents, arg)
+	return nil
 }
 
-func (m *Database) RemoveTeamRepository(ctx context.Context, arg gensql.RemoveTeamRepositoryParams) error {
-	panic("unimplemented RemoveTeamRepository")
+func (m *Database) ReleasePersonalNotification(_ context.Context, arg gensql.ReleasePersonalNotificationParams) error {
+	m.PersonalNotificationsSent = slices.DeleteFunc(m.PersonalNotificationsSent, func(sent gensql.ClaimPersonalNotificationParams) bool {
+		return sent == gensql.ClaimPersonalNotificationParams(arg)
+	})
+	return nil
+}
+
+func (m *Database) ReleaseSecretAuthorNotification(_ context.Context, arg gensql.ReleaseSecretAuthorNotificationParams) error {
+	m.SecretAuthorNotifications = slices.DeleteFunc(m.SecretAuthorNotifications, func(n gensql.ClaimSecretAuthorNotificationParams) bool {
+		return n == gensql.ClaimSecretAuthorNotificationParams(arg)
+	})
+	return nil
+}
+
+func (m *Database) RemoveTeamMember(_ context.Context, arg gensql.RemoveTeamMemberParams) error {
+	m.TeamMembers[arg.TeamSlug] = slices.DeleteFunc(m.TeamMembers[arg.TeamSlug], func(login string) bool {
+		return login == arg.UserLogin
+	})
+	return nil
+}
+
+func (m *Database) RemoveTeamRepository(_ context.Context, arg gensql.RemoveTeamRepositoryParams) error {
+	m.TeamRepositories[arg.TeamSlug] = slices.DeleteFunc(m.TeamRepositories[arg.TeamSlug], func(repository gensql.CreateRepositoryParams) bool {
+		return repository.Org == arg.Org && repository.Name == arg.Name
+	})
+	return nil
 }
 
 func (m *Database) UpdateRepository(ctx context.Context, arg gensql.UpdateRepositoryParams) error {
 	panic("unimplemented UpdateRepository")
 }
 
+func (m *Database) UpsertDependencyPullRequest(_ context.Context, arg gensql.UpsertDependencyPullRequestParams) (gensql.DependencyPullRequest, error) {
+	for i, pr := range m.DependencyPullRequests {
+		if pr.TeamSlug == arg.TeamSlug && pr.Repository == arg.Repository && pr.Number == arg.Number {
+			m.DependencyPullRequests[i].Title = arg.Title
+			m.DependencyPullRequests[i].State = arg.State
+			if arg.ResetCi {
+				m.DependencyPullRequests[i].CiFailed = false
+			}
+			return m.DependencyPullRequests[i], nil
+		}
+	}
+
+	pr := gensql.DependencyPullRequest{
+		TeamSlug:   arg.TeamSlug,
+		Repository: arg.Repository,
+		Number:     arg.Number,
+		Title:      arg.Title,
+		Url:        arg.Url,
+		Ecosystem:  arg.Ecosystem,
+		State:      arg.State,
+	}
+	m.DependencyPullRequests = append(m.DependencyPullRequests, pr)
+	return pr, nil
+}
+
+func (m *Database) UpsertOrgSecurityAlert(_ context.Context, arg gensql.UpsertOrgSecurityAlertParams) error {
+	m.OrgSecurityAlerts = slices.DeleteFunc(m.OrgSecurityAlerts, func(a gensql.UpsertOrgSecurityAlertParams) bool {
+		return a.Org == arg.Org && a.AlertUrl == arg.AlertUrl
+	})
+	m.OrgSecurityAlerts = append(m.OrgSecurityAlerts, arg)
+	return nil
+}
+
 func (m *Database) UpsertUserCommitCount(ctx context.Context, arg gensql.UpsertUserCommitCountParams) error {
 	panic("unimplemented UpsertUserCommitCount")
 }
 
 func (m *Database) GetUserByEmail(_ context.Context, email string) (string, error) {
+	if slices.Contains(m.FailingEmails, email) {
+		return "", errors.New("connection reset by peer")
+	}
+
 	return map[string]string{
 		"andre.roaldseth@nav.no":         "androa",
 		"kyrre.havik@nav.no":             "Kyrremann",
@@ -110,10 +353,14 @@ func (m *Database) GetUserByEmail(_ context.Context, email string) (string, erro
 	}[strings.ToLower(email)], nil
 }
 
-func (m *Database) GetUserSlackID(_ context.Context, login string) (string, error) {
+func (m *Database) GetUserSlackID(_ context.Context, arg gensql.GetUserSlackIDParams) (string, error) {
+	if arg.Workspace != "" {
+		return "", pgx.ErrNoRows
+	}
+
 	return map[string]string{
 		"Kyrremann": "U8PL7CR4K",
-	}[login], nil
+	}[arg.Login], nil
 }
 
 func (m *Database) GetTeamMember(_ context.Context, params gensql.GetTeamMemberParams) (string, error) {
@@ -0,0 +1,16 @@
+package mock
+
+import (
+	"log/slog"
+
+	"github.com/navikt/ghep/internal/github"
+)
+
+type Webhook struct {
+	Sent int
+}
+
+func (w *Webhook) Send(_ *slog.Logger, _ github.Team, _ github.Source, _ github.Event) error {
+	w.Sent++
+	return nil
+}
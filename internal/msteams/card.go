@@ -0,0 +1,144 @@
+package msteams
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/navikt/ghep/internal/slack"
+)
+
+const (
+	contentTypeAdaptiveCard = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+)
+
+type Card struct {
+	Type    string    `json:"type"`
+	Schema  string    `json:"$schema"`
+	Version string    `json:"version"`
+	Body    []Element `json:"body"`
+	MSTeams struct {
+		Width string `json:"width"`
+	} `json:"msteams"`
+}
+
+// Element is either a TextBlock or a Container holding other elements.
+type Element struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	Wrap     bool      `json:"wrap,omitempty"`
+	Size     string    `json:"size,omitempty"`
+	IsSubtle bool      `json:"isSubtle,omitempty"`
+	Style    string    `json:"style,omitempty"`
+	Items    []Element `json:"items,omitempty"`
+}
+
+type attachment struct {
+	ContentType string `json:"contentType"`
+	Content     Card   `json:"content"`
+}
+
+// webhookPayload is the format accepted by both incoming webhooks and workflow URLs.
+type webhookPayload struct {
+	Type        string       `json:"type"`
+	Attachments []attachment `json:"attachments"`
+}
+
+func newWebhookPayload(card Card) webhookPayload {
+	return webhookPayload{
+		Type: "message",
+		Attachments: []attachment{
+			{ContentType: contentTypeAdaptiveCard, Content: card},
+		},
+	}
+}
+
+// CreateCard converts a Slack message to an Adaptive Card, with the text on
+// top and each attachment in a container styled after its colour.
+func CreateCard(message slack.Message) Card {
+	card := Card{
+		Type:    "AdaptiveCard",
+		Schema:  adaptiveCardSchema,
+		Version: adaptiveCardVersion,
+	}
+	card.MSTeams.Width = "Full"
+
+	if message.Text != "" {
+		card.Body = append(card.Body, textBlock(message.Text))
+	}
+
+	for _, a := range message.Attachments {
+		container := Element{
+			Type:  "Container",
+			Style: styleByColor(a.Color),
+		}
+
+		if a.Text != "" {
+			container.Items = append(container.Items, textBlock(a.Text))
+		}
+
+		if a.Footer != "" {
+			footer := textBlock(a.Footer)
+			footer.Size = "Small"
+			footer.IsSubtle = true
+			container.Items = append(container.Items, footer)
+		}
+
+		if len(container.Items) > 0 {
+			card.Body = append(card.Body, container)
+		}
+	}
+
+	return card
+}
+
+func textBlock(text string) Element {
+	return Element{
+		Type: "TextBlock",
+		Text: ToMarkdown(text),
+		Wrap: true,
+	}
+}
+
+func styleByColor(color string) string {
+	switch color {
+	case slack.ColorCritical, slack.ColorFailed:
+		return "attention"
+	case slack.ColorHigh, slack.ColorMedium:
+		return "warning"
+	case slack.ColorOpened:
+		return "good"
+	case slack.ColorMerged:
+		return "accent"
+	default:
+		return "emphasis"
+	}
+}
+
+var (
+	slackLinkRegex    = regexp.MustCompile(`<([^<>|@!#]+)\|([^<>]+)>`)
+	slackURLRegex     = regexp.MustCompile(`<((?:https?|mailto):[^<>|]+)>`)
+	slackMentionRegex = regexp.MustCompile(`<[@#!]([^<>|]+)(?:\|([^<>]+))?>`)
+	slackBoldRegex    = regexp.MustCompile(`(^|[\s(>])\*([^*\n]+)\*`)
+)
+
+// ToMarkdown converts Slack mrkdwn to the markdown subset supported by Adaptive Cards.
+func ToMarkdown(text string) string {
+	text = slackLinkRegex.ReplaceAllString(text, "[$2]($1)")
+	text = slackURLRegex.ReplaceAllString(text, "[$1]($1)")
+	text = slackMentionRegex.ReplaceAllStringFunc(text, func(mention string) string {
+		parts := slackMentionRegex.FindStringSubmatch(mention)
+		if parts[2] != "" {
+			return "@" + parts[2]
+		}
+		return "@" + parts[1]
+	})
+	text = slackBoldRegex.ReplaceAllString(text, "$1**$2**")
+
+	// Adaptive Cards need a blank line for line breaks to be shown.
+	text = strings.ReplaceAll(text, "\n", "\n\n")
+
+	return html.UnescapeString(text)
+}
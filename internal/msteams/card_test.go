@@ -0,0 +1,88 @@
+package msteams
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/navikt/ghep/internal/slack"
+)
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "links with labels",
+			text: "Pull request <https://github.com/navikt/ghep/pull/1|#1> opened",
+			want: "Pull request [#1](https://github.com/navikt/ghep/pull/1) opened",
+		},
+		{
+			name: "links without labels",
+			text: "See <https://github.com/navikt/ghep>",
+			want: "See [https://github.com/navikt/ghep](https://github.com/navikt/ghep)",
+		},
+		{
+			name: "mentions",
+			text: "<@U123> and <!here>",
+			want: "@U123 and @here",
+		},
+		{
+			name: "bold and escaped characters",
+			text: "*Branch* main &gt; dev &amp; test",
+			want: "**Branch** main > dev & test",
+		},
+		{
+			name: "line breaks",
+			text: "first\nsecond",
+			want: "first\n\nsecond",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, ToMarkdown(tt.text)); diff != "" {
+				t.Errorf("ToMarkdown mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreateCard(t *testing.T) {
+	message := slack.Message{
+		Channel: "team-alerts",
+		Text:    "New alert in <https://github.com/navikt/ghep|ghep>",
+		Attachments: []slack.Attachment{
+			{
+				Text:   "*Critical* vulnerability",
+				Color:  slack.ColorCritical,
+				Footer: "Dependabot",
+			},
+			{
+				Color: slack.ColorDefault,
+			},
+		},
+	}
+
+	want := []Element{
+		{Type: "TextBlock", Text: "New alert in [ghep](https://github.com/navikt/ghep)", Wrap: true},
+		{
+			Type:  "Container",
+			Style: "attention",
+			Items: []Element{
+				{Type: "TextBlock", Text: "**Critical** vulnerability", Wrap: true},
+				{Type: "TextBlock", Text: "Dependabot", Wrap: true, Size: "Small", IsSubtle: true},
+			},
+		},
+	}
+
+	card := CreateCard(message)
+	if card.Type != "AdaptiveCard" || card.Version != adaptiveCardVersion {
+		t.Errorf("unexpected card type %q and version %q", card.Type, card.Version)
+	}
+
+	if diff := cmp.Diff(want, card.Body); diff != "" {
+		t.Errorf("CreateCard mismatch (-want +got):\n%s", diff)
+	}
+}
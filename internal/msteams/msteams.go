@@ -0,0 +1,137 @@
+// Package msteams posts messages to Microsoft Teams as Adaptive Cards,
+// through incoming webhooks or workflow URLs.
+package msteams
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+)
+
+// Client implements slack.Notifier for Microsoft Teams, posting each team's
+// sources and digests to their own webhook.
+type Client struct {
+	log        *slog.Logger
+	httpClient *http.Client
+	// webhooks maps the team and key to its webhook URL.
+	webhooks map[webhookKey]string
+}
+
+type webhookKey struct {
+	team string
+	key  string
+}
+
+// New reads the webhook URL for every source and digest configured with the
+// msteams notifier, from MSTEAMS_WEBHOOK_<TEAM>_<KEY> or the file in
+// MSTEAMS_WEBHOOK_<TEAM>_<KEY>_FILE.
+func New(log *slog.Logger, teams map[string]github.Team) (*Client, error) {
+	client := &Client{
+		log: log,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		webhooks: map[webhookKey]string{},
+	}
+
+	for name, team := range teams {
+		for _, key := range team.MSTeamsKeys() {
+			env := WebhookEnv(name, key)
+			url, err := webhookFromEnv(env)
+			if err != nil {
+				return nil, fmt.Errorf("team %s: reading webhook for %s: %w", name, key, err)
+			}
+			if url == "" {
+				return nil, fmt.Errorf("team %s: missing Microsoft Teams webhook for %s, set %s", name, key, env)
+			}
+
+			client.webhooks[webhookKey{team: name, key: key}] = url
+		}
+	}
+
+	return client, nil
+}
+
+// WebhookEnv returns the environment variable holding the webhook URL for a
+// team's source type or digest.
+func WebhookEnv(team, key string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, team+"_"+key)
+
+	return "MSTEAMS_WEBHOOK_" + strings.ToUpper(name)
+}
+
+func webhookFromEnv(key string) (string, error) {
+	if url := os.Getenv(key); url != "" {
+		return url, nil
+	}
+
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+
+	url, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(url)), nil
+}
+
+// Notify posts the message as an Adaptive Card to the webhook of the team's
+// source type or digest.
+func (c *Client) Notify(team, key string, message *slack.Message) error {
+	url, ok := c.webhooks[webhookKey{team: team, key: key}]
+	if !ok {
+		return fmt.Errorf("no Microsoft Teams webhook for %s in team %s", key, team)
+	}
+
+	body, err := json.Marshal(newWebhookPayload(CreateCard(*message)))
+	if err != nil {
+		return fmt.Errorf("encoding card: %w", err)
+	}
+
+	if err := c.post(url, body); err != nil {
+		return fmt.Errorf("error posting message to Microsoft Teams: %v", err)
+	}
+
+	return nil
+}
+
+func (c *Client) post(url string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req) // #nosec G704 -- URL is a webhook configured by the operator
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("non 2xx status code(%v): %s", resp.StatusCode, respBody)
+	}
+
+	return nil
+}
+
+var _ slack.Notifier = (*Client)(nil)
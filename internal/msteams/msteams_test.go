@@ -0,0 +1,99 @@
+package msteams
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/slack"
+)
+
+func TestNewRequiresWebhooks(t *testing.T) {
+	teams := map[string]github.Team{
+		"nada": {
+			Name:    "nada",
+			Sources: []github.Source{{SourceType: "commits", Channel: "#nada-commits", Notifier: github.NotifierMSTeams}},
+		},
+	}
+
+	if _, err := New(slog.New(slog.DiscardHandler), teams); err == nil {
+		t.Error("expected error for missing webhook")
+	}
+
+	t.Setenv("MSTEAMS_WEBHOOK_NADA_COMMITS", "https://example.com/webhook")
+	client, err := New(slog.New(slog.DiscardHandler), teams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := client.webhooks[webhookKey{team: "nada", key: "commits"}]; got != "https://example.com/webhook" {
+		t.Errorf("expected webhook for the commits of nada, got %q", got)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	var received webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	t.Setenv("MSTEAMS_WEBHOOK_NADA_COMMITS", server.URL)
+	client, err := New(slog.New(slog.DiscardHandler), map[string]github.Team{
+		"nada": {
+			Name:    "nada",
+			Sources: []github.Source{{SourceType: "commits", Channel: "nada-commits", Notifier: github.NotifierMSTeams}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Notify("nada", "commits", &slack.Message{Channel: "nada-commits", Text: "*Hello*"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(received.Attachments) != 1 || received.Attachments[0].ContentType != contentTypeAdaptiveCard {
+		t.Fatalf("unexpected payload %+v", received)
+	}
+
+	if got := received.Attachments[0].Content.Body[0].Text; got != "**Hello**" {
+		t.Errorf("expected bold text, got %q", got)
+	}
+
+	// Webhooks are keyed by team and source, not by channel
+	if err := client.Notify("nada", "pulls", &slack.Message{Channel: "nada-commits"}); err == nil {
+		t.Error("expected error for source without webhook")
+	}
+}
+
+func TestWebhookEnv(t *testing.T) {
+	tests := []struct {
+		team string
+		key  string
+		want string
+	}{
+		{team: "nada", key: "commits", want: "MSTEAMS_WEBHOOK_NADA_COMMITS"},
+		{team: "nada", key: github.NotifyKeyPullRequestDigest, want: "MSTEAMS_WEBHOOK_NADA_PULL_REQUEST_DIGEST"},
+		{team: "other-org/nada", key: "security", want: "MSTEAMS_WEBHOOK_OTHER_ORG_NADA_SECURITY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := WebhookEnv(tt.team, tt.key); got != tt.want {
+				t.Errorf("WebhookEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxSectionText is the maximum length of the text in a section block.
+	maxSectionText = 3000
+
+	// maxBlocks is the maximum number of blocks in a message.
+	maxBlocks = 50
+
+	// footerBlockID prefixes the block ID of the context blocks holding the
+	// footers of attachments, as block IDs are unique within a message.
+	footerBlockID = "footer"
+)
+
+// Block is a Block Kit layout block. Only the header, section, divider and
+// context blocks are used.
+type Block struct {
+	Type      string     `json:"type"`
+	BlockID   string     `json:"block_id,omitempty"`
+	Text      *Text      `json:"text,omitempty"`
+	Elements  []Element  `json:"elements,omitempty"`
+	Accessory *Accessory `json:"accessory,omitempty"`
+}
+
+type Text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Element is an element in a context block, either mrkdwn text or an image.
+type Element struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	AltText  string `json:"alt_text,omitempty"`
+}
+
+// Accessory is an interactive element next to the text of a section block,
+// either a button or a select menu.
+type Accessory struct {
+	Type        string   `json:"type"`
+	ActionID    string   `json:"action_id"`
+	Text        *Text    `json:"text,omitempty"`
+	Placeholder *Text    `json:"placeholder,omitempty"`
+	Value       string   `json:"value,omitempty"`
+	Style       string   `json:"style,omitempty"`
+	Options     []Option `json:"options,omitempty"`
+}
+
+type Option struct {
+	Text  Text   `json:"text"`
+	Value string `json:"value"`
+}
+
+func plainText(text string) *Text {
+	return &Text{Type: "plain_text", Text: text}
+}
+
+func headerBlock(text string) Block {
+	return Block{
+		Type: "header",
+		Text: plainText(text),
+	}
+}
+
+func sectionBlock(text string) Block {
+	if utf8.RuneCountInString(text) > maxSectionText {
+		runes := []rune(text)
+		text = string(runes[:maxSectionText-1]) + "…"
+	}
+
+	return Block{
+		Type: "section",
+		Text: &Text{Type: "mrkdwn", Text: text},
+	}
+}
+
+// sectionBlocks returns the text as sections, split between lines so long
+// lists, like the commits of a push, are not cut at the length of one section.
+func sectionBlocks(text string) []Block {
+	var blocks []Block
+	var section strings.Builder
+	for line := range strings.Lines(text) {
+		if section.Len() > 0 && utf8.RuneCountInString(section.String())+utf8.RuneCountInString(line) > maxSectionText {
+			blocks = append(blocks, sectionBlock(strings.TrimRight(section.String(), "\n")))
+			section.Reset()
+		}
+		section.WriteString(line)
+	}
+
+	if text := strings.TrimRight(section.String(), "\n"); text != "" {
+		blocks = append(blocks, sectionBlock(text))
+	}
+
+	return blocks
+}
+
+// textBlocks returns the blocks of a message with only text when it is built
+// with Block Kit, and nil otherwise.
+func textBlocks(blockKit bool, text string) []Block {
+	if !blockKit {
+		return nil
+	}
+
+	return limitBlocks(sectionBlocks(text))
+}
+
+// digestBlocks returns the blocks of a digest summary with a header, when it
+// is built with Block Kit, and nil otherwise.
+func digestBlocks(blockKit bool, header, text string) []Block {
+	if !blockKit {
+		return nil
+	}
+
+	return limitBlocks(append([]Block{headerBlock(header)}, sectionBlocks(text)...))
+}
+
+func contextBlock(text string) Block {
+	return Block{
+		Type:     "context",
+		Elements: []Element{{Type: "mrkdwn", Text: text}},
+	}
+}
+
+// footerBlock returns a context block with the footer icon and text, where
+// index tells apart the footers of a message.
+func footerBlock(index int, icon, footer string) Block {
+	if icon == "" {
+		icon = neutralGithubIcon
+	}
+
+	return Block{
+		Type:    "context",
+		BlockID: fmt.Sprintf("%s-%d", footerBlockID, index),
+		Elements: []Element{
+			{Type: "image", ImageURL: icon, AltText: "Github"},
+			{Type: "mrkdwn", Text: footer},
+		},
+	}
+}
+
+// isFooterBlock returns true for the context blocks created by footerBlock.
+func isFooterBlock(block Block) bool {
+	return strings.HasPrefix(block.BlockID, footerBlockID+"-")
+}
+
+// limitBlocks cuts the blocks to the most Slack allows in a message, with a
+// note in place of the ones left out.
+func limitBlocks(blocks []Block) []Block {
+	if len(blocks) <= maxBlocks {
+		return blocks
+	}
+
+	left := len(blocks) - maxBlocks + 1
+	return append(blocks[:maxBlocks-1:maxBlocks-1], contextBlock(fmt.Sprintf("_%d more blocks did not fit in the message._", left)))
+}
+
+// ToBlockKit returns the message with the text and attachments rendered as
+// top-level blocks, and no attachments, for the messages not built with Block
+// Kit. Each attachment becomes a section with its text, followed by a context
+// block with its footer and the attachment's own blocks, separated from the
+// rest by a divider. The text is kept as the fallback used in notifications
+// and by screen readers. Blocks past the most Slack allows are left out.
+func ToBlockKit(message *Message) *Message {
+	if message == nil || len(message.Blocks) > 0 {
+		return message
+	}
+
+	converted := *message
+	converted.Attachments = nil
+	if message.Text != "" {
+		converted.Blocks = append(converted.Blocks, sectionBlock(message.Text))
+	}
+
+	for i, a := range message.Attachments {
+		if len(converted.Blocks) > 0 {
+			converted.Blocks = append(converted.Blocks, Block{Type: "divider"})
+		}
+
+		if a.Text != "" {
+			converted.Blocks = append(converted.Blocks, sectionBlock(a.Text))
+		}
+
+		if a.Footer != "" {
+			converted.Blocks = append(converted.Blocks, footerBlock(i, a.FooterIcon, a.Footer))
+		}
+
+		converted.Blocks = append(converted.Blocks, a.Blocks...)
+	}
+
+	converted.Blocks = limitBlocks(converted.Blocks)
+	return &converted
+}
@@ -19,6 +19,9 @@ func (c Client) EnsureChannels(teams map[string]github.Team) error {
 
 	for name, team := range teams {
 		for i := range team.Sources {
+			if team.Sources[i].Notifier == github.NotifierMSTeams {
+				continue
+			}
 			team.Sources[i].Channel = c.findChannelIDByName(team.Sources[i].Channel, joinedChannels)
 		}
 		team.Config.ExternalContributorsChannel = c.findChannelIDByName(team.Config.ExternalContributorsChannel, joinedChannels)
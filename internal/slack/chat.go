@@ -37,3 +37,21 @@ func (c Client) PostUpdatedMessage(message Message) error {
 
 	return nil
 }
+
+// PostEphemeral posts a message in the channel that only the user can see.
+func (c Client) PostEphemeral(channel, user, text string) error {
+	payload, err := json.Marshal(map[string]string{
+		"channel": channel,
+		"user":    user,
+		"text":    text,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshalling message: %v", err)
+	}
+
+	if _, err := c.postRequest("chat.postEphemeral", payload); err != nil {
+		return fmt.Errorf("error posting ephemeral message to Slack: %v", err)
+	}
+
+	return nil
+}
@@ -6,22 +6,53 @@ import (
 	"github.com/navikt/ghep/internal/github"
 )
 
-func CreateCodeScanningAlertMessage(channel, timestamp string, event github.Event) *Message {
+// CreateCodeScanningAlertMessage creates the message for a code scanning alert.
+// New alerts say where the alert was found, later actions are posted in their thread.
+func CreateCodeScanningAlertMessage(channel, timestamp string, blockKit bool, event github.Event) *Message {
+	text := fmt.Sprintf("A code scanning alert was just %s for the repository %s.\nRead more: %s", event.Action, event.Repository.ToSlack(), event.Alert.URL)
+
 	var attachments []Attachment
+	var rule []Block
 	if event.Action == "created" {
+		if instance := event.Alert.MostRecentInstance; instance != nil && instance.Location.Path != "" {
+			location := instance.Location
+			lineUnit := "lines"
+			if location.EndLine <= location.StartLine {
+				lineUnit = "line"
+			}
+
+			text = fmt.Sprintf("A code scanning alert was just created for the repository %s, in <%s/blob/%s/%s%s|%s %s %s>",
+				event.Repository.ToSlack(), event.Repository.URL, instance.CommitSHA, location.Path, location.Anchor(), location.Path, lineUnit, location.Lines())
+			if branch := instance.Branch(); branch != "" {
+				text += fmt.Sprintf(" on `%s`", branch)
+			}
+			text += fmt.Sprintf(".\nRead more: %s", event.Alert.URL)
+		}
+
 		color := getColorBySeverity(event.Alert.Rule.SeverityType())
+		ruleText := fmt.Sprintf("*%s*\n%s", event.Alert.Rule.Description, event.Alert.Rule.FullDescription)
 
 		attachments = []Attachment{
 			{
-				Text:  fmt.Sprintf("*%s*\n%s", event.Alert.Rule.Description, event.Alert.Rule.FullDescription),
+				Text:  ruleText,
 				Color: color,
 			},
 		}
+		rule = []Block{sectionBlock(ruleText)}
+	}
+
+	if blockKit {
+		return &Message{
+			Channel:         channel,
+			Text:            text,
+			Blocks:          append([]Block{sectionBlock(text)}, rule...),
+			ThreadTimestamp: timestamp,
+		}
 	}
 
 	return &Message{
 		Channel:         channel,
-		Text:            fmt.Sprintf("A code scanning alert was just %s for the repository %s.\nRead more: %s", event.Action, event.Repository.ToSlack(), event.Alert.URL),
+		Text:            text,
 		Attachments:     attachments,
 		ThreadTimestamp: timestamp,
 	}
@@ -73,9 +73,10 @@ func createAuthors(ctx context.Context, log *slog.Logger, db sql.Database, event
 		}
 	}
 
+	webURL := event.Repository.WebURL()
 	authorsAsString := make([]string, len(commitAuthors))
 	for i, author := range commitAuthors {
-		authorsAsString[i] = author.AsUser().ToSlack()
+		authorsAsString[i] = author.AsUser(webURL).ToSlack()
 	}
 
 	var senders string
@@ -89,7 +90,9 @@ func createAuthors(ctx context.Context, log *slog.Logger, db sql.Database, event
 	return senders, nil
 }
 
-func CreateCommitMessage(ctx context.Context, log *slog.Logger, db sql.Database, channel string, event github.Event) (*Message, error) {
+// CreateCommitMessage creates the message for a push, listing the commits. With
+// Block Kit, long lists are split over more than one section.
+func CreateCommitMessage(ctx context.Context, log *slog.Logger, db sql.Database, channel string, blockKit bool, event github.Event) (*Message, error) {
 	authors, err := createAuthors(ctx, log, db, event)
 	if err != nil {
 		return nil, fmt.Errorf("creating authors: %w", err)
@@ -104,6 +107,14 @@ func CreateCommitMessage(ctx context.Context, log *slog.Logger, db sql.Database,
 		fmt.Fprintf(&attachmentText, "`<%s|%s>` - %s\n", c.URL, c.ID[:8], firstLine)
 	}
 
+	if blockKit {
+		return &Message{
+			Channel: channel,
+			Text:    text,
+			Blocks:  limitBlocks(append([]Block{sectionBlock(text)}, sectionBlocks(attachmentText.String())...)),
+		}, nil
+	}
+
 	attachments := []Attachment{
 		{
 			Text:  attachmentText.String(),
@@ -125,16 +136,29 @@ func CreateUpdatedCommitMessage(payload []byte, event github.Event) (*Message, e
 		return nil, fmt.Errorf("unmarshalling message: %w", err)
 	}
 
+	footer := fmt.Sprintf("<%s|%s>", event.Workflow.URL, event.Workflow.Name)
+
+	// Messages rendered with Block Kit have the footer in a context block
+	if len(message.Blocks) > 0 {
+		if slices.ContainsFunc(message.Blocks, isFooterBlock) {
+			return nil, nil
+		}
+
+		message.Blocks = append(message.Blocks, footerBlock(0, neutralGithubIcon, footer))
+		return &message, nil
+	}
+
 	if len(message.Attachments) == 0 {
 		return nil, nil
 	}
 
-	if message.Attachments[0].Footer != "" {
+	attachment := &message.Attachments[0]
+	if attachment.Footer != "" {
 		return nil, nil
 	}
 
-	message.Attachments[0].FooterIcon = neutralGithubIcon
-	message.Attachments[0].Footer = fmt.Sprintf("<%s|%s>", event.Workflow.URL, event.Workflow.Name)
+	attachment.FooterIcon = neutralGithubIcon
+	attachment.Footer = footer
 
 	return &message, nil
 }
@@ -36,3 +36,43 @@ func (c Client) OpenDM(slackUserID string) (string, error) {
 
 	return resp.Channel.ID, nil
 }
+
+type conversationsInfoResponse struct {
+	Response
+	Channel struct {
+		IsPrivate bool `json:"is_private"`
+	} `json:"channel"`
+}
+
+// IsPrivateChannel returns true if the channel, by ID, is private.
+func (c Client) IsPrivateChannel(channel string) (bool, error) {
+	body, err := c.getRequest("conversations.info", channel, "")
+	if err != nil {
+		return false, fmt.Errorf("getting channel info: %w", err)
+	}
+
+	var resp conversationsInfoResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return false, fmt.Errorf("unmarshalling conversations.info response: %w", err)
+	}
+
+	return resp.Channel.IsPrivate, nil
+}
+
+// listPublicChannels returns the names and IDs of the workspace's public channels.
+func (c Client) listPublicChannels() (map[string]bool, error) {
+	channels, err := c.listChannels("conversations.list")
+	if err != nil {
+		return nil, fmt.Errorf("listing channels: %w", err)
+	}
+
+	public := make(map[string]bool)
+	for _, channel := range channels {
+		if !channel.IsPrivate {
+			public[channel.Name] = true
+			public[channel.ID] = true
+		}
+	}
+
+	return public, nil
+}
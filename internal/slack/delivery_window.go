@@ -0,0 +1,42 @@
+package slack
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// CreateQueuedEventsSummary summarizes the events held back outside the
+// delivery window, which are posted in its thread.
+func CreateQueuedEventsSummary(channel string, queued []gensql.QueuedEvent) *Message {
+	counts := map[string]int{}
+	for _, event := range queued {
+		counts[event.EventType]++
+	}
+
+	eventTypes := make([]string, 0, len(counts))
+	for eventType := range counts {
+		eventTypes = append(eventTypes, eventType)
+	}
+	slices.SortFunc(eventTypes, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+
+	var parts []string
+	for _, eventType := range eventTypes {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[eventType], strings.ReplaceAll(eventType, "_", " ")))
+	}
+
+	events := "events"
+	if len(queued) == 1 {
+		events = "event"
+	}
+
+	return &Message{
+		Channel: channel,
+		Text:    fmt.Sprintf(":sunrise: %d %s outside delivery hours (%s), see the thread", len(queued), events, strings.Join(parts, ", ")),
+	}
+}
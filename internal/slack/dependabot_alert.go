@@ -6,8 +6,13 @@ import (
 	"github.com/navikt/ghep/internal/github"
 )
 
-func CreateDependabotAlertMessage(channel, timestamp string, event github.Event) *Message {
+// CreateDependabotAlertMessage creates the message for a Dependabot alert. New
+// alerts get a menu for dismissing them if the workspace is interactive.
+func CreateDependabotAlertMessage(channel, timestamp, team string, interactive, blockKit bool, event github.Event) *Message {
+	text := fmt.Sprintf("A Dependabot alert was just %s for the repository %s.\nRead more: %s", event.Action, event.Repository.ToSlack(), event.Alert.URL)
+
 	var attachments []Attachment
+	blocks := []Block{sectionBlock(text)}
 	if event.Action == "created" {
 		attachments = []Attachment{
 			{
@@ -15,11 +20,27 @@ func CreateDependabotAlertMessage(channel, timestamp string, event github.Event)
 				Color: ColorDefault,
 			},
 		}
+		blocks = append(blocks, sectionBlock(event.Alert.SecurityAdvisory.Summary))
+
+		if interactive && event.Alert.Number > 0 {
+			actions := dependabotAlertActions(team, event)
+			attachments = append(attachments, actions)
+			blocks = append(blocks, actions.Blocks...)
+		}
+	}
+
+	if blockKit {
+		return &Message{
+			Channel:         channel,
+			Text:            text,
+			ThreadTimestamp: timestamp,
+			Blocks:          blocks,
+		}
 	}
 
 	return &Message{
 		Channel:         channel,
-		Text:            fmt.Sprintf("A Dependabot alert was just %s for the repository %s.\nRead more: %s", event.Action, event.Repository.ToSlack(), event.Alert.URL),
+		Text:            text,
 		ThreadTimestamp: timestamp,
 		Attachments:     attachments,
 	}
@@ -0,0 +1,78 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// CreateDependencyRollupMessage creates the daily roll-up of dependency pull
+// requests, with one attachment per repository grouped by ecosystem. The pull
+// requests are expected to be sorted by repository, ecosystem and number.
+func CreateDependencyRollupMessage(channel string, prs []gensql.DependencyPullRequest) *Message {
+	var opened, merged, failing int
+	for _, pr := range prs {
+		switch {
+		case pr.State == "merged":
+			merged++
+		case pr.State == "open" && pr.CiFailed:
+			failing++
+		case pr.State == "open":
+			opened++
+		}
+	}
+
+	var attachments []Attachment
+	for start := 0; start < len(prs); {
+		end := start
+		for end < len(prs) && prs[end].Repository == prs[start].Repository {
+			end++
+		}
+
+		attachments = append(attachments, dependencyRollupAttachment(prs[start:end]))
+		start = end
+	}
+
+	return &Message{
+		Channel:     channel,
+		Text:        fmt.Sprintf(":package: Dependency updates: %d open, %d merged, %d failing CI", opened, merged, failing),
+		Attachments: attachments,
+	}
+}
+
+func dependencyRollupAttachment(prs []gensql.DependencyPullRequest) Attachment {
+	color := ColorOpened
+	lines := []string{fmt.Sprintf("*%s*", prs[0].Repository)}
+	ecosystem := ""
+	for _, pr := range prs {
+		if pr.Ecosystem != ecosystem {
+			ecosystem = pr.Ecosystem
+			lines = append(lines, fmt.Sprintf("_%s_", ecosystem))
+		}
+
+		if pr.State == "open" && pr.CiFailed {
+			color = ColorClosed
+		}
+
+		lines = append(lines, fmt.Sprintf("%s <%s|#%d> %s", dependencyStatusEmoji(pr), pr.Url, pr.Number, pr.Title))
+	}
+
+	return Attachment{
+		Text:  strings.Join(lines, "\n"),
+		Color: color,
+	}
+}
+
+func dependencyStatusEmoji(pr gensql.DependencyPullRequest) string {
+	switch {
+	case pr.State == "merged":
+		return ":white_check_mark:"
+	case pr.State == "closed":
+		return ":no_entry_sign:"
+	case pr.CiFailed:
+		return ":x:"
+	default:
+		return ":hourglass_flowing_sand:"
+	}
+}
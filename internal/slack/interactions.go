@@ -0,0 +1,175 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+)
+
+const (
+	ActionDismissDependabotAlert = "dismiss_dependabot_alert"
+	ActionRerunFailedJobs        = "rerun_failed_jobs"
+
+	// maxRequestAge is how old a signed request from Slack can be, to stop replays.
+	maxRequestAge = 5 * time.Minute
+)
+
+// ActionTarget is what a button on a message acts on, and is stored in the
+// block ID of the section holding the button.
+type ActionTarget struct {
+	Team       string
+	Repository string
+	// ID is the alert number or workflow run ID.
+	ID int
+}
+
+func (t ActionTarget) String() string {
+	return fmt.Sprintf("%s|%s|%d", t.Team, t.Repository, t.ID)
+}
+
+// ParseActionTarget parses a block ID created by ActionTarget.String.
+func ParseActionTarget(blockID string) (ActionTarget, error) {
+	parts := strings.Split(blockID, "|")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return ActionTarget{}, fmt.Errorf("invalid action target %q", blockID)
+	}
+
+	id, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return ActionTarget{}, fmt.Errorf("invalid action target %q: %w", blockID, err)
+	}
+
+	return ActionTarget{Team: parts[0], Repository: parts[1], ID: id}, nil
+}
+
+// Interaction is the payload Slack sends when a user clicks a button or picks
+// an option on a message.
+type Interaction struct {
+	Type string `json:"type"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Message struct {
+		Timestamp       string `json:"ts"`
+		ThreadTimestamp string `json:"thread_ts"`
+	} `json:"message"`
+	Actions []struct {
+		ActionID       string `json:"action_id"`
+		BlockID        string `json:"block_id"`
+		Value          string `json:"value"`
+		SelectedOption *struct {
+			Value string `json:"value"`
+		} `json:"selected_option"`
+	} `json:"actions"`
+}
+
+// ParseInteraction parses the payload field of an interaction request.
+func ParseInteraction(payload string) (Interaction, error) {
+	var interaction Interaction
+	if err := json.Unmarshal([]byte(payload), &interaction); err != nil {
+		return Interaction{}, fmt.Errorf("unmarshalling interaction: %w", err)
+	}
+
+	return interaction, nil
+}
+
+// ThreadTimestamp returns the thread the message clicked on belongs to, or the message itself.
+func (i Interaction) ThreadTimestamp() string {
+	if i.Message.ThreadTimestamp != "" {
+		return i.Message.ThreadTimestamp
+	}
+
+	return i.Message.Timestamp
+}
+
+// VerifySignature checks the X-Slack-Signature of a request, as described in
+// https://api.slack.com/authentication/verifying-requests-from-slack.
+func VerifySignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+
+	if age := now.Sub(time.Unix(seconds, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return fmt.Errorf("request timestamp is %s old", age)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+// dependabotAlertActions returns an attachment with a menu for dismissing the alert with a reason.
+func dependabotAlertActions(team string, event github.Event) Attachment {
+	labels := map[string]string{
+		"fix_started":    "A fix has already been started",
+		"inaccurate":     "This alert is inaccurate or incorrect",
+		"no_bandwidth":   "No bandwidth to fix this",
+		"not_used":       "Vulnerable code is not actually used",
+		"tolerable_risk": "Risk is tolerable to this project",
+	}
+
+	options := make([]Option, 0, len(github.DependabotDismissReasons))
+	for _, reason := range github.DependabotDismissReasons {
+		options = append(options, Option{Text: *plainText(labels[reason]), Value: reason})
+	}
+
+	target := ActionTarget{Team: team, Repository: event.Repository.Name, ID: event.Alert.Number}
+
+	return Attachment{
+		Color: ColorDefault,
+		Blocks: []Block{
+			{
+				Type:    "section",
+				BlockID: target.String(),
+				Text:    &Text{Type: "mrkdwn", Text: "Dismiss this alert?"},
+				Accessory: &Accessory{
+					Type:        "static_select",
+					ActionID:    ActionDismissDependabotAlert,
+					Placeholder: plainText("Dismiss with reason"),
+					Options:     options,
+				},
+			},
+		},
+	}
+}
+
+// workflowActions returns an attachment with a button for re-running the failed jobs.
+func workflowActions(team string, event github.Event) Attachment {
+	target := ActionTarget{Team: team, Repository: event.Repository.Name, ID: event.Workflow.ID}
+
+	return Attachment{
+		Color: ColorFailed,
+		Blocks: []Block{
+			{
+				Type:    "section",
+				BlockID: target.String(),
+				Text:    &Text{Type: "mrkdwn", Text: "Flaky? Re-run the failed jobs."},
+				Accessory: &Accessory{
+					Type:     "button",
+					ActionID: ActionRerunFailedJobs,
+					Text:     plainText("Re-run failed jobs"),
+					Value:    target.String(),
+				},
+			},
+		},
+	}
+}
@@ -11,9 +11,12 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/sql"
+	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
-func CreateIssueMessage(ctx context.Context, log *slog.Logger, db sql.Database, channel, threadTimestamp string, pingSlack bool, event github.Event) *Message {
+// CreateIssueMessage creates the message for an issue. With Block Kit, the
+// assignees and the repository are context blocks below the description.
+func CreateIssueMessage(ctx context.Context, log *slog.Logger, db sql.Database, channel, threadTimestamp, slackWorkspace string, pingSlack, blockKit bool, event github.Event) *Message {
 	color := ColorOpened
 
 	text := fmt.Sprintf("Issue <%s|#%d> %s in `%s` by %s", event.Issue.URL, event.Issue.Number, event.Action, event.Repository.ToSlack(), event.Sender.ToSlack())
@@ -27,12 +30,13 @@ func CreateIssueMessage(ctx context.Context, log *slog.Logger, db sql.Database,
 	if event.Action != "closed" && event.Issue.Body != "" {
 		attachmentText = fmt.Sprintf("%s\n%s", attachmentText, event.Issue.Body)
 	}
+	blocks := []Block{sectionBlock(text), sectionBlock(attachmentText)}
 
 	if len(event.Issue.Assignees) > 0 {
 		var assignees strings.Builder
 		for i, assignee := range event.Issue.Assignees {
 			if pingSlack {
-				userID, err := db.GetUserSlackID(ctx, assignee.Login)
+				userID, err := db.GetUserSlackID(ctx, gensql.GetUserSlackIDParams{Workspace: slackWorkspace, Login: assignee.Login})
 				if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 					log.Error("Getting user Slack ID", "user", assignee.Login, "error", err)
 				}
@@ -52,6 +56,17 @@ func CreateIssueMessage(ctx context.Context, log *slog.Logger, db sql.Database,
 		}
 
 		attachmentText += fmt.Sprintf("\n*Assignees:* %s", assignees.String())
+		blocks = append(blocks, contextBlock("*Assignees:* "+assignees.String()))
+	}
+
+	footer := fmt.Sprintf("<%s|%s>", event.Repository.URL, event.Repository.FullName)
+	if blockKit {
+		return &Message{
+			Channel:         channel,
+			ThreadTimestamp: threadTimestamp,
+			Text:            text,
+			Blocks:          append(blocks, footerBlock(0, neutralGithubIcon, footer)),
+		}
 	}
 
 	return &Message{
@@ -64,7 +79,7 @@ func CreateIssueMessage(ctx context.Context, log *slog.Logger, db sql.Database,
 				Type:       "mrkdwn",
 				Color:      color,
 				FooterIcon: neutralGithubIcon,
-				Footer:     fmt.Sprintf("<%s|%s>", event.Repository.URL, event.Repository.FullName),
+				Footer:     footer,
 			},
 		},
 	}
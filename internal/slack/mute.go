@@ -0,0 +1,36 @@
+package slack
+
+import (
+	"fmt"
+
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// MuteTarget describes what a mute silences, like "`pulls` events from `ghep`".
+func MuteTarget(mute gensql.Mute) string {
+	switch {
+	case mute.SourceType == "":
+		return fmt.Sprintf("`%s`", mute.Repository)
+	case mute.Repository == "":
+		return fmt.Sprintf("`%s` events", mute.SourceType)
+	default:
+		return fmt.Sprintf("`%s` events from `%s`", mute.SourceType, mute.Repository)
+	}
+}
+
+func CreateMuteEndedMessage(channel string, mute gensql.Mute) *Message {
+	events := "events were"
+	if mute.Suppressed == 1 {
+		events = "event was"
+	}
+
+	text := fmt.Sprintf("The mute of %s has ended, %d %s suppressed", MuteTarget(mute), mute.Suppressed, events)
+	if mute.Reason != "" {
+		text += fmt.Sprintf(" (%s)", mute.Reason)
+	}
+
+	return &Message{
+		Channel: channel,
+		Text:    text,
+	}
+}
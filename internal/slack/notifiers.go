@@ -0,0 +1,65 @@
+package slack
+
+import (
+	"encoding/json"
+
+	"github.com/navikt/ghep/internal/github"
+)
+
+// Notifier posts messages to a service that can neither thread, update nor
+// react to them, like Microsoft Teams. Messages are posted for a team and a
+// key, which is the source type or the digest the message is posted for.
+type Notifier interface {
+	Notify(team, key string, message *Message) error
+}
+
+// Notifiers picks where a team's messages are posted, either one of the Slack
+// workspaces or Microsoft Teams.
+type Notifiers struct {
+	slack       map[string]Slacker
+	interactive map[string]bool
+	msteams     Notifier
+}
+
+// NewNotifiers takes the Slack workspaces keyed by name, the workspaces with
+// interactivity enabled, and the Microsoft Teams notifier used by sources and
+// digests with the msteams notifier.
+func NewNotifiers(workspaces map[string]Slacker, interactive map[string]bool, msteams Notifier) Notifiers {
+	return Notifiers{
+		slack:       workspaces,
+		interactive: interactive,
+		msteams:     msteams,
+	}
+}
+
+// Slack returns the team's Slack workspace, used to thread, update and react
+// to messages posted to Slack.
+func (n Notifiers) Slack(team github.Team) Slacker {
+	return n.slack[team.SlackWorkspace]
+}
+
+// Post posts the message with the notifier, where an empty notifier is the
+// team's Slack workspace. The response is nil for other notifiers, as only
+// Slack messages can be threaded, updated and reacted to.
+func (n Notifiers) Post(team github.Team, notifier, key string, message *Message) (*MessageResponse, error) {
+	if notifier == github.NotifierMSTeams {
+		return nil, n.msteams.Notify(team.Name, key, message)
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := n.Slack(team).PostMessage(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Interactive returns true if messages posted with the notifier can have buttons.
+func (n Notifiers) Interactive(team github.Team, notifier string) bool {
+	return notifier != github.NotifierMSTeams && n.interactive[team.SlackWorkspace]
+}
@@ -0,0 +1,70 @@
+package slack
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/navikt/ghep/internal/github"
+)
+
+// maxQuotedComment is how much of a comment is quoted in a DM about a mention.
+const maxQuotedComment = 500
+
+func pullRequestLink(pr *github.Issue) string {
+	return fmt.Sprintf("<%s|#%d %s>", pr.URL, pr.Number, pr.Title)
+}
+
+func CreateReviewRequestedDM(channel string, event github.Event) *Message {
+	return &Message{
+		Channel: channel,
+		Text:    fmt.Sprintf(":eyes: %s requested your review on %s in %s", event.Sender.ToSlack(), pullRequestLink(event.PullRequest), event.Repository.ToSlack()),
+	}
+}
+
+func CreateReviewedDM(channel string, event github.Event) *Message {
+	text := fmt.Sprintf(":white_check_mark: %s approved %s in %s", event.Review.User.ToSlack(), pullRequestLink(event.PullRequest), event.Repository.ToSlack())
+	if event.Review.State == "changes_requested" {
+		text = fmt.Sprintf(":memo: %s requested changes on %s in %s", event.Review.User.ToSlack(), pullRequestLink(event.PullRequest), event.Repository.ToSlack())
+	}
+
+	return &Message{
+		Channel: channel,
+		Text:    text,
+	}
+}
+
+func CreateWorkflowFailedDM(channel string, event github.Event) *Message {
+	workflow := event.Workflow
+	sha := workflow.HeadSHA
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+
+	return &Message{
+		Channel: channel,
+		Text:    fmt.Sprintf(":x: <%s|%s> failed on your commit `%s` to `%s` in %s", workflow.URL, workflow.Name, sha, workflow.HeadBranch, event.Repository.ToSlack()),
+	}
+}
+
+func CreateMentionedDM(channel string, event github.Event) *Message {
+	issue := event.Issue
+	if issue == nil {
+		issue = event.PullRequest
+	}
+
+	comment := event.Comment.Body
+	if utf8.RuneCountInString(comment) > maxQuotedComment {
+		comment = string([]rune(comment)[:maxQuotedComment-1]) + "…"
+	}
+
+	return &Message{
+		Channel: channel,
+		Text:    fmt.Sprintf(":speech_balloon: %s mentioned you in <%s|#%d %s> in %s", event.Comment.User.ToSlack(), event.Comment.URL, issue.Number, issue.Title, event.Repository.ToSlack()),
+		Attachments: []Attachment{
+			{
+				Text:  comment,
+				Color: ColorDefault,
+			},
+		},
+	}
+}
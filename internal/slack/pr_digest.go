@@ -8,7 +8,9 @@ import (
 	"github.com/navikt/ghep/internal/github"
 )
 
-func CreatePullRequestDigestMessage(channel, teamName string, repoPRs []github.RepoPRs) (summary *Message, threadMsgs []*Message) {
+// CreatePullRequestDigestMessage creates the weekly pull request digest, with a
+// summary and one thread message per repository.
+func CreatePullRequestDigestMessage(channel, teamName string, blockKit bool, repoPRs []github.RepoPRs) (summary *Message, threadMsgs []*Message) {
 	if len(repoPRs) == 0 {
 		text := "Gratulerer! Alle pull requests er merget – dere er helt à jour! :tada:"
 		if teamName != "" {
@@ -18,6 +20,7 @@ func CreatePullRequestDigestMessage(channel, teamName string, repoPRs []github.R
 		return &Message{
 			Channel: channel,
 			Text:    text,
+			Blocks:  textBlocks(blockKit, text),
 		}, nil
 	}
 
@@ -42,10 +45,11 @@ func CreatePullRequestDigestMessage(channel, teamName string, repoPRs []github.R
 		prUnit = "åpen pull request"
 	}
 
-	summaryText := fmt.Sprintf("*Ukentlig PR-oversikt — %s*\n%d %s med %d %s", dateStr, len(repoPRs), repoUnit, totalPRs, prUnit)
+	title := fmt.Sprintf("Ukentlig PR-oversikt — %s", dateStr)
 	if teamName != "" {
-		summaryText = fmt.Sprintf("*Ukentlig PR-oversikt for %s — %s*\n%d %s med %d %s", teamName, dateStr, len(repoPRs), repoUnit, totalPRs, prUnit)
+		title = fmt.Sprintf("Ukentlig PR-oversikt for %s — %s", teamName, dateStr)
 	}
+	counts := fmt.Sprintf("%d %s med %d %s", len(repoPRs), repoUnit, totalPRs, prUnit)
 
 	for _, repo := range repoPRs {
 		var sb strings.Builder
@@ -65,14 +69,17 @@ func CreatePullRequestDigestMessage(channel, teamName string, repoPRs []github.R
 			fmt.Fprintf(&sb, "• <%s|#%d %s> (%d %s)\n", pr.URL, pr.Number, pr.Title, days, dayUnit)
 		}
 
+		text := strings.TrimRight(sb.String(), "\n")
 		threadMsgs = append(threadMsgs, &Message{
 			Channel: channel,
-			Text:    strings.TrimRight(sb.String(), "\n"),
+			Text:    text,
+			Blocks:  textBlocks(blockKit, text),
 		})
 	}
 
 	return &Message{
 		Channel: channel,
-		Text:    summaryText,
+		Text:    fmt.Sprintf("*%s*\n%s", title, counts),
+		Blocks:  digestBlocks(blockKit, title, counts),
 	}, threadMsgs
 }
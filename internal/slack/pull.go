@@ -11,9 +11,13 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/navikt/ghep/internal/github"
 	"github.com/navikt/ghep/internal/sql"
+	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
-func CreatePullRequestMessage(ctx context.Context, log *slog.Logger, db sql.Database, channel, threadTimestamp string, pingSlack, minimalist bool, event github.Event) *Message {
+// CreatePullRequestMessage creates the message for a pull request. With Block
+// Kit, the requested reviewers and the repository are context blocks below
+// the description.
+func CreatePullRequestMessage(ctx context.Context, log *slog.Logger, db sql.Database, channel, threadTimestamp, slackWorkspace string, pingSlack, minimalist, blockKit bool, event github.Event) *Message {
 	color := ColorOpened
 	switch event.Action {
 	case "merged":
@@ -32,8 +36,10 @@ func CreatePullRequestMessage(ctx context.Context, log *slog.Logger, db sql.Data
 
 	text := ""
 	attachments := []Attachment{}
+	var blocks []Block
 	if minimalist {
 		text = fmt.Sprintf("%s <%s|#%d %s> %s in `%s` by %s", eventType, event.PullRequest.URL, event.PullRequest.Number, html.EscapeString(event.PullRequest.Title), event.Action, event.Repository.ToSlack(), event.Sender.ToSlack())
+		blocks = []Block{sectionBlock(text)}
 	} else {
 		text = fmt.Sprintf("%s <%s|#%d> %s in `%s` by %s", eventType, event.PullRequest.URL, event.PullRequest.Number, event.Action, event.Repository.ToSlack(), event.Sender.ToSlack())
 		attachmentText := fmt.Sprintf("*<%s|#%d %s>*", event.PullRequest.URL, event.PullRequest.Number, html.EscapeString(event.PullRequest.Title))
@@ -41,12 +47,13 @@ func CreatePullRequestMessage(ctx context.Context, log *slog.Logger, db sql.Data
 		if event.Action != "closed" && event.PullRequest.Body != "" {
 			attachmentText = fmt.Sprintf("%s\n%s", attachmentText, event.PullRequest.Body)
 		}
+		blocks = []Block{sectionBlock(text), sectionBlock(attachmentText)}
 
 		if len(event.PullRequest.RequestedReviewers) > 0 {
 			var reviewers strings.Builder
 			for i, reviewer := range event.PullRequest.RequestedReviewers {
 				if pingSlack {
-					userID, err := db.GetUserSlackID(ctx, reviewer.Login)
+					userID, err := db.GetUserSlackID(ctx, gensql.GetUserSlackIDParams{Workspace: slackWorkspace, Login: reviewer.Login})
 					if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 						log.Error("Getting user Slack ID", "user", reviewer.Login, "error", err)
 					}
@@ -66,19 +73,31 @@ func CreatePullRequestMessage(ctx context.Context, log *slog.Logger, db sql.Data
 			}
 
 			attachmentText += fmt.Sprintf("\n*Requested reviewers:* %s", reviewers.String())
+			blocks = append(blocks, contextBlock("*Requested reviewers:* "+reviewers.String()))
 		}
 
+		footer := fmt.Sprintf("<%s|%s>", event.Repository.URL, event.Repository.FullName)
+		blocks = append(blocks, footerBlock(0, neutralGithubIcon, footer))
 		attachments = []Attachment{
 			{
 				Text:       attachmentText,
 				Type:       "mrkdwn",
 				Color:      color,
 				FooterIcon: neutralGithubIcon,
-				Footer:     fmt.Sprintf("<%s|%s>", event.Repository.URL, event.Repository.FullName),
+				Footer:     footer,
 			},
 		}
 	}
 
+	if blockKit {
+		return &Message{
+			Channel:         channel,
+			ThreadTimestamp: threadTimestamp,
+			Text:            text,
+			Blocks:          blocks,
+		}
+	}
+
 	return &Message{
 		Channel:         channel,
 		ThreadTimestamp: threadTimestamp,
@@ -0,0 +1,61 @@
+package slack
+
+import (
+	"slices"
+
+	"github.com/navikt/ghep/internal/github"
+)
+
+const redactedNote = "_:lock: Something looking like a secret was redacted from this message._"
+
+// Redact returns the message with secrets redacted from its text, attachments
+// and blocks, and a note saying so. The message is returned as is if there was
+// nothing to redact.
+func Redact(message *Message, redaction github.Redaction) *Message {
+	if message == nil {
+		return nil
+	}
+
+	redacted := *message
+	text, found := redaction.Redact(message.Text)
+	redacted.Text = text
+
+	redacted.Attachments = slices.Clone(message.Attachments)
+	for i := range redacted.Attachments {
+		text, ok := redaction.Redact(redacted.Attachments[i].Text)
+		redacted.Attachments[i].Text = text
+		found = found || ok
+	}
+
+	// Blocks share their text and elements with the message, so those are copied before redacting
+	redacted.Blocks = slices.Clone(message.Blocks)
+	for i := range redacted.Blocks {
+		block := &redacted.Blocks[i]
+		if block.Text != nil {
+			text, ok := redaction.Redact(block.Text.Text)
+			block.Text = &Text{Type: block.Text.Type, Text: text}
+			found = found || ok
+		}
+
+		block.Elements = slices.Clone(block.Elements)
+		for j := range block.Elements {
+			text, ok := redaction.Redact(block.Elements[j].Text)
+			block.Elements[j].Text = text
+			found = found || ok
+		}
+	}
+
+	if !found {
+		return message
+	}
+
+	if len(redacted.Blocks) > 0 {
+		redacted.Blocks = limitBlocks(append(redacted.Blocks, contextBlock(redactedNote)))
+	} else if n := len(redacted.Attachments); n > 0 {
+		redacted.Attachments[n-1].Text += "\n" + redactedNote
+	} else {
+		redacted.Text += "\n" + redactedNote
+	}
+
+	return &redacted
+}
@@ -0,0 +1,55 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/navikt/ghep/internal/github"
+)
+
+// CreateRegressionMessage creates the message for someone turning off
+// protections of a repository, coloured by how severe it is.
+func CreateRegressionMessage(channel string, eventType github.EventType, severity github.SeverityType, event github.Event) *Message {
+	repository := event.Repository.ToSlack()
+	sender := event.Sender.ToSlack()
+
+	var text, details string
+	switch eventType {
+	case github.TypeSecurityAndAnalysis:
+		disabled, _ := event.DisabledSecurityFeatures()
+		features := make([]string, len(disabled))
+		for i, feature := range disabled {
+			features[i] = strings.ReplaceAll(feature, "_", " ")
+		}
+
+		list := features[len(features)-1]
+		if len(features) > 1 {
+			list = strings.Join(features[:len(features)-1], ", ") + " and " + list
+		}
+
+		text = fmt.Sprintf("%s disabled %s for the repository %s", sender, list, repository)
+		details = fmt.Sprintf("<%s/settings/security_analysis|Code security settings>", event.Repository.URL)
+	case github.TypeBranchProtectionRule:
+		text = fmt.Sprintf("%s deleted the branch protection rule `%s` for the default branch of the repository %s", sender, event.BranchProtectionRule.Name, repository)
+		details = fmt.Sprintf("<%s/settings/branches|Branch protection settings>", event.Repository.URL)
+	case github.TypeRepositoryRuleset:
+		text = fmt.Sprintf("%s deleted the ruleset `%s` for the default branch of the repository %s", sender, event.RepositoryRuleset.Name, repository)
+		details = fmt.Sprintf("<%s/settings/rules|Ruleset settings>", event.Repository.URL)
+	case github.TypeForcePush:
+		text = fmt.Sprintf("%s force pushed to `%s` in the repository %s", sender, event.Repository.DefaultBranch, repository)
+		details = fmt.Sprintf("<%s|Compare changes>", event.Compare)
+	default:
+		return nil
+	}
+
+	return &Message{
+		Channel: channel,
+		Text:    text,
+		Attachments: []Attachment{
+			{
+				Text:  fmt.Sprintf("*Severity:* %s\n%s", severity.Name(), details),
+				Color: getColorBySeverity(severity),
+			},
+		},
+	}
+}
@@ -6,7 +6,8 @@ import (
 	"github.com/navikt/ghep/internal/github"
 )
 
-func CreateReleaseMessage(channel string, event github.Event) *Message {
+// CreateReleaseMessage creates the message for a release, with the release notes.
+func CreateReleaseMessage(channel string, blockKit bool, event github.Event) *Message {
 	releaseType := "release"
 	if event.Release.Draft {
 		releaseType = "draft release"
@@ -15,6 +16,19 @@ func CreateReleaseMessage(channel string, event github.Event) *Message {
 	}
 
 	text := fmt.Sprintf("%s created a <%s|%s> (`%s`) in %s", event.Sender.ToSlack(), event.Release.URL, releaseType, event.Release.Tag, event.Repository.ToSlack())
+	footer := fmt.Sprintf("<%s|%s>", event.Repository.URL, event.Repository.FullName)
+
+	if blockKit {
+		// Release notes can be long, and are split over more than one section
+		blocks := append([]Block{sectionBlock(text)}, sectionBlocks(event.Release.Body)...)
+		blocks = append(blocks, footerBlock(0, neutralGithubIcon, footer))
+
+		return &Message{
+			Channel: channel,
+			Text:    text,
+			Blocks:  limitBlocks(blocks),
+		}
+	}
 
 	return &Message{
 		Channel: channel,
@@ -24,7 +38,7 @@ func CreateReleaseMessage(channel string, event github.Event) *Message {
 				Text:       event.Release.Body,
 				Color:      ColorDefault,
 				FooterIcon: neutralGithubIcon,
-				Footer:     fmt.Sprintf("<%s|%s>", event.Repository.URL, event.Repository.FullName),
+				Footer:     footer,
 			},
 		},
 	}
@@ -0,0 +1,58 @@
+package slack
+
+import (
+	"fmt"
+
+	"github.com/navikt/ghep/internal/github"
+)
+
+// CreateRepositoryAdvisoryMessage creates the message for a security advisory
+// for one of the team's repositories. When a privately reported vulnerability
+// is published, it is posted in the thread of the report.
+func CreateRepositoryAdvisoryMessage(channel, timestamp string, blockKit bool, event github.Event) *Message {
+	advisory := event.RepositoryAdvisory
+	link := fmt.Sprintf("<%s|%s>", advisory.URL, advisory.GHSAID)
+
+	var text string
+	switch event.Action {
+	case "reported":
+		text = fmt.Sprintf("A vulnerability was just privately reported for the repository %s by %s: %s", event.Repository.ToSlack(), advisory.Author.ToSlack(), link)
+	default:
+		text = fmt.Sprintf("A repository security advisory was just %s for the repository %s: %s", event.Action, event.Repository.ToSlack(), link)
+	}
+
+	var attachments []Attachment
+	blocks := []Block{sectionBlock(text)}
+	if timestamp == "" {
+		attachment := Attachment{
+			Text:  fmt.Sprintf("*%s*\n%s", advisory.Summary, advisory.Description),
+			Color: ColorDefault,
+		}
+		if advisory.Severity != "" {
+			attachment.Color = getColorBySeverity(advisory.SeverityType())
+		}
+		blocks = append(blocks, sectionBlock(attachment.Text))
+		if advisory.CVEID != "" {
+			attachment.Footer = advisory.CVEID
+			blocks = append(blocks, contextBlock(advisory.CVEID))
+		}
+
+		attachments = []Attachment{attachment}
+	}
+
+	if blockKit {
+		return &Message{
+			Channel:         channel,
+			Text:            text,
+			Blocks:          blocks,
+			ThreadTimestamp: timestamp,
+		}
+	}
+
+	return &Message{
+		Channel:         channel,
+		Text:            text,
+		Attachments:     attachments,
+		ThreadTimestamp: timestamp,
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/navikt/ghep/internal/github"
 )
 
-func CreateSecretScanningAlertMessage(channel, timestamp string, event github.Event) *Message {
+// CreateSecretScanningAlertMessage creates the message for a secret scanning
+// alert, warning when the secret was publicly leaked.
+func CreateSecretScanningAlertMessage(channel, timestamp string, blockKit bool, event github.Event) *Message {
 	var text string
 	switch event.Action {
 	case "created":
@@ -20,12 +22,23 @@ func CreateSecretScanningAlertMessage(channel, timestamp string, event github.Ev
 	}
 
 	var attachments []Attachment
+	blocks := []Block{sectionBlock(text)}
 	if event.Alert.PubliclyLeaked && event.Action == "created" {
 		attachment := Attachment{
 			Text:  "The secret was publicly leaked!",
 			Color: ColorCritical,
 		}
 		attachments = append(attachments, attachment)
+		blocks = append(blocks, sectionBlock(":rotating_light: *The secret was publicly leaked!*"))
+	}
+
+	if blockKit {
+		return &Message{
+			Channel:         channel,
+			Text:            text,
+			Blocks:          blocks,
+			ThreadTimestamp: timestamp,
+		}
 	}
 
 	return &Message{
@@ -35,3 +48,31 @@ func CreateSecretScanningAlertMessage(channel, timestamp string, event github.Ev
 		ThreadTimestamp: timestamp,
 	}
 }
+
+// CreateSecretAuthorDM tells whoever committed the secret how to remediate it.
+func CreateSecretAuthorDM(channel string, event github.Event, location github.SecretLocation) *Message {
+	sha := location.CommitSHA
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+
+	text := fmt.Sprintf(":rotating_light: Secret scanning found a `%s` in your commit `%s` to %s, in <%s|%s line %d>.\n", *event.Alert.SecretType, sha, event.Repository.ToSlack(), location.URL, location.Path, location.StartLine)
+	text += "Removing it from the code is not enough, as it stays in the Git history. To remediate it:\n"
+	text += "1. Revoke or rotate the secret where it was issued\n"
+	text += "2. Move the new secret out of the code, into a secret store or an environment variable\n"
+	text += fmt.Sprintf("3. Close <%s|the alert> as revoked", event.Alert.URL)
+
+	return &Message{
+		Channel: channel,
+		Text:    text,
+	}
+}
+
+// CreateSecretAuthorNotifiedMessage notes in the alert thread that the author got a DM.
+func CreateSecretAuthorNotifiedMessage(channel, timestamp, slackID string) *Message {
+	return &Message{
+		Channel:         channel,
+		Text:            fmt.Sprintf("<@%s>, who committed the secret, has been sent the steps to remediate it.", slackID),
+		ThreadTimestamp: timestamp,
+	}
+}
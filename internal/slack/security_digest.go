@@ -8,16 +8,22 @@ import (
 	"github.com/navikt/ghep/internal/github"
 )
 
-func CreateSecurityDigestMessage(channel, teamName string, repoAlerts []github.RepoSecurityAlerts) (summary *Message, threadMsgs []*Message) {
+// CreateSecurityDigestMessage creates the weekly security digest. The trend is
+// compared with the previous digest, and is nil for the team's first digest.
+func CreateSecurityDigestMessage(channel, teamName string, blockKit bool, repoAlerts []github.RepoSecurityAlerts, trend *github.SecurityTrend) (summary *Message, threadMsgs []*Message) {
 	if len(repoAlerts) == 0 {
 		text := "Gratulerer! Ingen åpne sikkerhetsvarsler – dere er helt sikre! :tada:"
 		if teamName != "" {
 			text = fmt.Sprintf("Gratulerer! Ingen åpne sikkerhetsvarsler – %s er helt sikre! :tada:", teamName)
 		}
+		if trend != nil && trend.Fixed > 0 {
+			text += fmt.Sprintf("\n:white_check_mark: %d lukket siden forrige digest", trend.Fixed)
+		}
 
 		return &Message{
 			Channel: channel,
 			Text:    text,
+			Blocks:  textBlocks(blockKit, text),
 		}, nil
 	}
 
@@ -62,16 +68,14 @@ func CreateSecurityDigestMessage(channel, teamName string, repoAlerts []github.R
 		breakdown.WriteString(")")
 	}
 
-	summaryText := fmt.Sprintf("*Ukentlig sikkerhetsdigest — %s*\n%d %s på tvers av %d %s%s",
-		dateStr, total, alertUnit, len(repoAlerts), repoUnit, breakdown.String())
+	title := fmt.Sprintf("Ukentlig sikkerhetsdigest — %s", dateStr)
 	if teamName != "" {
-		summaryText = fmt.Sprintf("*Ukentlig sikkerhetsdigest for %s — %s*\n%d %s på tvers av %d %s%s",
-			teamName, dateStr, total, alertUnit, len(repoAlerts), repoUnit, breakdown.String())
+		title = fmt.Sprintf("Ukentlig sikkerhetsdigest for %s — %s", teamName, dateStr)
 	}
+	summaryText := fmt.Sprintf("%d %s på tvers av %d %s%s", total, alertUnit, len(repoAlerts), repoUnit, breakdown.String())
 
-	summary = &Message{
-		Channel: channel,
-		Text:    summaryText,
+	if trend != nil {
+		summaryText += fmt.Sprintf("\n:new: %d nye, :white_check_mark: %d lukket siden forrige digest", trend.New, trend.Fixed)
 	}
 
 	var totalCriticals int
@@ -79,7 +83,21 @@ func CreateSecurityDigestMessage(channel, teamName string, repoAlerts []github.R
 	// One thread message per repo
 	for _, repo := range repoAlerts {
 		var sb strings.Builder
-		fmt.Fprintf(&sb, "*%s*\n", repo.ToSlack())
+		fmt.Fprintf(&sb, "*%s*", repo.ToSlack())
+		if trend != nil {
+			if delta := trend.Delta(repo.Repository.Name); delta != "" {
+				fmt.Fprintf(&sb, " %s", delta)
+			}
+		}
+		if oldest := repo.Oldest(); !oldest.IsZero() {
+			days := int(now.Sub(oldest).Hours() / 24)
+			dayUnit := "dager"
+			if days == 1 {
+				dayUnit = "dag"
+			}
+			fmt.Fprintf(&sb, " (eldste: %d %s)", days, dayUnit)
+		}
+		sb.WriteString("\n")
 
 		if len(repo.SecretScanning) > 0 {
 			fmt.Fprintf(&sb, ":key: %s\n", repo.ToSlackWithMetadata("secret-scanning", len(repo.SecretScanning)))
@@ -110,6 +128,7 @@ func CreateSecurityDigestMessage(channel, teamName string, repoAlerts []github.R
 		threadMsgs = append(threadMsgs, &Message{
 			Channel: channel,
 			Text:    sb.String(),
+			Blocks:  textBlocks(blockKit, sb.String()),
 		})
 	}
 
@@ -119,7 +138,28 @@ func CreateSecurityDigestMessage(channel, teamName string, repoAlerts []github.R
 			criticalUnit = "critical"
 		}
 
-		summary.Text = summary.Text + fmt.Sprintf("\n:warning: %d %s :warning:", totalCriticals, criticalUnit)
+		summaryText += fmt.Sprintf("\n:warning: %d %s :warning:", totalCriticals, criticalUnit)
+	}
+
+	summary = &Message{
+		Channel: channel,
+		Text:    fmt.Sprintf("*%s*\n%s", title, summaryText),
+		Blocks:  digestBlocks(blockKit, title, summaryText),
+	}
+
+	if trend != nil && len(trend.NewlyCritical) > 0 {
+		var sb strings.Builder
+		fmt.Fprintln(&sb, "*Nye critical siden forrige digest*")
+		for _, alert := range trend.NewlyCritical {
+			fmt.Fprintf(&sb, "• %s: <%s|%s>\n", alert.Repository, alert.URL, alert.Description())
+		}
+
+		text := strings.TrimRight(sb.String(), "\n")
+		threadMsgs = append([]*Message{{
+			Channel: channel,
+			Text:    text,
+			Blocks:  textBlocks(blockKit, text),
+		}}, threadMsgs...)
 	}
 
 	return summary, threadMsgs
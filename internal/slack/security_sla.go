@@ -0,0 +1,30 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// CreateSecurityAlertEscalationMessage tells the team a security alert is past
+// its deadline, in the thread of the alert if it was posted.
+func CreateSecurityAlertEscalationMessage(channel, threadTimestamp string, alert gensql.SecurityAlert, days int, now time.Time) *Message {
+	open := int(now.Sub(alert.FirstSeenAt.Time).Hours() / 24)
+	alertType := strings.ReplaceAll(alert.AlertType, "_", " ")
+
+	text := fmt.Sprintf(":rotating_light: This %s %s in `%s` has been open for %d days, past the %d day deadline", alert.Severity, alertType, alert.Repository, open, days)
+	if threadTimestamp == "" {
+		text = fmt.Sprintf(":rotating_light: <%s|A %s %s> in `%s` has been open for %d days, past the %d day deadline", alert.AlertUrl, alert.Severity, alertType, alert.Repository, open, days)
+		if alert.Summary != "" {
+			text += fmt.Sprintf(": %s", alert.Summary)
+		}
+	}
+
+	return &Message{
+		Channel:         channel,
+		Text:            text,
+		ThreadTimestamp: threadTimestamp,
+	}
+}
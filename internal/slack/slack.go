@@ -44,16 +44,18 @@ func getColorBySeverity(severity github.SeverityType) string {
 }
 
 type Attachment struct {
-	Text       string `json:"text"`
-	Type       string `json:"type,omitempty"`
-	Color      string `json:"color"`
-	Footer     string `json:"footer,omitempty"`
-	FooterIcon string `json:"footer_icon,omitempty"`
+	Text       string  `json:"text"`
+	Type       string  `json:"type,omitempty"`
+	Color      string  `json:"color"`
+	Footer     string  `json:"footer,omitempty"`
+	FooterIcon string  `json:"footer_icon,omitempty"`
+	Blocks     []Block `json:"blocks,omitempty"`
 }
 
 type Message struct {
 	Channel         string       `json:"channel"`
 	Text            string       `json:"text"`
+	Blocks          []Block      `json:"blocks,omitempty"` // Blocks are set for teams using Block Kit, with Text as the fallback.
 	Attachments     []Attachment `json:"attachments,omitempty"`
 	ThreadTimestamp string       `json:"thread_ts,omitempty"` // ThreadTimestamp is used to reply to a thread in Slack.
 	Timestamp       string       `json:"ts,omitempty"`        // Timestamp is used to update a message in Slack.
@@ -62,8 +64,9 @@ type Message struct {
 }
 
 type Channel struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	IsPrivate bool   `json:"is_private"`
 }
 
 type User struct {
@@ -129,6 +132,17 @@ type Slacker interface {
 	PostWorkflowReaction(log *slog.Logger, event github.Event, channel, timestamp string) error
 }
 
+// ChannelInspector looks up whether channels are private.
+type ChannelInspector interface {
+	IsPrivateChannel(channel string) (bool, error)
+}
+
+// DirectMessenger sends direct messages to users.
+type DirectMessenger interface {
+	OpenDM(slackUserID string) (string, error)
+	PostMessage(payload []byte) (MessageResponse, error)
+}
+
 type Client struct {
 	log        *slog.Logger
 	httpClient *http.Client
@@ -186,7 +200,9 @@ func (c Client) getRequest(apiMethod, channel, timestamp string) (string, error)
 
 	query := req.URL.Query()
 	query.Set("channel", channel)
-	query.Set("timestamp", timestamp)
+	if timestamp != "" {
+		query.Set("timestamp", timestamp)
+	}
 	req.URL.RawQuery = query.Encode()
 
 	resp, err := c.httpDoWithRetry(req, 3)
@@ -6,17 +6,37 @@ import (
 	"github.com/navikt/ghep/internal/github"
 )
 
-func CreateWorkflowMessage(channel string, event github.Event) *Message {
+// CreateWorkflowMessage creates the message for a failed workflow, with a
+// button for re-running the failed jobs if the workspace is interactive.
+func CreateWorkflowMessage(channel, team string, interactive, blockKit bool, event github.Event) *Message {
 	text := fmt.Sprintf(":x: %s has a workflow with status `%s`, triggered by %s.\n<%s|#%d %s>", event.Repository.ToSlack(), event.Workflow.Conclusion, event.Sender.ToSlack(), event.Workflow.URL, event.Workflow.RunNumber, event.Workflow.Title)
 
 	var attachments []Attachment
+	blocks := []Block{sectionBlock(text)}
 	if event.Workflow.FailedJob.Name != "" {
+		failedJob := fmt.Sprintf("The job <%s|%s>[%s] failed in step `%s`", event.Workflow.FailedJob.URL, event.Workflow.FailedJob.Name, event.Workflow.HeadBranch, event.Workflow.FailedJob.Step)
+		footer := fmt.Sprintf("<%s|%s>", event.Repository.URL, event.Repository.FullName)
 		attachments = append(attachments, Attachment{
-			Text:       fmt.Sprintf("The job <%s|%s>[%s] failed in step `%s`", event.Workflow.FailedJob.URL, event.Workflow.FailedJob.Name, event.Workflow.HeadBranch, event.Workflow.FailedJob.Step),
+			Text:       failedJob,
 			Color:      ColorFailed,
-			Footer:     fmt.Sprintf("<%s|%s>", event.Repository.URL, event.Repository.FullName),
+			Footer:     footer,
 			FooterIcon: "https://slack.github.com/static/img/favicon-neutral.png",
 		})
+		blocks = append(blocks, sectionBlock(failedJob), footerBlock(0, neutralGithubIcon, footer))
+	}
+
+	if interactive && event.Workflow.ID > 0 {
+		actions := workflowActions(team, event)
+		attachments = append(attachments, actions)
+		blocks = append(blocks, actions.Blocks...)
+	}
+
+	if blockKit {
+		return &Message{
+			Channel: channel,
+			Text:    text,
+			Blocks:  blocks,
+		}
 	}
 
 	return &Message{
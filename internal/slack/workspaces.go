@@ -0,0 +1,242 @@
+package slack
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+)
+
+// Workspaces holds a Client for each Slack workspace teams are configured
+// with. The default workspace has an empty name, and uses SLACK_TOKEN.
+type Workspaces struct {
+	clients map[string]Client
+	// signingSecrets verify interaction requests, and are only set for workspaces with interactivity enabled.
+	signingSecrets map[string]string
+}
+
+// NewWorkspaces creates a client for the default workspace, and for every
+// named workspace used by teams, personal digests or personal notifications. The token for a named
+// workspace is read from SLACK_TOKEN_<NAME>, or from the file in
+// SLACK_TOKEN_<NAME>_FILE. The optional signing secret enabling buttons on
+// messages is read from SLACK_SIGNING_SECRET_<NAME> the same way.
+func NewWorkspaces(log *slog.Logger, teams map[string]github.Team, personalDigestUsers []github.PersonalDigestUserEntry, personalNotifications []github.PersonalNotifications) (Workspaces, error) {
+	names := map[string]bool{"": true}
+	for _, team := range teams {
+		names[team.SlackWorkspace] = true
+	}
+	for _, user := range personalDigestUsers {
+		names[user.SlackWorkspace] = true
+	}
+	for _, user := range personalNotifications {
+		names[user.SlackWorkspace] = true
+	}
+
+	workspaces := Workspaces{
+		clients:        make(map[string]Client, len(names)),
+		signingSecrets: map[string]string{},
+	}
+	for name := range names {
+		key := TokenEnv(name)
+		token, err := tokenFromEnv(key)
+		if err != nil {
+			return Workspaces{}, fmt.Errorf("reading token for Slack workspace %q: %w", name, err)
+		}
+
+		log := log
+		if name != "" {
+			log = log.With("workspace", name)
+		}
+
+		client, err := New(log, token)
+		if err != nil {
+			return Workspaces{}, fmt.Errorf("creating client for Slack workspace %q (%s): %w", name, key, err)
+		}
+
+		workspaces.clients[name] = client
+
+		signingSecret, err := tokenFromEnv(SigningSecretEnv(name))
+		if err != nil {
+			return Workspaces{}, fmt.Errorf("reading signing secret for Slack workspace %q: %w", name, err)
+		}
+
+		if signingSecret != "" {
+			workspaces.signingSecrets[name] = signingSecret
+		}
+	}
+
+	return workspaces, nil
+}
+
+// TokenEnv returns the environment variable holding the token for a workspace.
+func TokenEnv(workspace string) string {
+	return workspaceEnv("SLACK_TOKEN", workspace)
+}
+
+// SigningSecretEnv returns the environment variable holding the signing secret for a workspace.
+func SigningSecretEnv(workspace string) string {
+	return workspaceEnv("SLACK_SIGNING_SECRET", workspace)
+}
+
+func workspaceEnv(prefix, workspace string) string {
+	if workspace == "" {
+		return prefix
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, workspace)
+
+	return prefix + "_" + strings.ToUpper(name)
+}
+
+func tokenFromEnv(key string) (string, error) {
+	if token := os.Getenv(key); token != "" {
+		return token, nil
+	}
+
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+
+	token, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(token)), nil
+}
+
+// Names returns the names of all workspaces, starting with the default workspace.
+func (w Workspaces) Names() []string {
+	return slices.Sorted(maps.Keys(w.clients))
+}
+
+// Get returns the client for a named workspace, where an empty name is the default workspace.
+func (w Workspaces) Get(name string) (Client, bool) {
+	client, ok := w.clients[name]
+	return client, ok
+}
+
+// ForTeam returns the client for the workspace the team is configured with.
+func (w Workspaces) ForTeam(team github.Team) Client {
+	return w.clients[team.SlackWorkspace]
+}
+
+// Interactive returns the workspaces with a signing secret, where messages can have buttons.
+func (w Workspaces) Interactive() map[string]bool {
+	interactive := make(map[string]bool, len(w.signingSecrets))
+	for name := range w.signingSecrets {
+		interactive[name] = true
+	}
+
+	return interactive
+}
+
+// VerifyInteraction returns the workspace whose signing secret the request is
+// signed with, or an error if it is not signed by any of them.
+func (w Workspaces) VerifyInteraction(timestamp, signature string, body []byte, now time.Time) (string, error) {
+	if len(w.signingSecrets) == 0 {
+		return "", fmt.Errorf("no Slack signing secrets configured")
+	}
+
+	var err error
+	for _, name := range slices.Sorted(maps.Keys(w.signingSecrets)) {
+		if err = VerifySignature(w.signingSecrets[name], timestamp, signature, body, now); err == nil {
+			return name, nil
+		}
+	}
+
+	return "", err
+}
+
+// Slackers returns every workspace as a Slacker, keyed by workspace name.
+func (w Workspaces) Slackers() map[string]Slacker {
+	slackers := make(map[string]Slacker, len(w.clients))
+	for name, client := range w.clients {
+		slackers[name] = client
+	}
+
+	return slackers
+}
+
+// DirectMessengers returns every workspace as a DirectMessenger, keyed by workspace name.
+func (w Workspaces) DirectMessengers() map[string]DirectMessenger {
+	messengers := make(map[string]DirectMessenger, len(w.clients))
+	for name, client := range w.clients {
+		messengers[name] = client
+	}
+
+	return messengers
+}
+
+// EnsureChannels resolves channel names to IDs for each team, using the
+// workspace the team is configured with.
+func (w Workspaces) EnsureChannels(teams map[string]github.Team) error {
+	for name, client := range w.clients {
+		workspaceTeams := make(map[string]github.Team)
+		for slug, team := range teams {
+			if team.SlackWorkspace == name {
+				workspaceTeams[slug] = team
+			}
+		}
+
+		if len(workspaceTeams) == 0 {
+			continue
+		}
+
+		if err := client.EnsureChannels(workspaceTeams); err != nil {
+			return fmt.Errorf("workspace %q: %w", name, err)
+		}
+
+		maps.Copy(teams, workspaceTeams)
+	}
+
+	return nil
+}
+
+// PublicChannels returns the Slack channels each team posts to, by team name,
+// that anyone in the team's workspace can read.
+func (w Workspaces) PublicChannels(teams map[string]github.Team) (map[string][]string, error) {
+	publicChannels := make(map[string][]string)
+	for name, client := range w.clients {
+		var public map[string]bool
+		for slug, team := range teams {
+			if team.SlackWorkspace != name {
+				continue
+			}
+
+			if public == nil {
+				var err error
+				if public, err = client.listPublicChannels(); err != nil {
+					return nil, fmt.Errorf("workspace %q: %w", name, err)
+				}
+			}
+
+			channels := []string{team.Config.ExternalContributorsChannel}
+			for _, source := range team.Sources {
+				if source.Notifier != github.NotifierMSTeams {
+					channels = append(channels, source.Channel)
+				}
+			}
+
+			for _, channel := range channels {
+				if channel != "" && public[strings.TrimPrefix(channel, "#")] && !slices.Contains(publicChannels[slug], channel) {
+					publicChannels[slug] = append(publicChannels[slug], channel)
+				}
+			}
+		}
+	}
+
+	return publicChannels, nil
+}
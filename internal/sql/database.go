@@ -19,19 +19,40 @@ var embedMigrations embed.FS
 type Database interface {
 	AddTeamMember(ctx context.Context, params gensql.AddTeamMemberParams) error
 	AddTeamRepository(ctx context.Context, params gensql.AddTeamRepositoryParams) error
-	CreateRepository(ctx context.Context, name string) (int32, error)
+	ClaimPersonalNotification(ctx context.Context, arg gensql.ClaimPersonalNotificationParams) (int64, error)
+	ClaimSecretAuthorNotification(ctx context.Context, arg gensql.ClaimSecretAuthorNotificationParams) (bool, error)
+	CreateRepository(ctx context.Context, arg gensql.CreateRepositoryParams) (int32, error)
+	CreateSecurityAlert(ctx context.Context, arg gensql.CreateSecurityAlertParams) error
+	CreateSlackID(ctx context.Context, arg gensql.CreateSlackIDParams) error
 	CreateSlackMessage(ctx context.Context, arg gensql.CreateSlackMessageParams) error
+	CreateTeamEvent(ctx context.Context, arg gensql.CreateTeamEventParams) error
 	CreateUser(ctx context.Context, login string) error
+	DeleteOrgSecurityAlert(ctx context.Context, arg gensql.DeleteOrgSecurityAlertParams) error
+	DeleteSecurityAlert(ctx context.Context, arg gensql.DeleteSecurityAlertParams) error
+	DeleteSlackID(ctx context.Context, arg gensql.DeleteSlackIDParams) error
 	ExistsUser(ctx context.Context, login string) (bool, error)
-	GetRepository(ctx context.Context, name string) (gensql.Repository, error)
+	GetPersonalNotifications(ctx context.Context, login string) (gensql.PersonalNotification, error)
+	GetRepository(ctx context.Context, arg gensql.GetRepositoryParams) (gensql.Repository, error)
 	GetSlackMessage(ctx context.Context, arg gensql.GetSlackMessageParams) (gensql.GetSlackMessageRow, error)
 	GetTeamMember(ctx context.Context, params gensql.GetTeamMemberParams) (string, error)
 	GetUserByEmail(ctx context.Context, email string) (string, error)
-	GetUserSlackID(ctx context.Context, login string) (string, error)
+	GetUserSlackID(ctx context.Context, arg gensql.GetUserSlackIDParams) (string, error)
+	IncrementMuteSuppressed(ctx context.Context, id int64) error
+	ListActiveMutes(ctx context.Context, teamSlug string) ([]gensql.Mute, error)
+	ListRolledUpDependencyPullRequests(ctx context.Context, arg gensql.ListRolledUpDependencyPullRequestsParams) ([]gensql.DependencyPullRequest, error)
+	ListSlackIDs(ctx context.Context, workspace string) ([]gensql.ListSlackIDsRow, error)
 	ListSlackMessagesByEvent(ctx context.Context, arg gensql.ListSlackMessagesByEventParams) ([]gensql.ListSlackMessagesByEventRow, error)
+	ListTeamMembers(ctx context.Context, teamSlug string) ([]string, error)
+	ListTeamRepositories(ctx context.Context, teamSlug string) ([]gensql.Repository, error)
+	MarkDependencyPullRequestCIFailed(ctx context.Context, arg gensql.MarkDependencyPullRequestCIFailedParams) (gensql.DependencyPullRequest, error)
+	QueueEvent(ctx context.Context, arg gensql.QueueEventParams) error
+	ReleasePersonalNotification(ctx context.Context, arg gensql.ReleasePersonalNotificationParams) error
+	ReleaseSecretAuthorNotification(ctx context.Context, arg gensql.ReleaseSecretAuthorNotificationParams) error
 	RemoveTeamMember(ctx context.Context, arg gensql.RemoveTeamMemberParams) error
 	RemoveTeamRepository(ctx context.Context, arg gensql.RemoveTeamRepositoryParams) error
 	UpdateRepository(ctx context.Context, arg gensql.UpdateRepositoryParams) error
+	UpsertDependencyPullRequest(ctx context.Context, arg gensql.UpsertDependencyPullRequestParams) (gensql.DependencyPullRequest, error)
+	UpsertOrgSecurityAlert(ctx context.Context, arg gensql.UpsertOrgSecurityAlertParams) error
 	UpsertUserCommitCount(ctx context.Context, arg gensql.UpsertUserCommitCountParams) error
 }
 
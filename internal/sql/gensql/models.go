@@ -4,7 +4,125 @@
 
 package gensql
 
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type DependencyPullRequest struct {
+	TeamSlug   string
+	Repository string
+	Number     int32
+	Title      string
+	Url        string
+	Ecosystem  string
+	State      string
+	CiFailed   bool
+	UpdatedAt  pgtype.Timestamptz
+	RolledUpAt pgtype.Timestamptz
+}
+
+type Mute struct {
+	ID         int64
+	TeamSlug   string
+	Repository string
+	MutedUntil pgtype.Timestamptz
+	CreatedBy  string
+	CreatedAt  pgtype.Timestamptz
+	SourceType string
+	Reason     string
+	Suppressed int32
+	ReportedAt pgtype.Timestamptz
+}
+
+type OrgSecurityAlert struct {
+	Org           string
+	AlertUrl      string
+	AlertType     string
+	Repository    string
+	RepositoryUrl string
+	Severity      string
+	Description   string
+	CreatedAt     pgtype.Timestamptz
+}
+
+type OrgSecurityAlertSync struct {
+	Org      string
+	SyncedAt pgtype.Timestamptz
+}
+
+type PersonalNotification struct {
+	Login          string
+	SlackWorkspace string
+	Enabled        bool
+	Types          []string
+	QuietFrom      string
+	QuietTo        string
+	Timezone       string
+	UpdatedAt      pgtype.Timestamptz
+}
+
+type QueuedEvent struct {
+	ID         int64
+	TeamSlug   string
+	Channel    string
+	SourceType string
+	EventType  string
+	EventID    string
+	Payload    []byte
+	QueuedAt   pgtype.Timestamptz
+}
+
 type Repository struct {
 	ID   int32
 	Name string
+	Org  string
+}
+
+type SecurityAlert struct {
+	TeamSlug    string
+	AlertUrl    string
+	AlertType   string
+	Repository  string
+	Severity    string
+	Summary     string
+	FirstSeenAt pgtype.Timestamptz
+	EscalatedAt pgtype.Timestamptz
+}
+
+type SecurityDigest struct {
+	ID       int64
+	TeamSlug string
+	SentAt   pgtype.Timestamptz
+}
+
+type SecurityDigestAlert struct {
+	DigestID int64
+	AlertUrl string
+	Critical bool
+}
+
+type SecurityDigestCount struct {
+	DigestID   int64
+	Repository string
+	AlertType  string
+	Severity   string
+	Count      int32
+}
+
+type SlackID struct {
+	Login     string
+	ID        string
+	Workspace string
+}
+
+type TeamEvent struct {
+	ID         int64
+	TeamSlug   string
+	Repository string
+	EventType  string
+	Title      string
+	Summary    string
+	Url        string
+	Author     string
+	CreatedAt  pgtype.Timestamptz
 }
@@ -9,42 +9,62 @@ import (
 	"context"
 )
 
+const AssignRepositoriesToOrg = `-- name: AssignRepositoriesToOrg :exec
+UPDATE repositories SET org = $1 WHERE org = ''
+`
+
+func (q *Queries) AssignRepositoriesToOrg(ctx context.Context, org string) error {
+	_, err := q.db.Exec(ctx, AssignRepositoriesToOrg, org)
+	return err
+}
+
 const CreateRepository = `-- name: CreateRepository :one
-INSERT INTO repositories (name) VALUES ($1)
-ON CONFLICT (name) DO NOTHING
+INSERT INTO repositories (org, name) VALUES ($1, $2)
+ON CONFLICT (org, name) DO NOTHING
 RETURNING id
 `
 
-func (q *Queries) CreateRepository(ctx context.Context, name string) (int32, error) {
-	row := q.db.QueryRow(ctx, CreateRepository, name)
+type CreateRepositoryParams struct {
+	Org  string
+	Name string
+}
+
+func (q *Queries) CreateRepository(ctx context.Context, arg CreateRepositoryParams) (int32, error) {
+	row := q.db.QueryRow(ctx, CreateRepository, arg.Org, arg.Name)
 	var id int32
 	err := row.Scan(&id)
 	return id, err
 }
 
 const GetRepository = `-- name: GetRepository :one
-SELECT id, name FROM repositories WHERE name = $1
+SELECT id, name, org FROM repositories WHERE org = $1 AND name = $2
 `
 
-func (q *Queries) GetRepository(ctx context.Context, name string) (Repository, error) {
-	row := q.db.QueryRow(ctx, GetRepository, name)
+type GetRepositoryParams struct {
+	Org  string
+	Name string
+}
+
+func (q *Queries) GetRepository(ctx context.Context, arg GetRepositoryParams) (Repository, error) {
+	row := q.db.QueryRow(ctx, GetRepository, arg.Org, arg.Name)
 	var i Repository
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.Org)
 	return i, err
 }
 
 const UpdateRepository = `-- name: UpdateRepository :exec
 UPDATE repositories
 SET name = $1
-WHERE name = $2
+WHERE org = $2 AND name = $3
 `
 
 type UpdateRepositoryParams struct {
 	Name    string
+	Org     string
 	OldName string
 }
 
 func (q *Queries) UpdateRepository(ctx context.Context, arg UpdateRepositoryParams) error {
-	_, err := q.db.Exec(ctx, UpdateRepository, arg.Name, arg.OldName)
+	_, err := q.db.Exec(ctx, UpdateRepository, arg.Name, arg.Org, arg.OldName)
 	return err
 }
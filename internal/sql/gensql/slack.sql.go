@@ -10,17 +10,18 @@ import (
 )
 
 const CreateSlackID = `-- name: CreateSlackID :exec
-INSERT INTO slack_ids (login, id) VALUES ($1, $2)
-ON CONFLICT (login) DO UPDATE SET id = EXCLUDED.id
+INSERT INTO slack_ids (workspace, login, id) VALUES ($1, $2, $3)
+ON CONFLICT (workspace, login) DO UPDATE SET id = EXCLUDED.id
 `
 
 type CreateSlackIDParams struct {
-	Login string
-	ID    string
+	Workspace string
+	Login     string
+	ID        string
 }
 
 func (q *Queries) CreateSlackID(ctx context.Context, arg CreateSlackIDParams) error {
-	_, err := q.db.Exec(ctx, CreateSlackID, arg.Login, arg.ID)
+	_, err := q.db.Exec(ctx, CreateSlackID, arg.Workspace, arg.Login, arg.ID)
 	return err
 }
 
@@ -50,6 +51,38 @@ func (q *Queries) CreateSlackMessage(ctx context.Context, arg CreateSlackMessage
 	return err
 }
 
+const DeleteSlackID = `-- name: DeleteSlackID :exec
+DELETE FROM slack_ids WHERE workspace = $1 AND login = $2
+`
+
+type DeleteSlackIDParams struct {
+	Workspace string
+	Login     string
+}
+
+func (q *Queries) DeleteSlackID(ctx context.Context, arg DeleteSlackIDParams) error {
+	_, err := q.db.Exec(ctx, DeleteSlackID, arg.Workspace, arg.Login)
+	return err
+}
+
+const GetLoginBySlackID = `-- name: GetLoginBySlackID :one
+SELECT login
+FROM slack_ids
+WHERE workspace = $1 AND id = $2
+`
+
+type GetLoginBySlackIDParams struct {
+	Workspace string
+	ID        string
+}
+
+func (q *Queries) GetLoginBySlackID(ctx context.Context, arg GetLoginBySlackIDParams) (string, error) {
+	row := q.db.QueryRow(ctx, GetLoginBySlackID, arg.Workspace, arg.ID)
+	var login string
+	err := row.Scan(&login)
+	return login, err
+}
+
 const GetSlackMessage = `-- name: GetSlackMessage :one
 SELECT thread_ts, channel, payload
 FROM slack_messages
@@ -78,16 +111,53 @@ func (q *Queries) GetSlackMessage(ctx context.Context, arg GetSlackMessageParams
 const GetUserSlackID = `-- name: GetUserSlackID :one
 SELECT id
 FROM slack_ids
-WHERE login = $1
+WHERE workspace = $1 AND login = $2
 `
 
-func (q *Queries) GetUserSlackID(ctx context.Context, login string) (string, error) {
-	row := q.db.QueryRow(ctx, GetUserSlackID, login)
+type GetUserSlackIDParams struct {
+	Workspace string
+	Login     string
+}
+
+func (q *Queries) GetUserSlackID(ctx context.Context, arg GetUserSlackIDParams) (string, error) {
+	row := q.db.QueryRow(ctx, GetUserSlackID, arg.Workspace, arg.Login)
 	var id string
 	err := row.Scan(&id)
 	return id, err
 }
 
+const ListSlackIDs = `-- name: ListSlackIDs :many
+SELECT login, id
+FROM slack_ids
+WHERE workspace = $1
+ORDER BY login
+`
+
+type ListSlackIDsRow struct {
+	Login string
+	ID    string
+}
+
+func (q *Queries) ListSlackIDs(ctx context.Context, workspace string) ([]ListSlackIDsRow, error) {
+	rows, err := q.db.Query(ctx, ListSlackIDs, workspace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSlackIDsRow
+	for rows.Next() {
+		var i ListSlackIDsRow
+		if err := rows.Scan(&i.Login, &i.ID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const ListSlackMessagesByEvent = `-- name: ListSlackMessagesByEvent :many
 SELECT thread_ts, channel, payload
 FROM slack_messages
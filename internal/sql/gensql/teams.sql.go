@@ -39,13 +39,30 @@ func (q *Queries) AddTeamRepository(ctx context.Context, arg AddTeamRepositoryPa
 	return err
 }
 
+const AssignTeamsToOrg = `-- name: AssignTeamsToOrg :exec
+UPDATE teams SET org = $1 WHERE org = ''
+`
+
+func (q *Queries) AssignTeamsToOrg(ctx context.Context, org string) error {
+	_, err := q.db.Exec(ctx, AssignTeamsToOrg, org)
+	return err
+}
+
 const CreateTeam = `-- name: CreateTeam :exec
-INSERT INTO teams (slug) VALUES ($1)
-ON CONFLICT (slug) DO NOTHING
+INSERT INTO teams (slug, org, github_slug) VALUES ($1, $2, $3)
+ON CONFLICT (slug) DO UPDATE
+SET org = EXCLUDED.org,
+    github_slug = EXCLUDED.github_slug
 `
 
-func (q *Queries) CreateTeam(ctx context.Context, slug string) error {
-	_, err := q.db.Exec(ctx, CreateTeam, slug)
+type CreateTeamParams struct {
+	Slug       string
+	Org        string
+	GithubSlug string
+}
+
+func (q *Queries) CreateTeam(ctx context.Context, arg CreateTeamParams) error {
+	_, err := q.db.Exec(ctx, CreateTeam, arg.Slug, arg.Org, arg.GithubSlug)
 	return err
 }
 
@@ -69,6 +86,22 @@ func (q *Queries) GetTeam(ctx context.Context, slug string) (string, error) {
 	return slug_2, err
 }
 
+const GetTeamByGithubSlug = `-- name: GetTeamByGithubSlug :one
+SELECT slug FROM teams WHERE org = $1 AND github_slug = $2
+`
+
+type GetTeamByGithubSlugParams struct {
+	Org        string
+	GithubSlug string
+}
+
+func (q *Queries) GetTeamByGithubSlug(ctx context.Context, arg GetTeamByGithubSlugParams) (string, error) {
+	row := q.db.QueryRow(ctx, GetTeamByGithubSlug, arg.Org, arg.GithubSlug)
+	var slug string
+	err := row.Scan(&slug)
+	return slug, err
+}
+
 const GetTeamMember = `-- name: GetTeamMember :one
 SELECT user_login FROM team_members WHERE team_slug = $1 AND user_login = $2
 `
@@ -162,7 +195,7 @@ func (q *Queries) ListTeamMembers(ctx context.Context, teamSlug string) ([]strin
 }
 
 const ListTeamRepositories = `-- name: ListTeamRepositories :many
-SELECT r.id, r.name
+SELECT r.id, r.name, r.org
 FROM team_repositories tr
 JOIN repositories r ON tr.repository_id = r.id
 WHERE tr.team_slug = $1
@@ -178,7 +211,7 @@ func (q *Queries) ListTeamRepositories(ctx context.Context, teamSlug string) ([]
 	var items []Repository
 	for rows.Next() {
 		var i Repository
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(&i.ID, &i.Name, &i.Org); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -218,12 +251,17 @@ SELECT t.slug
 FROM teams t
 JOIN team_repositories tr ON t.slug = tr.team_slug
 JOIN repositories r ON tr.repository_id = r.id
-WHERE r.name = $1
+WHERE r.org = $1 AND r.name = $2
 ORDER BY t.slug
 `
 
-func (q *Queries) ListTeamsByRepository(ctx context.Context, name string) ([]string, error) {
-	rows, err := q.db.Query(ctx, ListTeamsByRepository, name)
+type ListTeamsByRepositoryParams struct {
+	Org  string
+	Name string
+}
+
+func (q *Queries) ListTeamsByRepository(ctx context.Context, arg ListTeamsByRepositoryParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, ListTeamsByRepository, arg.Org, arg.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -242,6 +280,35 @@ func (q *Queries) ListTeamsByRepository(ctx context.Context, name string) ([]str
 	return items, nil
 }
 
+const ListTeamsInOrg = `-- name: ListTeamsInOrg :many
+SELECT slug, github_slug FROM teams WHERE org = $1 ORDER BY slug
+`
+
+type ListTeamsInOrgRow struct {
+	Slug       string
+	GithubSlug string
+}
+
+func (q *Queries) ListTeamsInOrg(ctx context.Context, org string) ([]ListTeamsInOrgRow, error) {
+	rows, err := q.db.Query(ctx, ListTeamsInOrg, org)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTeamsInOrgRow
+	for rows.Next() {
+		var i ListTeamsInOrgRow
+		if err := rows.Scan(&i.Slug, &i.GithubSlug); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const RemoveTeamMember = `-- name: RemoveTeamMember :exec
 DELETE FROM team_members WHERE team_slug = $1 AND user_login = $2
 `
@@ -258,15 +325,16 @@ func (q *Queries) RemoveTeamMember(ctx context.Context, arg RemoveTeamMemberPara
 
 const RemoveTeamRepository = `-- name: RemoveTeamRepository :exec
 DELETE FROM team_repositories
-WHERE team_slug = $1 AND repository_id = (SELECT id FROM repositories WHERE name = $2)
+WHERE team_slug = $1 AND repository_id = (SELECT id FROM repositories WHERE org = $2 AND name = $3)
 `
 
 type RemoveTeamRepositoryParams struct {
 	TeamSlug string
+	Org      string
 	Name     string
 }
 
 func (q *Queries) RemoveTeamRepository(ctx context.Context, arg RemoveTeamRepositoryParams) error {
-	_, err := q.db.Exec(ctx, RemoveTeamRepository, arg.TeamSlug, arg.Name)
+	_, err := q.db.Exec(ctx, RemoveTeamRepository, arg.TeamSlug, arg.Org, arg.Name)
 	return err
 }
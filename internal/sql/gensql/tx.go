@@ -0,0 +1,33 @@
+package gensql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// InTx runs fn with queries in a transaction, which is committed if fn returns
+// nil and rolled back otherwise. It is not generated by sqlc, as sqlc leaves
+// transactions to the caller.
+func (q *Queries) InTx(ctx context.Context, fn func(q *Queries) error) error {
+	beginner, ok := q.db.(interface {
+		Begin(ctx context.Context) (pgx.Tx, error)
+	})
+	if !ok {
+		return errors.New("database does not support transactions")
+	}
+
+	tx, err := beginner.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	// Rolling back is a no-op once the transaction is committed
+	defer tx.Rollback(ctx)
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Slack user IDs differ between workspaces, so they are stored per workspace.
+-- Existing IDs belong to the default workspace, which has an empty name.
+ALTER TABLE slack_ids ADD COLUMN workspace text NOT NULL DEFAULT '';
+ALTER TABLE slack_ids DROP CONSTRAINT slack_ids_pkey;
+ALTER TABLE slack_ids ADD PRIMARY KEY (workspace, login);
+
+-- +goose Down
+DELETE FROM slack_ids WHERE workspace <> '';
+ALTER TABLE slack_ids DROP CONSTRAINT slack_ids_pkey;
+ALTER TABLE slack_ids ADD PRIMARY KEY (login);
+ALTER TABLE slack_ids DROP COLUMN workspace;
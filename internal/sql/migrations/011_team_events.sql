@@ -0,0 +1,18 @@
+-- +goose Up
+-- History of notable events routed to each team, used for the Atom feeds.
+CREATE TABLE team_events (
+    id         BIGSERIAL   PRIMARY KEY,
+    team_slug  TEXT        NOT NULL,
+    repository TEXT        NOT NULL,
+    event_type TEXT        NOT NULL,
+    title      TEXT        NOT NULL,
+    summary    TEXT        NOT NULL DEFAULT '',
+    url        TEXT        NOT NULL,
+    author     TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX team_events_team_slug_created_at_idx ON team_events (team_slug, created_at DESC);
+
+-- +goose Down
+DROP TABLE team_events;
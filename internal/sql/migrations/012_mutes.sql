@@ -0,0 +1,15 @@
+-- +goose Up
+-- Repositories a team has muted from Slack, layered over ignoreRepositories in teams.yaml.
+CREATE TABLE mutes (
+    id          BIGSERIAL   PRIMARY KEY,
+    team_slug   TEXT        NOT NULL,
+    repository  TEXT        NOT NULL,
+    muted_until TIMESTAMPTZ NOT NULL,
+    created_by  TEXT        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX mutes_team_slug_repository_idx ON mutes (team_slug, repository);
+
+-- +goose Down
+DROP TABLE mutes;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- An empty repository mutes the source type in all repositories, and an empty
+-- source type mutes all events from the repository.
+ALTER TABLE mutes
+    ADD COLUMN source_type TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN reason      TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN suppressed  INTEGER     NOT NULL DEFAULT 0,
+    ADD COLUMN reported_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE mutes
+    DROP COLUMN source_type,
+    DROP COLUMN reason,
+    DROP COLUMN suppressed,
+    DROP COLUMN reported_at;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Preferences for real-time DMs set from Slack, overriding personal-notifications in teams.yaml.
+CREATE TABLE personal_notifications (
+    login           TEXT        PRIMARY KEY REFERENCES users(login) ON DELETE CASCADE,
+    slack_workspace TEXT        NOT NULL DEFAULT '',
+    enabled         BOOLEAN     NOT NULL,
+    types           TEXT[]      NOT NULL DEFAULT '{}',
+    quiet_from      TEXT        NOT NULL DEFAULT '',
+    quiet_to        TEXT        NOT NULL DEFAULT '',
+    timezone        TEXT        NOT NULL DEFAULT '',
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- DMs already sent for a webhook delivery, so redeliveries from Github are not sent again.
+CREATE TABLE personal_notifications_sent (
+    delivery_id TEXT        NOT NULL,
+    login       TEXT        NOT NULL,
+    sent_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (delivery_id, login)
+);
+
+-- +goose Down
+DROP TABLE personal_notifications_sent;
+DROP TABLE personal_notifications;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Messages held back outside a team's or source's delivery window, posted as a
+-- summary when the window opens.
+CREATE TABLE queued_events (
+    id          BIGSERIAL   PRIMARY KEY,
+    team_slug   TEXT        NOT NULL,
+    channel     TEXT        NOT NULL,
+    source_type TEXT        NOT NULL,
+    event_type  TEXT        NOT NULL,
+    event_id    TEXT        NOT NULL DEFAULT '',
+    payload     JSON        NOT NULL,
+    queued_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX queued_events_team_slug_channel_idx ON queued_events (team_slug, channel);
+
+-- +goose Down
+DROP TABLE queued_events;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Open security alerts per team, escalated when left open past the team's SLA.
+CREATE TABLE security_alerts (
+    team_slug     TEXT        NOT NULL,
+    alert_url     TEXT        NOT NULL,
+    alert_type    TEXT        NOT NULL,
+    repository    TEXT        NOT NULL,
+    severity      TEXT        NOT NULL,
+    summary       TEXT        NOT NULL DEFAULT '',
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    escalated_at  TIMESTAMPTZ,
+    PRIMARY KEY (team_slug, alert_url)
+);
+
+-- +goose Down
+DROP TABLE security_alerts;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- What each security digest saw, so the next digest can show trends.
+CREATE TABLE security_digests (
+    id        BIGSERIAL   PRIMARY KEY,
+    team_slug TEXT        NOT NULL,
+    sent_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX security_digests_team_slug_sent_at_idx ON security_digests (team_slug, sent_at);
+
+CREATE TABLE security_digest_counts (
+    digest_id  BIGINT  NOT NULL REFERENCES security_digests(id) ON DELETE CASCADE,
+    repository TEXT    NOT NULL,
+    alert_type TEXT    NOT NULL,
+    severity   TEXT    NOT NULL,
+    count      INTEGER NOT NULL,
+    PRIMARY KEY (digest_id, repository, alert_type, severity)
+);
+
+-- The open alerts, to tell which were opened and closed since the previous digest.
+CREATE TABLE security_digest_alerts (
+    digest_id BIGINT  NOT NULL REFERENCES security_digests(id) ON DELETE CASCADE,
+    alert_url TEXT    NOT NULL,
+    critical  BOOLEAN NOT NULL,
+    PRIMARY KEY (digest_id, alert_url)
+);
+
+-- +goose Down
+DROP TABLE security_digest_alerts;
+DROP TABLE security_digest_counts;
+DROP TABLE security_digests;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Open security alerts per organization, shared by all teams' security digests.
+-- Refreshed from the Github API when stale, and kept up to date from webhook events in between.
+CREATE TABLE org_security_alerts (
+    org            TEXT        NOT NULL,
+    alert_url      TEXT        NOT NULL,
+    alert_type     TEXT        NOT NULL,
+    repository     TEXT        NOT NULL,
+    repository_url TEXT        NOT NULL,
+    severity       TEXT        NOT NULL DEFAULT '',
+    description    TEXT        NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (org, alert_url)
+);
+
+CREATE TABLE org_security_alert_syncs (
+    org       TEXT        PRIMARY KEY,
+    synced_at TIMESTAMPTZ NOT NULL
+);
+
+-- +goose Down
+DROP TABLE org_security_alert_syncs;
+DROP TABLE org_security_alerts;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Dependabot and Renovate pull requests collected for a team's daily roll-up,
+-- instead of posting each of them. rolled_up_at is when the roll-up they were
+-- last posted in was sent.
+CREATE TABLE dependency_pull_requests (
+    team_slug    TEXT        NOT NULL,
+    repository   TEXT        NOT NULL,
+    number       INTEGER     NOT NULL,
+    title        TEXT        NOT NULL,
+    url          TEXT        NOT NULL,
+    ecosystem    TEXT        NOT NULL,
+    state        TEXT        NOT NULL,
+    ci_failed    BOOLEAN     NOT NULL DEFAULT false,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    rolled_up_at TIMESTAMPTZ,
+    PRIMARY KEY (team_slug, repository, number)
+);
+
+CREATE INDEX dependency_pull_requests_team_slug_rolled_up_at_idx ON dependency_pull_requests (team_slug, rolled_up_at);
+
+-- +goose Down
+DROP TABLE dependency_pull_requests;
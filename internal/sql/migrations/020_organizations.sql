@@ -0,0 +1,26 @@
+-- +goose Up
+-- Teams and repositories belong to an organization, instead of prefixing the
+-- names of those outside GITHUB_ORG with "org/". The organization is stored
+-- in lower case. Rows stored before ghep supported more than one organization
+-- get GITHUB_ORG on startup.
+ALTER TABLE teams ADD COLUMN org TEXT NOT NULL DEFAULT '';
+ALTER TABLE teams ADD COLUMN github_slug TEXT NOT NULL DEFAULT '';
+
+UPDATE teams SET org = lower(split_part(slug, '/', 1)), github_slug = split_part(slug, '/', 2) WHERE slug LIKE '%/%';
+UPDATE teams SET github_slug = slug WHERE github_slug = '';
+
+ALTER TABLE repositories ADD COLUMN org TEXT NOT NULL DEFAULT '';
+
+UPDATE repositories SET org = lower(split_part(name, '/', 1)), name = split_part(name, '/', 2) WHERE name LIKE '%/%';
+
+ALTER TABLE repositories DROP CONSTRAINT repositories_name_key;
+ALTER TABLE repositories ADD CONSTRAINT repositories_org_name_key UNIQUE (org, name);
+
+-- +goose Down
+ALTER TABLE repositories DROP CONSTRAINT repositories_org_name_key;
+UPDATE repositories SET name = org || '/' || name WHERE org <> '';
+ALTER TABLE repositories ADD CONSTRAINT repositories_name_key UNIQUE (name);
+ALTER TABLE repositories DROP COLUMN org;
+
+ALTER TABLE teams DROP COLUMN github_slug;
+ALTER TABLE teams DROP COLUMN org;
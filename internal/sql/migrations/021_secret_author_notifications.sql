@@ -0,0 +1,13 @@
+-- +goose Up
+-- Teams that have noted the DM to a secret's author in their alert threads.
+-- The author is sent the DM once per alert, by the first team, and
+-- redeliveries of the alert are skipped.
+CREATE TABLE secret_author_notifications (
+    alert_url   TEXT        NOT NULL,
+    team_slug   TEXT        NOT NULL,
+    notified_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (alert_url, team_slug)
+);
+
+-- +goose Down
+DROP TABLE secret_author_notifications;
@@ -0,0 +1,30 @@
+-- name: UpsertDependencyPullRequest :one
+INSERT INTO dependency_pull_requests (team_slug, repository, number, title, url, ecosystem, state)
+VALUES (@team_slug, @repository, @number, @title, @url, @ecosystem, @state)
+ON CONFLICT (team_slug, repository, number) DO UPDATE
+  SET title = EXCLUDED.title,
+      state = EXCLUDED.state,
+      ci_failed = CASE WHEN @reset_ci::boolean THEN false ELSE dependency_pull_requests.ci_failed END,
+      updated_at = now()
+RETURNING team_slug, repository, number, title, url, ecosystem, state, ci_failed, updated_at, rolled_up_at;
+
+-- name: MarkDependencyPullRequestCIFailed :one
+UPDATE dependency_pull_requests
+SET ci_failed = true, updated_at = now()
+WHERE team_slug = @team_slug AND repository = @repository AND number = @number AND NOT ci_failed
+RETURNING team_slug, repository, number, title, url, ecosystem, state, ci_failed, updated_at, rolled_up_at;
+
+-- name: RollUpDependencyPullRequests :exec
+UPDATE dependency_pull_requests
+SET rolled_up_at = @rolled_up_at
+WHERE team_slug = @team_slug AND (rolled_up_at IS NULL OR (state = 'open' AND ci_failed));
+
+-- name: ListRolledUpDependencyPullRequests :many
+SELECT team_slug, repository, number, title, url, ecosystem, state, ci_failed, updated_at, rolled_up_at
+FROM dependency_pull_requests
+WHERE team_slug = @team_slug AND rolled_up_at = @rolled_up_at
+ORDER BY repository, ecosystem, number;
+
+-- name: DeleteClosedDependencyPullRequests :exec
+DELETE FROM dependency_pull_requests
+WHERE team_slug = @team_slug AND state <> 'open' AND rolled_up_at IS NOT NULL AND updated_at < @before;
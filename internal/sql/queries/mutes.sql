@@ -0,0 +1,32 @@
+-- name: CreateMute :one
+INSERT INTO mutes (team_slug, repository, source_type, muted_until, reason, created_by)
+VALUES (@team_slug, @repository, @source_type, @muted_until, @reason, @created_by)
+RETURNING id, team_slug, repository, muted_until, created_by, created_at, source_type, reason, suppressed, reported_at;
+
+-- name: ListActiveMutes :many
+SELECT id, team_slug, repository, muted_until, created_by, created_at, source_type, reason, suppressed, reported_at
+FROM mutes
+WHERE team_slug = @team_slug AND muted_until > now()
+ORDER BY muted_until;
+
+-- name: ListAllActiveMutes :many
+SELECT id, team_slug, repository, muted_until, created_by, created_at, source_type, reason, suppressed, reported_at
+FROM mutes
+WHERE muted_until > now()
+ORDER BY team_slug, muted_until;
+
+-- name: IncrementMuteSuppressed :exec
+UPDATE mutes SET suppressed = suppressed + 1 WHERE id = @id;
+
+-- name: EndMute :execrows
+UPDATE mutes SET muted_until = now()
+WHERE id = @id AND muted_until > now();
+
+-- name: EndMutes :exec
+UPDATE mutes SET muted_until = now()
+WHERE team_slug = @team_slug AND repository = @repository AND source_type = @source_type AND muted_until > now();
+
+-- name: ClaimEndedMutes :many
+UPDATE mutes SET reported_at = now()
+WHERE muted_until <= now() AND reported_at IS NULL
+RETURNING id, team_slug, repository, muted_until, created_by, created_at, source_type, reason, suppressed, reported_at;
@@ -0,0 +1,28 @@
+-- name: DeleteOrgSecurityAlert :exec
+DELETE FROM org_security_alerts
+WHERE org = @org AND alert_url = @alert_url;
+
+-- name: DeleteOrgSecurityAlerts :exec
+DELETE FROM org_security_alerts
+WHERE org = @org;
+
+-- name: GetOrgSecurityAlertSync :one
+SELECT synced_at FROM org_security_alert_syncs
+WHERE org = @org;
+
+-- name: ListOrgSecurityAlerts :many
+SELECT org, alert_url, alert_type, repository, repository_url, severity, description, created_at
+FROM org_security_alerts
+WHERE org = @org
+ORDER BY repository, created_at;
+
+-- name: UpsertOrgSecurityAlert :exec
+INSERT INTO org_security_alerts (org, alert_url, alert_type, repository, repository_url, severity, description, created_at)
+VALUES (@org, @alert_url, @alert_type, @repository, @repository_url, @severity, @description, @created_at)
+ON CONFLICT (org, alert_url) DO UPDATE
+SET severity = EXCLUDED.severity, description = EXCLUDED.description;
+
+-- name: UpsertOrgSecurityAlertSync :exec
+INSERT INTO org_security_alert_syncs (org, synced_at)
+VALUES (@org, @synced_at)
+ON CONFLICT (org) DO UPDATE SET synced_at = EXCLUDED.synced_at;
@@ -0,0 +1,30 @@
+-- name: ClaimPersonalNotification :execrows
+-- Github only redelivers recent deliveries, so older claims are cleaned up.
+WITH expired AS (
+  DELETE FROM personal_notifications_sent
+  WHERE sent_at < now() - interval '7 days'
+)
+INSERT INTO personal_notifications_sent (delivery_id, login)
+VALUES (@delivery_id, @login)
+ON CONFLICT DO NOTHING;
+
+-- name: GetPersonalNotifications :one
+SELECT login, slack_workspace, enabled, types, quiet_from, quiet_to, timezone, updated_at
+FROM personal_notifications
+WHERE login = @login;
+
+-- name: ReleasePersonalNotification :exec
+DELETE FROM personal_notifications_sent
+WHERE delivery_id = @delivery_id AND login = @login;
+
+-- name: UpsertPersonalNotifications :exec
+INSERT INTO personal_notifications (login, slack_workspace, enabled, types, quiet_from, quiet_to, timezone)
+VALUES (@login, @slack_workspace, @enabled, @types, @quiet_from, @quiet_to, @timezone)
+ON CONFLICT (login) DO UPDATE
+  SET slack_workspace = EXCLUDED.slack_workspace,
+      enabled         = EXCLUDED.enabled,
+      types           = EXCLUDED.types,
+      quiet_from      = EXCLUDED.quiet_from,
+      quiet_to        = EXCLUDED.quiet_to,
+      timezone        = EXCLUDED.timezone,
+      updated_at      = now();
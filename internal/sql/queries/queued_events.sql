@@ -0,0 +1,13 @@
+-- name: QueueEvent :exec
+INSERT INTO queued_events (team_slug, channel, source_type, event_type, event_id, payload)
+VALUES (@team_slug, @channel, @source_type, @event_type, @event_id, @payload);
+
+-- name: ListQueuedChannels :many
+SELECT DISTINCT team_slug, channel, source_type
+FROM queued_events
+ORDER BY team_slug, channel, source_type;
+
+-- name: ClaimQueuedEvents :many
+DELETE FROM queued_events
+WHERE team_slug = @team_slug AND channel = @channel
+RETURNING id, team_slug, channel, source_type, event_type, event_id, payload, queued_at;
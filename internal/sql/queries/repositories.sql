@@ -1,12 +1,15 @@
 -- name: CreateRepository :one
-INSERT INTO repositories (name) VALUES ($1)
-ON CONFLICT (name) DO NOTHING
+INSERT INTO repositories (org, name) VALUES ($1, $2)
+ON CONFLICT (org, name) DO NOTHING
 RETURNING id;
 
 -- name: GetRepository :one
-SELECT id, name FROM repositories WHERE name = $1;
+SELECT id, name, org FROM repositories WHERE org = $1 AND name = $2;
 
 -- name: UpdateRepository :exec
 UPDATE repositories
 SET name = @name
-WHERE name = @old_name;
+WHERE org = @org AND name = @old_name;
+
+-- name: AssignRepositoriesToOrg :exec
+UPDATE repositories SET org = $1 WHERE org = '';
@@ -0,0 +1,13 @@
+-- name: ClaimSecretAuthorNotification :one
+-- Returns no rows if the team has already claimed the alert, and whether it
+-- is the first team to claim it, as the subquery does not see the new row.
+INSERT INTO secret_author_notifications (alert_url, team_slug)
+VALUES (@alert_url, @team_slug)
+ON CONFLICT DO NOTHING
+RETURNING NOT EXISTS (
+  SELECT 1 FROM secret_author_notifications WHERE alert_url = @alert_url
+) AS first;
+
+-- name: ReleaseSecretAuthorNotification :exec
+DELETE FROM secret_author_notifications
+WHERE alert_url = @alert_url AND team_slug = @team_slug;
@@ -0,0 +1,18 @@
+-- name: CreateSecurityAlert :exec
+INSERT INTO security_alerts (team_slug, alert_url, alert_type, repository, severity, summary)
+VALUES (@team_slug, @alert_url, @alert_type, @repository, @severity, @summary)
+ON CONFLICT (team_slug, alert_url) DO NOTHING;
+
+-- name: DeleteSecurityAlert :exec
+DELETE FROM security_alerts
+WHERE team_slug = @team_slug AND alert_url = @alert_url;
+
+-- name: ListUnescalatedSecurityAlerts :many
+SELECT team_slug, alert_url, alert_type, repository, severity, summary, first_seen_at, escalated_at
+FROM security_alerts
+WHERE escalated_at IS NULL
+ORDER BY team_slug, first_seen_at;
+
+-- name: MarkSecurityAlertEscalated :execrows
+UPDATE security_alerts SET escalated_at = now()
+WHERE team_slug = @team_slug AND alert_url = @alert_url AND escalated_at IS NULL;
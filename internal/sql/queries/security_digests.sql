@@ -0,0 +1,32 @@
+-- name: CreateSecurityDigest :one
+INSERT INTO security_digests (team_slug, sent_at)
+VALUES (@team_slug, @sent_at)
+RETURNING id;
+
+-- name: CreateSecurityDigestAlert :exec
+INSERT INTO security_digest_alerts (digest_id, alert_url, critical)
+VALUES (@digest_id, @alert_url, @critical)
+ON CONFLICT DO NOTHING;
+
+-- name: CreateSecurityDigestCount :exec
+INSERT INTO security_digest_counts (digest_id, repository, alert_type, severity, count)
+VALUES (@digest_id, @repository, @alert_type, @severity, @count);
+
+-- name: GetLatestSecurityDigest :one
+SELECT id, team_slug, sent_at
+FROM security_digests
+WHERE team_slug = @team_slug
+ORDER BY sent_at DESC
+LIMIT 1;
+
+-- name: ListSecurityDigestAlerts :many
+SELECT digest_id, alert_url, critical
+FROM security_digest_alerts
+WHERE digest_id = @digest_id
+ORDER BY alert_url;
+
+-- name: ListSecurityDigestCounts :many
+SELECT digest_id, repository, alert_type, severity, count
+FROM security_digest_counts
+WHERE digest_id = @digest_id
+ORDER BY repository, alert_type, severity;
@@ -20,10 +20,24 @@ FROM slack_messages
 WHERE team_slug = $1 AND event_id = $2;
 
 -- name: CreateSlackID :exec
-INSERT INTO slack_ids (login, id) VALUES ($1, $2)
-ON CONFLICT (login) DO UPDATE SET id = EXCLUDED.id;
+INSERT INTO slack_ids (workspace, login, id) VALUES ($1, $2, $3)
+ON CONFLICT (workspace, login) DO UPDATE SET id = EXCLUDED.id;
 
 -- name: GetUserSlackID :one
 SELECT id
 FROM slack_ids
-WHERE login = $1;
+WHERE workspace = $1 AND login = $2;
+
+-- name: GetLoginBySlackID :one
+SELECT login
+FROM slack_ids
+WHERE workspace = $1 AND id = $2;
+
+-- name: ListSlackIDs :many
+SELECT login, id
+FROM slack_ids
+WHERE workspace = $1
+ORDER BY login;
+
+-- name: DeleteSlackID :exec
+DELETE FROM slack_ids WHERE workspace = $1 AND login = $2;
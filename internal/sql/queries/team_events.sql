@@ -0,0 +1,20 @@
+-- name: CreateTeamEvent :exec
+INSERT INTO team_events (team_slug, repository, event_type, title, summary, url, author)
+VALUES (@team_slug, @repository, @event_type, @title, @summary, @url, @author);
+
+-- name: ListTeamEvents :many
+SELECT id, team_slug, repository, event_type, title, summary, url, author, created_at
+FROM team_events
+WHERE team_slug = @team_slug
+ORDER BY created_at DESC, id DESC
+LIMIT @max_events;
+
+-- name: ListTeamRepositoryEvents :many
+SELECT id, team_slug, repository, event_type, title, summary, url, author, created_at
+FROM team_events
+WHERE team_slug = @team_slug AND repository = @repository
+ORDER BY created_at DESC, id DESC
+LIMIT @max_events;
+
+-- name: DeleteTeamEventsBefore :exec
+DELETE FROM team_events WHERE created_at < @created_at;
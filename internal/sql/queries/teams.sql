@@ -1,21 +1,32 @@
 -- name: CreateTeam :exec
-INSERT INTO teams (slug) VALUES ($1)
-ON CONFLICT (slug) DO NOTHING;
+INSERT INTO teams (slug, org, github_slug) VALUES ($1, $2, $3)
+ON CONFLICT (slug) DO UPDATE
+SET org = EXCLUDED.org,
+    github_slug = EXCLUDED.github_slug;
 
 -- name: ListTeams :many
 SELECT slug FROM teams ORDER BY slug;
 
+-- name: ListTeamsInOrg :many
+SELECT slug, github_slug FROM teams WHERE org = $1 ORDER BY slug;
+
 -- name: ListTeamsByRepository :many
 SELECT t.slug
 FROM teams t
 JOIN team_repositories tr ON t.slug = tr.team_slug
 JOIN repositories r ON tr.repository_id = r.id
-WHERE r.name = $1
+WHERE r.org = $1 AND r.name = $2
 ORDER BY t.slug;
 
 -- name: GetTeam :one
 SELECT slug FROM teams WHERE slug = $1;
 
+-- name: GetTeamByGithubSlug :one
+SELECT slug FROM teams WHERE org = $1 AND github_slug = $2;
+
+-- name: AssignTeamsToOrg :exec
+UPDATE teams SET org = $1 WHERE org = '';
+
 -- name: AddTeamMember :exec
 INSERT INTO team_members (team_slug, user_login) VALUES ($1, $2)
 ON CONFLICT (team_slug, user_login) DO NOTHING;
@@ -48,10 +59,10 @@ ON CONFLICT (team_slug, repository_id) DO NOTHING;
 
 -- name: RemoveTeamRepository :exec
 DELETE FROM team_repositories
-WHERE team_slug = $1 AND repository_id = (SELECT id FROM repositories WHERE name = $2);
+WHERE team_slug = $1 AND repository_id = (SELECT id FROM repositories WHERE org = $2 AND name = $3);
 
 -- name: ListTeamRepositories :many
-SELECT r.id, r.name
+SELECT r.id, r.name, r.org
 FROM team_repositories tr
 JOIN repositories r ON tr.repository_id = r.id
 WHERE tr.team_slug = $1
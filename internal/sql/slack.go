@@ -0,0 +1,76 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+// SyncSlackIDs makes the Slack IDs stored for the workspace match the given
+// Slack users, keyed by email, and returns the logins that were added and
+// removed. Every user is looked up before anything is changed, as a failed
+// lookup would otherwise remove the user's stored Slack ID.
+func SyncSlackIDs(ctx context.Context, db Database, workspace string, users map[string]string) (added, removed []string, err error) {
+	storedIDs, err := db.ListSlackIDs(ctx, workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slackIDs := make(map[string]string, len(users))
+	for _, email := range slices.Sorted(maps.Keys(users)) {
+		login, err := db.GetUserByEmail(ctx, email)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return nil, nil, fmt.Errorf("getting user by email %s: %w", email, err)
+		}
+
+		if login != "" {
+			slackIDs[login] = users[email]
+		}
+	}
+
+	stored := make(map[string]string, len(storedIDs))
+	for _, slackID := range storedIDs {
+		stored[slackID.Login] = slackID.ID
+	}
+
+	for _, login := range slices.Sorted(maps.Keys(slackIDs)) {
+		if stored[login] == slackIDs[login] {
+			continue
+		}
+
+		if err := db.CreateSlackID(ctx, gensql.CreateSlackIDParams{
+			Workspace: workspace,
+			Login:     login,
+			ID:        slackIDs[login],
+		}); err != nil {
+			return nil, nil, fmt.Errorf("saving Slack ID for %s: %w", login, err)
+		}
+
+		added = append(added, login)
+	}
+
+	for _, slackID := range storedIDs {
+		if _, ok := slackIDs[slackID.Login]; ok {
+			continue
+		}
+
+		if err := db.DeleteSlackID(ctx, gensql.DeleteSlackIDParams{
+			Workspace: workspace,
+			Login:     slackID.Login,
+		}); err != nil {
+			return nil, nil, fmt.Errorf("deleting Slack ID for %s: %w", slackID.Login, err)
+		}
+
+		removed = append(removed, slackID.Login)
+	}
+
+	return added, removed, nil
+}
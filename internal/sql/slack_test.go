@@ -0,0 +1,80 @@
+package sql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/navikt/ghep/internal/mock"
+	"github.com/navikt/ghep/internal/sql"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+func TestSyncSlackIDs(t *testing.T) {
+	slackID := func(workspace, login, id string) gensql.CreateSlackIDParams {
+		return gensql.CreateSlackIDParams{Workspace: workspace, Login: login, ID: id}
+	}
+
+	db := &mock.Database{
+		SlackIDs: []gensql.CreateSlackIDParams{
+			slackID("", "Kyrremann", "U1"),
+			slackID("", "androa", "U2"),
+			slackID("", "frodesundby", "U3"),
+			slackID("other", "rbjornstad", "U4"),
+		},
+	}
+
+	users := map[string]string{
+		"kyrre.havik@nav.no":             "U1",
+		"andre.roaldseth@nav.no":         "U5",
+		"thomas.siegfried.krampl@nav.no": "U6",
+		"unknown@nav.no":                 "U7",
+	}
+
+	added, removed, err := sql.SyncSlackIDs(context.TODO(), db, "", users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"androa", "thokra-nav"}, added); diff != "" {
+		t.Errorf("added mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"frodesundby"}, removed); diff != "" {
+		t.Errorf("removed mismatch (-want +got):\n%s", diff)
+	}
+	// Slack IDs in other workspaces are left alone
+	want := []gensql.CreateSlackIDParams{
+		slackID("", "Kyrremann", "U1"),
+		slackID("other", "rbjornstad", "U4"),
+		slackID("", "androa", "U5"),
+		slackID("", "thokra-nav", "U6"),
+	}
+	if diff := cmp.Diff(want, db.SlackIDs); diff != "" {
+		t.Errorf("Slack IDs mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSyncSlackIDsFailingLookup(t *testing.T) {
+	stored := []gensql.CreateSlackIDParams{
+		{Login: "Kyrremann", ID: "U1"},
+		{Login: "androa", ID: "U2"},
+	}
+	db := &mock.Database{
+		FailingEmails: []string{"kyrre.havik@nav.no"},
+		SlackIDs:      append([]gensql.CreateSlackIDParams{}, stored...),
+	}
+
+	users := map[string]string{
+		"kyrre.havik@nav.no":             "U1",
+		"thomas.siegfried.krampl@nav.no": "U6",
+	}
+
+	if _, _, err := sql.SyncSlackIDs(context.TODO(), db, "", users); err == nil {
+		t.Fatal("expected the sync to fail")
+	}
+
+	// Nothing is changed, as the user that failed would have lost their Slack ID
+	if diff := cmp.Diff(stored, db.SlackIDs); diff != "" {
+		t.Errorf("Slack IDs changed (-want +got):\n%s", diff)
+	}
+}
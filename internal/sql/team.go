@@ -10,17 +10,18 @@ import (
 	"github.com/navikt/ghep/internal/sql/gensql"
 )
 
-// AddRepositoryToTeam adds a repository to a team, creating the repository if it does not exist.
-func AddRepositoryToTeam(ctx context.Context, db Database, team, repositoryName string) error {
-	repository, err := db.GetRepository(ctx, repositoryName)
+// AddRepositoryToTeam adds a repository in the organization to a team,
+// creating the repository if it does not exist.
+func AddRepositoryToTeam(ctx context.Context, db Database, team, org, repositoryName string) error {
+	repository, err := db.GetRepository(ctx, gensql.GetRepositoryParams{Org: org, Name: repositoryName})
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			return err
 		}
 
-		result, err := db.CreateRepository(ctx, repositoryName)
+		result, err := db.CreateRepository(ctx, gensql.CreateRepositoryParams{Org: org, Name: repositoryName})
 		if err != nil {
-			return fmt.Errorf("failed to create repository %s: %w", repositoryName, err)
+			return fmt.Errorf("failed to create repository %s/%s: %w", org, repositoryName, err)
 		}
 
 		repository.ID = result
@@ -51,23 +52,87 @@ func AddMemberToTeam(ctx context.Context, db Database, team, userLogin string) e
 	})
 }
 
-func RemoveRepositoriesNotBelongingToTeam(ctx context.Context, db *gensql.Queries, team string, repositories []string) error {
+// SyncTeamRepositories makes the repositories of a team in the database match
+// the given list of repositories in the organization, and returns the
+// repositories that were added and removed.
+func SyncTeamRepositories(ctx context.Context, db Database, team, org string, repositories []string) (added, removed []string, err error) {
 	currentRepositories, err := db.ListTeamRepositories(ctx, team)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
+	var current []string
 	for _, repository := range currentRepositories {
-		exists := slices.Contains(repositories, repository.Name)
-		if !exists {
-			if err := db.RemoveTeamRepository(ctx, gensql.RemoveTeamRepositoryParams{
-				TeamSlug: team,
-				Name:     repository.Name,
-			}); err != nil {
-				return err
-			}
+		if repository.Org == org {
+			current = append(current, repository.Name)
+		}
+	}
+
+	for _, repository := range repositories {
+		if slices.Contains(current, repository) {
+			continue
+		}
+
+		if err := AddRepositoryToTeam(ctx, db, team, org, repository); err != nil {
+			return nil, nil, fmt.Errorf("adding repository %s: %w", repository, err)
+		}
+
+		added = append(added, repository)
+	}
+
+	for _, repository := range current {
+		if slices.Contains(repositories, repository) {
+			continue
+		}
+
+		if err := db.RemoveTeamRepository(ctx, gensql.RemoveTeamRepositoryParams{
+			TeamSlug: team,
+			Org:      org,
+			Name:     repository,
+		}); err != nil {
+			return nil, nil, fmt.Errorf("removing repository %s: %w", repository, err)
+		}
+
+		removed = append(removed, repository)
+	}
+
+	return added, removed, nil
+}
+
+// SyncTeamMembers makes the members of a team in the database match the given
+// list, and returns the members that were added and removed.
+func SyncTeamMembers(ctx context.Context, db Database, team string, members []string) (added, removed []string, err error) {
+	current, err := db.ListTeamMembers(ctx, team)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, member := range members {
+		if slices.Contains(current, member) {
+			continue
+		}
+
+		if err := AddMemberToTeam(ctx, db, team, member); err != nil {
+			return nil, nil, fmt.Errorf("adding member %s: %w", member, err)
 		}
+
+		added = append(added, member)
+	}
+
+	for _, member := range current {
+		if slices.Contains(members, member) {
+			continue
+		}
+
+		if err := db.RemoveTeamMember(ctx, gensql.RemoveTeamMemberParams{
+			TeamSlug:  team,
+			UserLogin: member,
+		}); err != nil {
+			return nil, nil, fmt.Errorf("removing member %s: %w", member, err)
+		}
+
+		removed = append(removed, member)
 	}
 
-	return nil
+	return added, removed, nil
 }
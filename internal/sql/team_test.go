@@ -0,0 +1,89 @@
+package sql_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/navikt/ghep/internal/mock"
+	"github.com/navikt/ghep/internal/sql"
+	"github.com/navikt/ghep/internal/sql/gensql"
+)
+
+func TestSyncTeamRepositories(t *testing.T) {
+	repository := func(org, name string) gensql.CreateRepositoryParams {
+		return gensql.CreateRepositoryParams{Org: org, Name: name}
+	}
+
+	db := &mock.Database{
+		Repositories: []gensql.CreateRepositoryParams{repository("navikt", "ghep"), repository("navikt", "oldie"), repository("other-org", "ghep")},
+		TeamRepositories: map[string][]gensql.CreateRepositoryParams{
+			"nada":  {repository("navikt", "ghep"), repository("navikt", "oldie"), repository("other-org", "ghep")},
+			"other": {repository("navikt", "oldie")},
+		},
+	}
+
+	added, removed, err := sql.SyncTeamRepositories(context.TODO(), db, "nada", "navikt", []string{"ghep", "newbie"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"newbie"}, added); diff != "" {
+		t.Errorf("added mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"oldie"}, removed); diff != "" {
+		t.Errorf("removed mismatch (-want +got):\n%s", diff)
+	}
+	// Repositories in other organizations are left alone
+	want := []gensql.CreateRepositoryParams{repository("navikt", "ghep"), repository("other-org", "ghep"), repository("navikt", "newbie")}
+	if diff := cmp.Diff(want, db.TeamRepositories["nada"]); diff != "" {
+		t.Errorf("team repositories mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]gensql.CreateRepositoryParams{repository("navikt", "oldie")}, db.TeamRepositories["other"]); diff != "" {
+		t.Errorf("other team's repositories changed (-want +got):\n%s", diff)
+	}
+
+	// The new repository is created once, and reused by other teams
+	if _, _, err := sql.SyncTeamRepositories(context.TODO(), db, "other", "navikt", []string{"oldie", "newbie"}); err != nil {
+		t.Fatal(err)
+	}
+	want = []gensql.CreateRepositoryParams{repository("navikt", "ghep"), repository("navikt", "oldie"), repository("other-org", "ghep"), repository("navikt", "newbie")}
+	if diff := cmp.Diff(want, db.Repositories); diff != "" {
+		t.Errorf("repositories mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSyncTeamMembers(t *testing.T) {
+	db := &mock.Database{
+		Users:       []string{"Kyrremann", "androa"},
+		TeamMembers: map[string][]string{"nada": {"Kyrremann", "androa"}},
+	}
+
+	added, removed, err := sql.SyncTeamMembers(context.TODO(), db, "nada", []string{"Kyrremann", "thokra-nav"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"thokra-nav"}, added); diff != "" {
+		t.Errorf("added mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"androa"}, removed); diff != "" {
+		t.Errorf("removed mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"Kyrremann", "thokra-nav"}, db.TeamMembers["nada"]); diff != "" {
+		t.Errorf("team members mismatch (-want +got):\n%s", diff)
+	}
+	if !slices.Contains(db.Users, "thokra-nav") {
+		t.Error("expected the new member to be created as a user")
+	}
+
+	// Syncing again changes nothing
+	added, removed, err = sql.SyncTeamMembers(context.TODO(), db, "nada", []string{"Kyrremann", "thokra-nav"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("expected no changes, got added %v and removed %v", added, removed)
+	}
+}
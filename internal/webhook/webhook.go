@@ -0,0 +1,330 @@
+// Package webhook sends team-routed events to outgoing webhooks as signed
+// CloudEvents, so internal tools can use them without subscribing to Github.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/navikt/ghep/internal/github"
+)
+
+const (
+	// SignatureHeader holds the HMAC-SHA256 of the timestamp and the body, in
+	// the same format as Github's X-Hub-Signature-256.
+	SignatureHeader = "X-Ghep-Signature-256"
+	// TimestampHeader holds when the request was signed, in Unix seconds.
+	TimestampHeader = "X-Ghep-Timestamp"
+
+	contentTypeCloudEvents = "application/cloudevents+json"
+	maxAttempts            = 4
+)
+
+// Sender sends events to the outgoing webhook configured on a source.
+type Sender interface {
+	Send(log *slog.Logger, team github.Team, source github.Source, event github.Event) error
+}
+
+type sink struct {
+	url    string
+	secret string
+}
+
+type Client struct {
+	log        *slog.Logger
+	httpClient *http.Client
+	sinks      map[string]sink
+	// retryWait is the wait before the first retry, and is doubled for each attempt.
+	retryWait time.Duration
+	// deliveries tracks events delivered in the background, and ctx is
+	// cancelled when Shutdown gives up on them.
+	deliveries sync.WaitGroup
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+// New reads the URL and signing secret of every webhook used by a source,
+// from WEBHOOK_SINK_<NAME>_URL and WEBHOOK_SINK_<NAME>_SECRET, or the files in
+// the same variables suffixed with _FILE.
+func New(log *slog.Logger, teams map[string]github.Team) (*Client, error) {
+	client := &Client{
+		log: log,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		sinks:     map[string]sink{},
+		retryWait: time.Second,
+	}
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+
+	for name, team := range teams {
+		for _, source := range team.Sources {
+			if _, ok := client.sinks[source.Webhook]; ok || source.Webhook == "" {
+				continue
+			}
+
+			prefix := EnvPrefix(source.Webhook)
+			url, err := fromEnv(prefix + "_URL")
+			if err != nil {
+				return nil, fmt.Errorf("team %s: reading URL for webhook %s: %w", name, source.Webhook, err)
+			}
+			secret, err := fromEnv(prefix + "_SECRET")
+			if err != nil {
+				return nil, fmt.Errorf("team %s: reading secret for webhook %s: %w", name, source.Webhook, err)
+			}
+
+			if url == "" || secret == "" {
+				return nil, fmt.Errorf("team %s: webhook %s needs both %s_URL and %s_SECRET", name, source.Webhook, prefix, prefix)
+			}
+
+			client.sinks[source.Webhook] = sink{url: url, secret: secret}
+		}
+	}
+
+	return client, nil
+}
+
+// EnvPrefix returns the prefix of the environment variables configuring a webhook.
+func EnvPrefix(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+
+	return "WEBHOOK_SINK_" + strings.ToUpper(name)
+}
+
+func fromEnv(key string) (string, error) {
+	if value := os.Getenv(key); value != "" {
+		return value, nil
+	}
+
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+
+	value, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(value)), nil
+}
+
+// CloudEvent is a CloudEvents 1.0 envelope in structured JSON mode.
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            Data      `json:"data"`
+}
+
+type Data struct {
+	Team       string          `json:"team"`
+	SourceType string          `json:"sourceType"`
+	EventType  string          `json:"eventType"`
+	Action     string          `json:"action,omitempty"`
+	Repository string          `json:"repository,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// NewCloudEvent wraps the event in an envelope, with a type like
+// ghep.pull_request.opened and the team as source. Secrets are redacted from
+// the payload, as they are from messages. The id is derived from the Github
+// delivery, the team and the source, so a redelivery from Github gets the
+// same id and receivers can deduplicate on it.
+func NewCloudEvent(team github.Team, source github.Source, event github.Event) (CloudEvent, error) {
+	payload := event.Raw
+	if payload == nil {
+		var err error
+		if payload, err = json.Marshal(event); err != nil {
+			return CloudEvent{}, err
+		}
+	}
+
+	payload, err := team.Config.Redaction.RedactJSON(payload)
+	if err != nil {
+		return CloudEvent{}, fmt.Errorf("redacting payload: %w", err)
+	}
+
+	id, err := eventID(team, source, event)
+	if err != nil {
+		return CloudEvent{}, err
+	}
+
+	eventType := event.GetEventType().Name()
+	ceType := "ghep." + eventType
+	if event.Action != "" {
+		ceType += "." + event.Action
+	}
+
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              id,
+		Source:          "/teams/" + team.Name,
+		Type:            ceType,
+		Subject:         event.GetRepositoryName(),
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data: Data{
+			Team:       team.Name,
+			SourceType: source.SourceType,
+			EventType:  eventType,
+			Action:     event.Action,
+			Repository: event.GetRepositoryName(),
+			Payload:    payload,
+		},
+	}, nil
+}
+
+func eventID(team github.Team, source github.Source, event github.Event) (string, error) {
+	// Events not from a webhook request, like in tests, have no delivery
+	if event.DeliveryID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(id), nil
+	}
+
+	sum := sha256.Sum256([]byte(event.DeliveryID + "/" + team.Name + "/" + source.SourceType))
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// Sign returns the signature of the timestamp and the body, as
+// sha256=<hex encoded HMAC of "<timestamp>.<body>">.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send delivers the event to the source's webhook in the background, so slow
+// receivers and retries do not hold up the response to Github. Call Shutdown
+// before exiting, so pending deliveries are not lost.
+func (c *Client) Send(log *slog.Logger, team github.Team, source github.Source, event github.Event) error {
+	s, ok := c.sinks[source.Webhook]
+	if !ok {
+		return fmt.Errorf("no webhook named %s", source.Webhook)
+	}
+
+	cloudEvent, err := NewCloudEvent(team, source, event)
+	if err != nil {
+		return fmt.Errorf("creating cloud event: %w", err)
+	}
+
+	body, err := json.Marshal(cloudEvent)
+	if err != nil {
+		return fmt.Errorf("encoding cloud event: %w", err)
+	}
+
+	log = log.With("webhook", source.Webhook, "cloud_event_id", cloudEvent.ID)
+	c.deliveries.Go(func() {
+		if err := c.deliver(c.ctx, log, s, body); err != nil {
+			log.Error("Delivering event to webhook", "error", err)
+		}
+	})
+
+	return nil
+}
+
+// Shutdown waits for pending deliveries to finish. When ctx is done before
+// they have, their requests and retries are cancelled, and ctx's error is returned.
+func (c *Client) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		c.deliveries.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		c.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// deliver posts the body, and retries with exponential backoff on network
+// errors, 429 and 5xx responses.
+func (c *Client) deliver(ctx context.Context, log *slog.Logger, s sink, body []byte) error {
+	wait := c.retryWait
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var retry bool
+		retry, err = c.post(ctx, s, body)
+		if err == nil {
+			return nil
+		}
+
+		if !retry || attempt == maxAttempts {
+			break
+		}
+
+		log.Info("Delivering event to webhook failed, retrying", "error", err, "attempt", attempt, "wait", wait.String())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+
+	return err
+}
+
+// post signs the body with the current time, so retries are not rejected by
+// receivers checking the timestamp.
+func (c *Client) post(ctx context.Context, s sink, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", contentTypeCloudEvents)
+	req.Header.Set("User-Agent", "ghep")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(s.secret, timestamp, body))
+
+	resp, err := c.httpClient.Do(req) // #nosec G704 -- URL is a webhook configured by the operator
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("non 2xx status code(%v): %s", resp.StatusCode, respBody)
+	}
+
+	return false, nil
+}
+
+var _ Sender = (*Client)(nil)
@@ -4,16 +4,24 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 
+	"github.com/navikt/ghep/internal/email"
+	"github.com/navikt/ghep/internal/events"
 	"github.com/navikt/ghep/internal/ghep"
 	"github.com/navikt/ghep/internal/github"
+	"github.com/navikt/ghep/internal/msteams"
 	"github.com/navikt/ghep/internal/slack"
 	"github.com/navikt/ghep/internal/sql"
+	"github.com/navikt/ghep/internal/webhook"
 )
 
 func main() {
-	ctx := context.Background()
+	// Stopping Ghep shuts down the API server and the schedulers
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 	log := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 
 	db, err := sql.New(ctx, log.With("client", "db"), true)
@@ -23,38 +31,87 @@ func main() {
 	}
 
 	log.Info("Parsing team configuration")
-	teamConfig, personalDigestUsers, err := github.ParseTeamConfig(os.Getenv("REPOS_CONFIG_FILE_PATH"))
+	teamConfig, personalDigestUsers, personalNotifications, err := github.ParseTeamConfig(os.Getenv("REPOS_CONFIG_FILE_PATH"))
 	if err != nil {
 		log.Error("Parsing team config", "error", err)
 		os.Exit(1)
 	}
 
-	githubClient := github.New(
+	for name, team := range teamConfig {
+		if !team.Config.SilenceDependabot.IsKnown() {
+			log.Warn("Ignoring unknown config.silenceDependabot, use always or rollup", "team", name, "value", team.Config.SilenceDependabot)
+		}
+	}
+
+	githubClients, err := github.NewClients(
+		log.With("client", "github"),
 		db,
-		os.Getenv("GITHUB_APP_INSTALLATION_ID"),
+		github.NewURLs(
+			os.Getenv("GITHUB_API_URL"),
+			os.Getenv("GITHUB_GRAPHQL_URL"),
+			os.Getenv("GITHUB_WEB_URL"),
+		),
 		os.Getenv("GITHUB_APP_ID"),
 		os.Getenv("GITHUB_APP_PRIVATE_KEY"),
 		os.Getenv("GITHUB_ORG"),
+		os.Getenv("GITHUB_APP_INSTALLATION_ID"),
+		os.Getenv("GITHUB_ADDITIONAL_ORGS"),
 	)
+	if err != nil {
+		log.Error("Creating Github clients", "error", err)
+		os.Exit(1)
+	}
 
-	log.Info("Creating Slack client")
-	slackClient, err := slack.New(
+	if err := githubClients.ValidateTeams(teamConfig); err != nil {
+		log.Error("Validating team configuration", "error", err)
+		os.Exit(1)
+	}
+
+	log.Info("Creating Slack clients")
+	slackWorkspaces, err := slack.NewWorkspaces(
 		log.With("client", "slack"),
-		os.Getenv("SLACK_TOKEN"),
+		teamConfig,
+		personalDigestUsers,
+		personalNotifications,
 	)
 	if err != nil {
-		log.Error("Creating Slack client", "error", err)
+		log.Error("Creating Slack clients", "error", err)
+		os.Exit(1)
+	}
+
+	log.Info("Creating Microsoft Teams client")
+	msteamsClient, err := msteams.New(log.With("client", "msteams"), teamConfig)
+	if err != nil {
+		log.Error("Creating Microsoft Teams client", "error", err)
+		os.Exit(1)
+	}
+
+	notifiers := slack.NewNotifiers(slackWorkspaces.Slackers(), slackWorkspaces.Interactive(), msteamsClient)
+
+	emailClient, err := email.New(log.With("client", "email"), teamConfig, personalDigestUsers)
+	if err != nil {
+		log.Error("Creating email client", "error", err)
+		os.Exit(1)
+	}
+
+	webhooks, err := webhook.New(log.With("client", "webhook"), teamConfig)
+	if err != nil {
+		log.Error("Creating webhook client", "error", err)
 		os.Exit(1)
 	}
 
 	subscribeToOrg, _ := strconv.ParseBool(os.Getenv("GHEP_SUBSCRIBE_TO_ORG"))
 
-	go ghep.FetchGithubData(ctx, log.With("component", "fetch-teams"), db, teamConfig, githubClient, subscribeToOrg)
-	go ghep.FetchSlackUsers(ctx, log.With("component", "fetch-slack"), db)
-	go ghep.RunLeaderSchedulers(ctx, log.With("component", "schedulers"), db, teamConfig, githubClient, slackClient, personalDigestUsers)
+	resyncer := ghep.NewResyncer(log.With("component", "resync"), db, teamConfig, githubClients, slackWorkspaces, subscribeToOrg)
+
+	digests := ghep.NewDigestSender(log.With("component", "digest"), teamConfig, githubClients, notifiers, emailClient)
+
+	personal := events.NewPersonalNotifier(db, slackWorkspaces.DirectMessengers(), personalNotifications)
+
+	go ghep.RunLeaderSchedulers(ctx, log.With("component", "schedulers"), db, teamConfig, githubClients, slackWorkspaces, notifiers, emailClient, personalDigestUsers, resyncer)
 
 	glog := log.With("component", "ghep")
-	if err := ghep.Run(ctx, glog, db, teamConfig, githubClient, slackClient, subscribeToOrg); err != nil {
+	if err := ghep.Run(ctx, glog, db, teamConfig, githubClients, slackWorkspaces, notifiers, webhooks, subscribeToOrg, resyncer, digests, personal); err != nil {
 		glog.Error("Running Ghep", "error", err)
 		os.Exit(1)
 	}